@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/valkey"
+)
+
+const adminUsage = `usage: mcp-ruleset-server admin <command>
+
+commands:
+  rebuild-indexes  re-record the search indexes of every stored ruleset
+
+The read cache lives inside each server process; flush it with the flush_cache tool.`
+
+// runAdmin implements the "admin" subcommand and returns the process exit code
+func runAdmin(cfg *config.Config, args []string) int {
+	if len(args) != 1 || args[0] != "rebuild-indexes" {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	}
+
+	if err := adminRebuildIndexes(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "admin %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// adminRebuildIndexes rebuilds the ruleset indexes, printing progress to stderr
+func adminRebuildIndexes(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	client, err := valkey.NewClient(cfg.ValkeyHost, cfg.ValkeyPort, valkeyOptions(cfg)...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Valkey: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	service := ruleset.NewService(client, serviceOptions(cfg)...)
+	count, err := service.RebuildIndexesWithProgress(func(done, total int) {
+		fmt.Fprintf(os.Stderr, "\rIndexed %d/%d", done, total)
+		if done == total {
+			fmt.Fprintln(os.Stderr)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rebuilt indexes for %d ruleset(s)\n", count)
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/metrics"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/rs/zerolog/log"
+)
+
+// startCorpusMetrics records the stored corpus in m now and every interval in
+// the background until stop is called
+func startCorpusMetrics(service ruleset.ServiceInterface, m *metrics.Metrics, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	record := func() {
+		rulesets, err := service.List()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to record stored ruleset sizes")
+			return
+		}
+		m.RecordCorpus(rulesets)
+	}
+
+	go func() {
+		defer close(finished)
+		record()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				record()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
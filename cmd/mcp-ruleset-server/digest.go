@@ -0,0 +1,58 @@
+package main
+
+import (
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/digest"
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/rs/zerolog/log"
+)
+
+// startDigests collects the changes published by broker in the background and
+// sends a digest of them every interval, and of any remaining ones when stop is
+// called. A digest that cannot be sent is retried, with later changes merged
+// in, at the next interval. Digests are not gated on leadership: broker only
+// carries this replica's changes, which no other replica would report.
+func startDigests(broker *events.Broker, sender digest.Sender, namespace string, interval time.Duration) (stop func()) {
+	changes, unsubscribe := broker.Subscribe()
+	collector := digest.NewCollector(namespace, time.Now())
+	finished := make(chan struct{})
+
+	send := func() {
+		now := time.Now()
+		d := collector.Digest(now)
+		if d == nil {
+			collector.Reset(now)
+			return
+		}
+		if err := sender.Send(d); err != nil {
+			log.Warn().Err(err).Int("mutations", d.Mutations).Msg("Failed to send change digest")
+			return
+		}
+		collector.Reset(now)
+		log.Info().Int("mutations", d.Mutations).Msg("Change digest sent")
+	}
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case e, ok := <-changes:
+				if !ok {
+					send()
+					return
+				}
+				collector.Add(e)
+			case <-ticker.C:
+				send()
+			}
+		}
+	}()
+
+	return func() {
+		unsubscribe()
+		<-finished
+	}
+}
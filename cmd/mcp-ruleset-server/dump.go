@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/dump"
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/jbrinkman/archivyr/internal/valkey"
+	"github.com/rs/zerolog/log"
+)
+
+// runDump implements the "dump" subcommand, which writes a logical dump of the
+// corpus, or its JSON Schema with -schema. It returns the process exit code.
+func runDump(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	out := fs.String("out", "", "file to write the dump to (default stdout)")
+	schema := fs.Bool("schema", false, "print the JSON Schema of the dump format instead")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *schema {
+		_, _ = os.Stdout.Write(dump.Schema)
+		return 0
+	}
+
+	client, ok := connectForCLI(cfg)
+	if !ok {
+		return 1
+	}
+	defer func() { _ = client.Close() }()
+
+	d, err := dump.Export(
+		ruleset.NewService(client, serviceOptions(cfg)...),
+		prompt.NewService(client),
+		snippet.NewService(client),
+		time.Now(),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Dump failed")
+		return 1
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create dump file")
+			return 1
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := dump.Encode(w, d); err != nil {
+		log.Error().Err(err).Msg("Dump failed")
+		return 1
+	}
+
+	log.Info().Int("rulesets", len(d.Rulesets)).Int("templates", len(d.Templates)).Int("snippets", len(d.Snippets)).Msg("Dump written")
+	return 0
+}
+
+// runLoad implements the "load" subcommand, which writes every entity of a
+// dump file to the connected Valkey instance. It returns the process exit code.
+func runLoad(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: mcp-ruleset-server load <dump file>")
+		return 2
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open dump file")
+		return 1
+	}
+	defer func() { _ = f.Close() }()
+
+	d, err := dump.Decode(f)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid dump")
+		return 1
+	}
+
+	client, ok := connectForCLI(cfg)
+	if !ok {
+		return 1
+	}
+	defer func() { _ = client.Close() }()
+
+	result, err := dump.Load(d,
+		ruleset.NewService(client, serviceOptions(cfg)...),
+		prompt.NewService(client),
+		snippet.NewService(client),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Load failed")
+		return 1
+	}
+
+	fmt.Printf("Loaded %d ruleset(s), %d prompt template(s), %d snippet(s)\n",
+		result.Rulesets, result.Templates, result.Snippets)
+	return 0
+}
+
+// runExportDiff implements the "export-diff" subcommand, which reports the
+// rulesets added, removed and changed between two dump files. It needs no
+// Valkey connection and returns the process exit code.
+func runExportDiff(args []string) int {
+	fs := flag.NewFlagSet("export-diff", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "write the comparison as JSON instead of text")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: mcp-ruleset-server export-diff [-json] <old dump file> <new dump file>")
+		return 2
+	}
+
+	dumps := make([]*dump.Dump, 0, 2)
+	for _, path := range fs.Args() {
+		d, err := readDumpFile(path)
+		if err != nil {
+			log.Error().Err(err).Str("file", path).Msg("Invalid dump")
+			return 1
+		}
+		dumps = append(dumps, d)
+	}
+
+	comparison := dump.Compare(dumps[0], dumps[1])
+	var err error
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(comparison)
+	} else {
+		err = comparison.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write comparison")
+		return 1
+	}
+	return 0
+}
+
+// readDumpFile decodes the dump file at path
+func readDumpFile(path string) (*dump.Dump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dump file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return dump.Decode(f)
+}
+
+// connectForCLI validates the configuration and connects to Valkey, logging any failure
+func connectForCLI(cfg *config.Config) (*valkey.Client, bool) {
+	if err := cfg.Validate(); err != nil {
+		log.Error().Err(err).Msg("Invalid configuration")
+		return nil, false
+	}
+
+	client, err := valkey.NewClient(cfg.ValkeyHost, cfg.ValkeyPort, valkeyOptions(cfg)...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to connect to Valkey")
+		return nil, false
+	}
+	return client, true
+}
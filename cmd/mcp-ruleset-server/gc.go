@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/valkey"
+	"github.com/rs/zerolog/log"
+)
+
+// runGC implements the "gc" subcommand, which collects garbage once and
+// prints what was reclaimed. It returns the process exit code.
+func runGC(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("gc", flag.ContinueOnError)
+	minAge := fs.Duration("min-age", blobRetention(cfg), "keep unreferenced blobs stored more recently than this (default BLOB_RETENTION_MINUTES)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Error().Err(err).Msg("Invalid configuration")
+		return 1
+	}
+
+	client, err := valkey.NewClient(cfg.ValkeyHost, cfg.ValkeyPort, valkeyOptions(cfg)...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to connect to Valkey")
+		return 1
+	}
+	defer func() { _ = client.Close() }()
+
+	report, err := ruleset.NewService(client, serviceOptions(cfg)...).CollectGarbage(*minAge)
+	if err != nil {
+		log.Error().Err(err).Msg("Garbage collection failed")
+		return 1
+	}
+
+	fmt.Printf("Scanned %d blob(s), removed %d, reclaimed %d bytes\n",
+		report.BlobsScanned, report.BlobsRemoved, report.BytesReclaimed)
+	return 0
+}
+
+// blobRetention returns the configured retention of unreferenced blobs
+func blobRetention(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.BlobRetentionMinutes) * time.Minute
+}
+
+// startGC collects garbage every interval in the background until stop is called.
+// Unreferenced blobs are kept for the retention period. Intervals where
+// isLeader reports another replica leads are skipped.
+func startGC(service *ruleset.Service, interval, retention time.Duration, isLeader func() bool) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if !isLeader() {
+					log.Debug().Msg("Skipping garbage collection; another replica leads scheduled jobs")
+					continue
+				}
+				report, err := service.CollectGarbage(retention)
+				if err != nil {
+					log.Warn().Err(err).Msg("Scheduled garbage collection failed")
+					continue
+				}
+				log.Info().
+					Int("blobs_scanned", report.BlobsScanned).
+					Int("blobs_removed", report.BlobsRemoved).
+					Int64("bytes_reclaimed", report.BytesReclaimed).
+					Msg("Garbage collection finished")
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -2,14 +2,34 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/digest"
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/httpapi"
+	"github.com/jbrinkman/archivyr/internal/leader"
+	"github.com/jbrinkman/archivyr/internal/marker"
 	"github.com/jbrinkman/archivyr/internal/mcp"
+	"github.com/jbrinkman/archivyr/internal/metrics"
+	"github.com/jbrinkman/archivyr/internal/pack"
+	"github.com/jbrinkman/archivyr/internal/prompt"
 	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/search"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/jbrinkman/archivyr/internal/source"
+	"github.com/jbrinkman/archivyr/internal/util"
 	"github.com/jbrinkman/archivyr/internal/valkey"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -21,11 +41,48 @@ func main() {
 	// Initialize zerolog logger with configured log level
 	setupLogger(cfg.LogLevel)
 
+	// Pack management runs as a one-shot command instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "pack" {
+		os.Exit(runPack(cfg, os.Args[2:]))
+	}
+
+	// Garbage collection can also run once on demand
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		os.Exit(runGC(cfg, os.Args[2:]))
+	}
+
+	// Maintenance commands for operators
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(runAdmin(cfg, os.Args[2:]))
+	}
+
+	// Logical dumps are written and loaded as one-shot commands
+	if len(os.Args) > 1 && os.Args[1] == "dump" {
+		os.Exit(runDump(cfg, os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "load" {
+		os.Exit(runLoad(cfg, os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export-diff" {
+		os.Exit(runExportDiff(os.Args[2:]))
+	}
+
+	// Spreadsheet summary of the corpus
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		os.Exit(runReport(cfg, os.Args[2:]))
+	}
+
+	// Smoke test of a live deployment
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		os.Exit(runSelftest(cfg, os.Args[2:]))
+	}
+
 	log.Info().Msg("Starting MCP Ruleset Server")
 	log.Info().
 		Str("valkey_host", cfg.ValkeyHost).
 		Str("valkey_port", cfg.ValkeyPort).
 		Str("log_level", cfg.LogLevel).
+		Str("parse_mode", cfg.ParseMode).
 		Msg("Configuration loaded")
 
 	// Validate configuration
@@ -33,10 +90,19 @@ func main() {
 		log.Fatal().Err(err).Msg("Invalid configuration")
 	}
 
+	// Serve a local snapshot instead of Valkey when asked to
+	if slices.Contains(os.Args[1:], "--offline") {
+		os.Exit(runOffline(cfg))
+	}
+
 	// Create Valkey client and test connection
 	log.Info().Msg("Connecting to Valkey")
-	valkeyClient, err := valkey.NewClient(cfg.ValkeyHost, cfg.ValkeyPort)
+	valkeyClient, err := valkey.NewClient(cfg.ValkeyHost, cfg.ValkeyPort, valkeyOptions(cfg)...)
 	if err != nil {
+		if cfg.DegradedMode {
+			log.Warn().Err(err).Msg("Failed to connect to Valkey; falling back to the local snapshot")
+			os.Exit(runOffline(cfg))
+		}
 		log.Fatal().Err(err).Msg("Failed to connect to Valkey")
 	}
 	defer func() {
@@ -56,14 +122,170 @@ func main() {
 	}
 	log.Info().Msg("Valkey connection successful")
 
-	// Create ruleset service with Valkey client
-	rulesetService := ruleset.NewService(valkeyClient)
+	// Record metrics on the configured backend
+	serverMetrics, stopMetrics, err := newMetrics(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up metrics")
+	}
+	defer stopMetrics()
+
+	// Create ruleset service with Valkey client, broadcasting changes to the HTTP
+	// event stream and recording the size of every content write
+	changes := events.NewBroker()
+	rulesetOptions := append(serviceOptions(cfg), ruleset.WithHooks(ruleset.CombineHooks(changes.Hooks(), serverMetrics.Hooks())))
+	if cfg.BackgroundReindex {
+		// Only the long-running server, since one-shot commands would exit before the rebuild completes
+		rulesetOptions = append(rulesetOptions, ruleset.WithBackgroundReindex(time.Duration(cfg.ReindexDelayMs)*time.Millisecond))
+	}
+	rulesetService := ruleset.NewService(valkeyClient, rulesetOptions...)
 	log.Info().Msg("Ruleset service initialized")
 
+	// Drop cached rulesets that other server instances modify
+	if cfg.CacheInvalidation {
+		watcher, err := valkey.WatchKeys(cfg.ValkeyHost, cfg.ValkeyPort, cfg.KeyPrefix, func(key string) {
+			if name, ok := strings.CutPrefix(key, cfg.KeyPrefix); ok {
+				rulesetService.InvalidateCache(name)
+			}
+		}, valkeyOptions(cfg)...)
+		if err != nil {
+			log.Warn().Err(err).Msg("Cache invalidation is disabled; cached rulesets may be stale after changes by other clients")
+		} else {
+			defer watcher.Close()
+			log.Info().Msg("Cache invalidation enabled")
+		}
+	}
+
+	// Index rulesets stored before timestamp indexes existed
+	if count, err := rulesetService.RebuildIndexes(); err != nil {
+		log.Warn().Err(err).Msg("Failed to rebuild ruleset indexes")
+	} else {
+		log.Info().Int("rulesets", count).Msg("Ruleset indexes rebuilt")
+	}
+
+	// Describe the stored corpus, recomputed from storage rather than accumulated from writes
+	if serverMetrics != nil && cfg.CorpusMetricsIntervalMinutes > 0 {
+		stopCorpusMetrics := startCorpusMetrics(rulesetService, serverMetrics, time.Duration(cfg.CorpusMetricsIntervalMinutes)*time.Minute)
+		defer stopCorpusMetrics()
+	}
+
+	// Run fleet-wide scheduled jobs on one replica only
+	isLeader := func() bool { return true }
+	if cfg.LeaderElection {
+		elector := leader.NewElector(valkeyClient, "leader:"+cfg.KeyPrefix+"scheduler", replicaID(), time.Duration(cfg.LeaderLeaseSeconds)*time.Second)
+		stopElection := elector.Start()
+		defer stopElection()
+		isLeader = elector.IsLeader
+	}
+
+	// Periodically remove content no ruleset references any more
+	if cfg.GCIntervalMinutes > 0 {
+		stopGC := startGC(rulesetService, time.Duration(cfg.GCIntervalMinutes)*time.Minute, blobRetention(cfg), isLeader)
+		defer stopGC()
+	}
+
+	// Accept changes while Valkey is briefly unreachable and replay them later
+	// Changesets and maintenance go through the queue too, so they keep its order
+	var service ruleset.ServiceInterface = rulesetService
+	var changesetApplier ruleset.ChangesetApplier = rulesetService
+	var maintainer ruleset.Maintainer = rulesetService
+	if cfg.WriteQueueSize > 0 {
+		queue := ruleset.NewQueuedService(rulesetService, valkey.IsUnavailable, cfg.WriteQueueSize)
+		stopReplay := startReplay(queue, writeQueueRetryInterval)
+		defer stopReplay()
+		service = queue
+		changesetApplier = queue
+		maintainer = queue
+	}
+
+	// Keep a local snapshot for offline reads
+	if cfg.SnapshotPath != "" {
+		stopSnapshots := startSnapshots(rulesetService, cfg.SnapshotPath, time.Duration(cfg.SnapshotIntervalMinutes)*time.Minute)
+		defer stopSnapshots()
+	}
+
+	// Summarise changes in a periodic digest rather than notifying every mutation
+	if cfg.DigestWebhookURL != "" {
+		stopDigests := startDigests(changes, digest.NewWebhook(cfg.DigestWebhookURL), cfg.KeyPrefix, time.Duration(cfg.DigestIntervalMinutes)*time.Minute)
+		defer stopDigests()
+	}
+
+	trustedKeys, err := pack.ParsePublicKeys(cfg.PackTrustedKeys)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid PACK_TRUSTED_KEYS")
+	}
+
 	// Create MCP handler
-	mcpHandler := mcp.NewHandler(rulesetService)
+	promptService := prompt.NewService(valkeyClient)
+	snippetService := snippet.NewService(valkeyClient)
+	handlerOptions := []mcp.Option{
+		mcp.WithMetrics(serverMetrics),
+		mcp.WithPromptService(promptService),
+		mcp.WithSnippetService(snippetService),
+		mcp.WithSearchService(search.NewService(valkeyClient)),
+		mcp.WithPackInstaller(pack.NewManager(service, promptService, snippetService, trustedKeys)),
+		mcp.WithSourceFetcher(source.NewHTTPFetcher(nil)),
+		mcp.WithConcurrencyLimit(cfg.MaxConcurrentTools, time.Duration(cfg.ToolQueueTimeoutMs)*time.Millisecond),
+		mcp.WithServerConfig(cfg.Snapshot()),
+		mcp.WithMandatoryRulesets(cfg.MandatoryRulesets),
+		mcp.WithChangesets(changesetApplier),
+		mcp.WithMarkers(changes, marker.NewService(valkeyClient, cfg.KeyPrefix)),
+		mcp.WithLegacyURIPolicy(mcp.LegacyURIPolicy(cfg.LegacyURIPolicy)),
+	}
+	welcome, err := loadWelcome(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid welcome resource")
+	}
+	handlerOptions = append(handlerOptions, mcp.WithWelcome(welcome))
+	if cfg.IDStrategy != "" {
+		ids, err := util.NewIDGenerator(cfg.IDStrategy)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid ID strategy")
+		}
+		handlerOptions = append(handlerOptions, mcp.WithIDGenerator(ids))
+	}
+	if cfg.BackgroundReindex {
+		handlerOptions = append(handlerOptions, mcp.WithIndexStatus(rulesetService))
+	}
+	if cfg.ReferencePolicy != "" {
+		handlerOptions = append(handlerOptions, mcp.WithReferencePolicy(ruleset.ReferencePolicy(cfg.ReferencePolicy)))
+	}
+	if cfg.PackRegistryURL != "" {
+		registry, err := pack.NewRegistry(cfg.PackRegistryURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid PACK_REGISTRY_URL")
+		}
+		handlerOptions = append(handlerOptions, mcp.WithPackRegistry(registry))
+	}
+	if cfg.AdminTools {
+		handlerOptions = append(handlerOptions, mcp.WithAdmin(maintainer))
+	}
+	mcpHandler := mcp.NewHandler(service, handlerOptions...)
 	log.Info().Msg("MCP handler initialized")
 
+	// Start the optional HTTP server for metrics, ruleset access and the web UI
+	if cfg.HTTPAddr != "" {
+		httpOptions := []httpapi.Option{httpapi.WithEvents(changes)}
+		if cfg.WebUI {
+			httpOptions = append(httpOptions, httpapi.WithUI(cfg.WebUIToken))
+		}
+		httpServer := startHTTPServer(cfg.HTTPAddr, service, httpOptions...)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := httpServer.Shutdown(ctx); err != nil {
+				log.Error().Err(err).Msg("Error shutting down HTTP server")
+			}
+		}()
+	}
+
+	if err := serve(mcpHandler); err != nil {
+		log.Error().Err(err).Msg("MCP server error")
+		os.Exit(1)
+	}
+}
+
+// serve runs the MCP server until it fails or a shutdown signal arrives
+func serve(mcpHandler *mcp.Handler) error {
 	// Set up graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -81,11 +303,130 @@ func main() {
 	case sig := <-sigChan:
 		log.Info().Str("signal", sig.String()).Msg("Received shutdown signal")
 	case err := <-errChan:
-		log.Error().Err(err).Msg("MCP server error")
-		os.Exit(1)
+		return err
 	}
 
 	log.Info().Msg("MCP Ruleset Server stopped")
+	return nil
+}
+
+// startHTTPServer serves /metrics, /rulesets/, /schemas/ and, when enabled by
+// opts, /events and the web UI under /ui/ and /api/ on addr in the background
+func startHTTPServer(addr string, service ruleset.ServiceInterface, opts ...httpapi.Option) *http.Server {
+	api := httpapi.NewHandler(service, opts...)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(prometheus.DefaultGatherer))
+	mux.Handle("/rulesets/", api)
+	mux.Handle("/schemas/", api)
+	mux.Handle("/ui/", api)
+	mux.Handle("/api/", api)
+	mux.Handle("/events", api)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Str("addr", addr).Msg("Starting HTTP server")
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("HTTP server error")
+		}
+	}()
+
+	return httpServer
+}
+
+// newMetrics creates the server's metrics on the backend selected by
+// METRICS_BACKEND; they are nil when it is "none". stop flushes measurements
+// not exported yet.
+func newMetrics(cfg *config.Config) (m *metrics.Metrics, stop func(), err error) {
+	stop = func() {}
+	var backend metrics.Backend
+	switch cfg.MetricsBackend {
+	case "none":
+		return nil, stop, nil
+	case "otlp":
+		otlp, err := metrics.NewOTLPBackend(context.Background())
+		if err != nil {
+			return nil, stop, err
+		}
+		backend = otlp
+		stop = func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := otlp.Shutdown(ctx); err != nil {
+				log.Warn().Err(err).Msg("Failed to flush metrics")
+			}
+		}
+	default:
+		backend = metrics.NewPrometheusBackend(prometheus.DefaultRegisterer)
+	}
+
+	m, err = metrics.NewWithBackend(backend)
+	if err != nil {
+		stop()
+		return nil, func() {}, err
+	}
+	return m, stop, nil
+}
+
+// loadWelcome returns the markdown of the archivyr://welcome resource, empty
+// when none is configured
+func loadWelcome(cfg *config.Config) (string, error) {
+	if cfg.WelcomeFile == "" {
+		return cfg.WelcomeMarkdown, nil
+	}
+	data, err := os.ReadFile(cfg.WelcomeFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read WELCOME_FILE: %w", err)
+	}
+	return string(data), nil
+}
+
+// replicaID identifies this process among the replicas sharing a Valkey
+func replicaID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	id, err := util.MustIDGenerator(util.StrategyNanoID).NewID("")
+	if err != nil {
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return host + "-" + id
+}
+
+// valkeyOptions returns the Valkey connection options for cfg
+func valkeyOptions(cfg *config.Config) []valkey.Option {
+	return []valkey.Option{
+		valkey.WithClientName(cfg.ValkeyClientName),
+		valkey.WithRequestTimeout(time.Duration(cfg.ValkeyRequestTimeoutMs) * time.Millisecond),
+		valkey.WithDatabase(cfg.ValkeyDatabase),
+	}
+}
+
+// serviceOptions translates configuration into ruleset service options
+func serviceOptions(cfg *config.Config) []ruleset.Option {
+	opts := []ruleset.Option{
+		ruleset.WithKeyPrefix(cfg.KeyPrefix),
+		ruleset.WithLimits(ruleset.Limits{MaxMarkdownBytes: cfg.MaxMarkdownBytes}),
+	}
+
+	if cfg.ParseMode == string(ruleset.ParseModeLenient) {
+		opts = append(opts, ruleset.WithParseMode(ruleset.ParseModeLenient))
+	}
+
+	if cfg.BlobStorage {
+		opts = append(opts, ruleset.WithBlobStorage())
+	}
+
+	if cfg.CacheSize > 0 {
+		opts = append(opts, ruleset.WithCache(ruleset.NewLRUCache(cfg.CacheSize)))
+	}
+
+	return opts
 }
 
 // setupLogger configures zerolog with the specified log level
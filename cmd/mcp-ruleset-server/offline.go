@@ -0,0 +1,95 @@
+package main
+
+import (
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/mcp"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snapshot"
+	"github.com/rs/zerolog/log"
+)
+
+// runOffline serves reads from the snapshot at SNAPSHOT_PATH without
+// connecting to Valkey and returns the process exit code
+func runOffline(cfg *config.Config) int {
+	if cfg.SnapshotPath == "" {
+		log.Error().Msg("Offline mode requires SNAPSHOT_PATH")
+		return 1
+	}
+
+	snap, err := snapshot.Load(cfg.SnapshotPath)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load snapshot")
+		return 1
+	}
+	service, err := snap.Service()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load snapshot")
+		return 1
+	}
+	log.Warn().
+		Str("path", cfg.SnapshotPath).
+		Time("taken_at", snap.TakenAt).
+		Int("rulesets", len(snap.Rulesets)).
+		Msg("Serving read-only snapshot; changes are rejected until Valkey is available")
+
+	welcome, err := loadWelcome(cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid welcome resource")
+		return 1
+	}
+
+	serverConfig := cfg.Snapshot()
+	serverConfig["offline"] = map[string]any{"snapshot_taken_at": snap.TakenAt}
+	if err := serve(mcp.NewHandler(service, mcp.WithServerConfig(serverConfig), mcp.WithMandatoryRulesets(cfg.MandatoryRulesets), mcp.WithWelcome(welcome), mcp.WithLegacyURIPolicy(mcp.LegacyURIPolicy(cfg.LegacyURIPolicy)))); err != nil {
+		log.Error().Err(err).Msg("MCP server error")
+		return 1
+	}
+	return 0
+}
+
+// startSnapshots writes a snapshot of the corpus now and every interval in the
+// background, and a last one when stop is called. A zero interval skips the
+// periodic writes.
+func startSnapshots(service ruleset.ServiceInterface, path string, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	write := func() {
+		snap, err := snapshot.Take(service, time.Now())
+		if err == nil {
+			err = snapshot.Write(path, snap)
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("Failed to write snapshot")
+			return
+		}
+		log.Debug().Str("path", path).Int("rulesets", len(snap.Rulesets)).Msg("Snapshot written")
+	}
+
+	go func() {
+		defer close(finished)
+		write()
+		if interval <= 0 {
+			<-done
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				write()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+		write()
+	}
+}
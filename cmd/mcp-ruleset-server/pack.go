@@ -0,0 +1,247 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/pack"
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/jbrinkman/archivyr/internal/valkey"
+)
+
+const packUsage = `usage: mcp-ruleset-server pack <command> [flags]
+
+commands:
+  keygen   generate a signing key pair
+  export   export and sign a pack from the connected Valkey instance
+  install  verify and install a signed pack bundle file, or -name to fetch one from PACK_REGISTRY_URL
+  search   search the pack registry`
+
+// runPack implements the "pack" subcommand and returns the process exit code
+func runPack(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, packUsage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "keygen":
+		err = packKeygen(args[1:])
+	case "export":
+		err = packExport(cfg, args[1:])
+	case "install":
+		err = packInstall(cfg, args[1:])
+	case "search":
+		err = packSearch(cfg, args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, packUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pack %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// packKeygen writes a new private key to a file and prints the public key
+func packKeygen(args []string) error {
+	fs := flag.NewFlagSet("pack keygen", flag.ContinueOnError)
+	out := fs.String("out", "pack.key", "file to write the base64 private key to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	if err := os.WriteFile(*out, []byte(base64.StdEncoding.EncodeToString(private.Seed())+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+
+	fmt.Printf("Private key written to %s\nPublic key (add to PACK_TRUSTED_KEYS): %s\n",
+		*out, base64.StdEncoding.EncodeToString(public))
+	return nil
+}
+
+// packExport exports the selected entities as a signed bundle
+func packExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("pack export", flag.ContinueOnError)
+	name := fs.String("name", "", "pack name (snake_case)")
+	version := fs.String("version", "", "pack version")
+	description := fs.String("description", "", "pack description")
+	keyFile := fs.String("key", "pack.key", "file containing the base64 private key")
+	out := fs.String("out", "", "file to write the bundle to (default stdout)")
+	rulesets := fs.String("rulesets", "", "comma-separated ruleset names (default all)")
+	templates := fs.String("templates", "", "comma-separated prompt template names (default all)")
+	snippets := fs.String("snippets", "", "comma-separated snippet names (default all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	keyData, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+	key, err := pack.ParsePrivateKey(string(keyData))
+	if err != nil {
+		return err
+	}
+
+	manager, closeClient, err := newPackManager(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	p, err := manager.Export(*name, *version, *description, pack.Selection{
+		Rulesets:  splitNames(*rulesets),
+		Templates: splitNames(*templates),
+		Snippets:  splitNames(*snippets),
+	})
+	if err != nil {
+		return err
+	}
+
+	bundle, err := pack.Sign(p, key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return os.WriteFile(*out, data, 0o644)
+}
+
+// packInstall installs a bundle signed by one of PACK_TRUSTED_KEYS from a file or the registry
+func packInstall(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("pack install", flag.ContinueOnError)
+	name := fs.String("name", "", "pack to fetch from the registry instead of a bundle file")
+	version := fs.String("version", "", "registry pack version (default latest)")
+	force := fs.Bool("force", false, "replace rulesets not installed from this pack")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case *name != "" && fs.NArg() == 0:
+		registry, regErr := newRegistry(cfg)
+		if regErr != nil {
+			return regErr
+		}
+		data, err = registry.Fetch(*name, *version)
+	case *name == "" && fs.NArg() == 1:
+		data, err = os.ReadFile(fs.Arg(0))
+	default:
+		return fmt.Errorf("expected exactly one bundle file or -name")
+	}
+	if err != nil {
+		return err
+	}
+
+	trusted, err := pack.ParsePublicKeys(cfg.PackTrustedKeys)
+	if err != nil {
+		return fmt.Errorf("invalid PACK_TRUSTED_KEYS: %w", err)
+	}
+
+	manager, closeClient, err := newPackManager(cfg, trusted)
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	result, err := manager.Install(data, pack.InstallOptions{Force: *force})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed pack '%s' version %s: %d ruleset(s), %d prompt template(s), %d snippet(s)\n",
+		result.Name, result.Version, result.Rulesets, result.Templates, result.Snippets)
+	for _, c := range result.Conflicts {
+		fmt.Printf("Skipped %s; use -force to replace it\n", c)
+	}
+	return nil
+}
+
+// packSearch lists registry packs matching the query arguments
+func packSearch(cfg *config.Config, args []string) error {
+	registry, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+
+	entries, err := registry.Search(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s\t%s\t%s\n", e.Name, e.Version, e.Description)
+	}
+	return nil
+}
+
+// newRegistry creates a client for PACK_REGISTRY_URL
+func newRegistry(cfg *config.Config) (*pack.Registry, error) {
+	if cfg.PackRegistryURL == "" {
+		return nil, fmt.Errorf("PACK_REGISTRY_URL is not set")
+	}
+	return pack.NewRegistry(cfg.PackRegistryURL)
+}
+
+// newPackManager connects to Valkey and returns a pack manager with a function closing the connection
+func newPackManager(cfg *config.Config, trusted []ed25519.PublicKey) (*pack.Manager, func(), error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	client, err := valkey.NewClient(cfg.ValkeyHost, cfg.ValkeyPort, valkeyOptions(cfg)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to Valkey: %w", err)
+	}
+
+	manager := pack.NewManager(
+		ruleset.NewService(client, serviceOptions(cfg)...),
+		prompt.NewService(client),
+		snippet.NewService(client),
+		trusted,
+	)
+	return manager, func() { _ = client.Close() }, nil
+}
+
+// splitNames splits a comma-separated flag value, returning nil when it is empty
+func splitNames(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/report"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/rs/zerolog/log"
+)
+
+// runReport implements the "report" subcommand, which writes a CSV summary of
+// every ruleset for spreadsheets. It returns the process exit code.
+func runReport(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	out := fs.String("out", "", "file to write the report to (default stdout)")
+	excel := fs.Bool("excel", false, "write a byte order mark and separate fields with ';' for Excel in locales using a decimal comma")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	client, ok := connectForCLI(cfg)
+	if !ok {
+		return 1
+	}
+	defer func() { _ = client.Close() }()
+
+	rulesets, err := ruleset.NewService(client, serviceOptions(cfg)...).List()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list rulesets")
+		return 1
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create report file")
+			return 1
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	opts := report.Options{}
+	if *excel {
+		opts = report.Options{Comma: ';', BOM: true}
+	}
+	if err := report.Write(w, rulesets, opts); err != nil {
+		log.Error().Err(err).Msg("Report failed")
+		return 1
+	}
+
+	if *out != "" {
+		fmt.Printf("Report of %d ruleset(s) written to %s\n", len(rulesets), *out)
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/config"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/selftest"
+	"github.com/jbrinkman/archivyr/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+// runSelftest implements the "selftest" subcommand, which runs a create, get,
+// update, search and delete cycle against the configured Valkey under a
+// temporary key prefix and prints the outcome of each step. It returns the
+// process exit code: 0 when every step passed.
+func runSelftest(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	client, ok := connectForCLI(cfg)
+	if !ok {
+		return 1
+	}
+	defer func() { _ = client.Close() }()
+
+	// A prefix outside KEY_PREFIX, so servers sharing the Valkey never list the probe
+	id, err := util.MustIDGenerator(util.StrategyNanoID).NewID("")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate self-test namespace")
+		return 1
+	}
+	prefix := "selftest:" + id + ":"
+
+	// The deployment's settings, such as blob storage and limits, apply to the probe too
+	service := ruleset.NewService(client, append(serviceOptions(cfg), ruleset.WithKeyPrefix(prefix))...)
+	fmt.Printf("Running self-test against %s:%s under key prefix %s\n", cfg.ValkeyHost, cfg.ValkeyPort, prefix)
+
+	report := selftest.Run(service)
+	for _, step := range report.Steps {
+		switch step.Status {
+		case selftest.StatusSkipped:
+			fmt.Printf("%s  %s\n", step.Status, step.Name)
+		case selftest.StatusFailed:
+			fmt.Printf("%s  %-7s %s: %v\n", step.Status, step.Name, step.Duration.Round(time.Microsecond), step.Err)
+		default:
+			fmt.Printf("%s  %-7s %s\n", step.Status, step.Name, step.Duration.Round(time.Microsecond))
+		}
+	}
+	if report.CleanupErr != nil {
+		fmt.Printf("WARNING: %v; remove keys matching %s* manually\n", report.CleanupErr, prefix)
+	}
+
+	if !report.Passed() {
+		fmt.Printf("Self-test failed: %d of %d step(s) failed\n", report.Failed(), len(report.Steps))
+		return 1
+	}
+	fmt.Printf("Self-test passed: %d step(s)\n", len(report.Steps))
+	return 0
+}
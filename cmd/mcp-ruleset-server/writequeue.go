@@ -0,0 +1,55 @@
+package main
+
+import (
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/rs/zerolog/log"
+)
+
+// writeQueueRetryInterval is how often queued changes are replayed while Valkey is unreachable
+const writeQueueRetryInterval = 5 * time.Second
+
+// startReplay replays queued changes every interval in the background until
+// stop is called. Stopping makes a last attempt so a recovered connection is
+// not left with changes that would otherwise be lost.
+func startReplay(queue *ruleset.QueuedService, interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	replay := func() {
+		if queue.Pending() == 0 {
+			return
+		}
+		applied, err := queue.Replay()
+		if applied > 0 {
+			log.Info().Int("applied", applied).Int("pending", queue.Pending()).Msg("Replayed queued changes")
+		}
+		if err != nil {
+			log.Debug().Err(err).Int("pending", queue.Pending()).Msg("Valkey still unavailable; keeping queued changes")
+		}
+	}
+
+	go func() {
+		defer close(finished)
+		for {
+			select {
+			case <-ticker.C:
+				replay()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-finished
+		replay()
+		if pending := queue.Pending(); pending > 0 {
+			log.Warn().Int("pending", pending).Msg("Discarding queued changes that could not be applied")
+		}
+	}
+}
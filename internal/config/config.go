@@ -3,23 +3,162 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/util"
+	"github.com/jbrinkman/archivyr/internal/validation"
 )
 
 // Config holds the application configuration
 type Config struct {
 	ValkeyHost string
 	ValkeyPort string
-	LogLevel   string
+	// ValkeyClientName is the connection name shown in CLIENT LIST (empty leaves it unset)
+	ValkeyClientName string
+	// ValkeyRequestTimeoutMs bounds each Valkey command (0 keeps the client default)
+	ValkeyRequestTimeoutMs int
+	// ValkeyDatabase is the logical database holding the rulesets
+	ValkeyDatabase int
+	LogLevel       string
+	// ParseMode selects how malformed stored data is handled: "strict" fails
+	// the read, "lenient" logs a warning and returns what could be decoded.
+	ParseMode string
+	// KeyPrefix is the Valkey key prefix for ruleset hashes
+	KeyPrefix string
+	// CacheSize is the number of rulesets held in the read cache (0 disables it)
+	CacheSize int
+	// CacheInvalidation drops cached rulesets modified by other clients, using
+	// Valkey keyspace notifications
+	CacheInvalidation bool
+	// MaxMarkdownBytes caps the size of ruleset markdown (0 means unlimited)
+	MaxMarkdownBytes int
+	// HTTPAddr is the listen address of the optional HTTP server exposing /metrics and /rulesets (empty disables it)
+	HTTPAddr string
+	// PackTrustedKeys is a comma-separated list of base64 ed25519 public keys whose packs may be installed
+	PackTrustedKeys string
+	// PackRegistryURL is the HTTPS base URL of the pack registry (empty disables registry tools)
+	PackRegistryURL string
+	// MaxConcurrentTools caps the number of tool invocations running at once (0 means unlimited)
+	MaxConcurrentTools int
+	// ToolQueueTimeoutMs is how long a tool call waits for a free slot before it is rejected
+	ToolQueueTimeoutMs int
+	// BlobStorage stores ruleset bodies as shared content-addressed blobs
+	BlobStorage bool
+	// GCIntervalMinutes is how often unreferenced content is garbage collected (0 disables it)
+	GCIntervalMinutes int
+	// BlobRetentionMinutes is how long garbage collection keeps a blob after it was last stored
+	BlobRetentionMinutes int
+	// AdminTools registers the operator-only rebuild_indexes and flush_cache tools
+	AdminTools bool
+	// WriteQueueSize is how many changes are queued while Valkey is unreachable (0 disables queueing)
+	WriteQueueSize int
+	// SnapshotPath is the local file holding a snapshot of the corpus for offline reads (empty disables snapshots)
+	SnapshotPath string
+	// SnapshotIntervalMinutes is how often the snapshot is rewritten while connected
+	SnapshotIntervalMinutes int
+	// DegradedMode serves the snapshot read-only when Valkey cannot be reached at startup
+	DegradedMode bool
+	// WebUI serves the web UI for browsing and editing rulesets on the HTTP listener
+	WebUI bool
+	// WebUIToken is the access token required by the web UI, its API and the event stream
+	WebUIToken string
+	// DigestWebhookURL receives a periodic digest of ruleset changes (empty disables digests)
+	DigestWebhookURL string
+	// DigestIntervalMinutes is the period each digest covers
+	DigestIntervalMinutes int
+	// MandatoryRulesets names the rulesets every agent must follow, highest priority first
+	MandatoryRulesets []string
+	// ReferencePolicy selects what deleting a ruleset other rulesets link to does:
+	// "warn" reports the references, "block" refuses, "cascade" removes the links
+	ReferencePolicy string
+	// LeaderElection makes replicas sharing a Valkey elect one of them to run
+	// scheduled jobs such as garbage collection
+	LeaderElection bool
+	// LeaderLeaseSeconds is how long a leader holds its lease without renewing it
+	LeaderLeaseSeconds int
+	// WelcomeMarkdown and WelcomeFile provide the archivyr://welcome
+	// onboarding resource inline or from a markdown file; at most one is set
+	WelcomeMarkdown string
+	WelcomeFile     string
+	// IDStrategy selects how the server generates IDs: uuid, ulid, nanoid or date_slug
+	IDStrategy string
+	// BackgroundReindex leaves index maintenance of pack installs to a
+	// throttled background rebuild pausing ReindexDelayMs after each ruleset
+	BackgroundReindex bool
+	ReindexDelayMs    int
+	// MetricsBackend selects where metrics are recorded: "prometheus" (served
+	// at /metrics), "otlp" (pushed to an OpenTelemetry collector) or "none"
+	MetricsBackend string
+	// CorpusMetricsIntervalMinutes is how often the stored corpus metrics are
+	// recomputed from storage (0 disables them)
+	CorpusMetricsIntervalMinutes int
+	// LegacyURIPolicy selects how reads of the deprecated ruleset:{name} URI
+	// form are handled: "allow", "warn" or "reject"
+	LegacyURIPolicy string
 }
 
+// minWebUITokenLength is the shortest accepted web UI access token
+const minWebUITokenLength = 16
+
 // LoadConfig loads configuration from environment variables with defaults
 func LoadConfig() *Config {
 	config := &Config{
 		ValkeyHost: getEnvOrDefault("VALKEY_HOST", "localhost"),
 		ValkeyPort: getEnvOrDefault("VALKEY_PORT", "6379"),
 		LogLevel:   getEnvOrDefault("LOG_LEVEL", "info"),
+		ParseMode:  getEnvOrDefault("PARSE_MODE", "strict"),
+		KeyPrefix:  getEnvOrDefault("KEY_PREFIX", "ruleset:"),
+
+		ValkeyClientName:       os.Getenv("VALKEY_CLIENT_NAME"),
+		ValkeyRequestTimeoutMs: getEnvIntOrDefault("VALKEY_REQUEST_TIMEOUT_MS", 0),
+		ValkeyDatabase:         getEnvIntOrDefault("VALKEY_DATABASE", 0),
+
+		CacheSize:         getEnvIntOrDefault("CACHE_SIZE", 0),
+		CacheInvalidation: getEnvBoolOrDefault("CACHE_INVALIDATION", false),
+		MaxMarkdownBytes:  getEnvIntOrDefault("MAX_MARKDOWN_BYTES", 0),
+		HTTPAddr:          os.Getenv("HTTP_ADDR"),
+		PackTrustedKeys:   os.Getenv("PACK_TRUSTED_KEYS"),
+		PackRegistryURL:   os.Getenv("PACK_REGISTRY_URL"),
+
+		MaxConcurrentTools: getEnvIntOrDefault("MAX_CONCURRENT_TOOLS", 0),
+		ToolQueueTimeoutMs: getEnvIntOrDefault("TOOL_QUEUE_TIMEOUT_MS", 5000),
+
+		BlobStorage:          getEnvBoolOrDefault("BLOB_STORAGE", false),
+		GCIntervalMinutes:    getEnvIntOrDefault("GC_INTERVAL_MINUTES", 0),
+		BlobRetentionMinutes: getEnvIntOrDefault("BLOB_RETENTION_MINUTES", 60),
+		AdminTools:           getEnvBoolOrDefault("ADMIN_TOOLS", false),
+		WriteQueueSize:       getEnvIntOrDefault("WRITE_QUEUE_SIZE", 0),
+
+		SnapshotPath:            os.Getenv("SNAPSHOT_PATH"),
+		SnapshotIntervalMinutes: getEnvIntOrDefault("SNAPSHOT_INTERVAL_MINUTES", 15),
+		DegradedMode:            getEnvBoolOrDefault("DEGRADED_MODE", false),
+
+		WebUI:      getEnvBoolOrDefault("WEB_UI", false),
+		WebUIToken: os.Getenv("WEB_UI_TOKEN"),
+
+		DigestWebhookURL:      os.Getenv("DIGEST_WEBHOOK_URL"),
+		DigestIntervalMinutes: getEnvIntOrDefault("DIGEST_INTERVAL_MINUTES", 1440),
+
+		MandatoryRulesets: splitList(os.Getenv("MANDATORY_RULESETS")),
+		ReferencePolicy:   getEnvOrDefault("REFERENCE_POLICY", "warn"),
+		IDStrategy:        getEnvOrDefault("ID_STRATEGY", util.StrategyUUID),
+		LegacyURIPolicy:   getEnvOrDefault("LEGACY_URI_POLICY", "warn"),
+		MetricsBackend:    getEnvOrDefault("METRICS_BACKEND", "prometheus"),
+
+		CorpusMetricsIntervalMinutes: getEnvIntOrDefault("CORPUS_METRICS_INTERVAL_MINUTES", 15),
+
+		WelcomeMarkdown: os.Getenv("WELCOME_MARKDOWN"),
+		WelcomeFile:     os.Getenv("WELCOME_FILE"),
+
+		BackgroundReindex: getEnvBoolOrDefault("BACKGROUND_REINDEX", false),
+		ReindexDelayMs:    getEnvIntOrDefault("REINDEX_DELAY_MS", 10),
+
+		LeaderElection:     getEnvBoolOrDefault("LEADER_ELECTION", false),
+		LeaderLeaseSeconds: getEnvIntOrDefault("LEADER_LEASE_SECONDS", 30),
 	}
 	return config
 }
@@ -56,9 +195,208 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("LOG_LEVEL must be one of: debug, info, warn, error; got %s", c.LogLevel)
 	}
 
+	// Validate parse mode (empty falls back to strict)
+	switch c.ParseMode {
+	case "", "strict", "lenient":
+	default:
+		return fmt.Errorf("PARSE_MODE must be one of: strict, lenient; got %s", c.ParseMode)
+	}
+
+	if c.ValkeyRequestTimeoutMs < 0 {
+		return fmt.Errorf("VALKEY_REQUEST_TIMEOUT_MS must be a non-negative integer")
+	}
+
+	if c.ValkeyDatabase < 0 {
+		return fmt.Errorf("VALKEY_DATABASE must be a non-negative integer")
+	}
+
+	if c.CacheSize < 0 {
+		return fmt.Errorf("CACHE_SIZE must be a non-negative integer")
+	}
+
+	if c.CacheInvalidation && c.CacheSize == 0 {
+		return fmt.Errorf("CACHE_INVALIDATION requires CACHE_SIZE to enable the cache")
+	}
+
+	if c.MaxMarkdownBytes < 0 {
+		return fmt.Errorf("MAX_MARKDOWN_BYTES must be a non-negative integer")
+	}
+
+	if c.MaxConcurrentTools < 0 {
+		return fmt.Errorf("MAX_CONCURRENT_TOOLS must be a non-negative integer")
+	}
+
+	if c.ToolQueueTimeoutMs < 0 {
+		return fmt.Errorf("TOOL_QUEUE_TIMEOUT_MS must be a non-negative integer")
+	}
+
+	if c.GCIntervalMinutes < 0 {
+		return fmt.Errorf("GC_INTERVAL_MINUTES must be a non-negative integer")
+	}
+
+	if c.WriteQueueSize < 0 {
+		return fmt.Errorf("WRITE_QUEUE_SIZE must be a non-negative integer")
+	}
+
+	if c.SnapshotIntervalMinutes < 0 {
+		return fmt.Errorf("SNAPSHOT_INTERVAL_MINUTES must be a non-negative integer")
+	}
+
+	if c.DegradedMode && c.SnapshotPath == "" {
+		return fmt.Errorf("DEGRADED_MODE requires SNAPSHOT_PATH")
+	}
+
+	if c.BlobRetentionMinutes < 0 {
+		return fmt.Errorf("BLOB_RETENTION_MINUTES must be a non-negative integer")
+	}
+
+	if c.HTTPAddr != "" && c.WebUI && len(c.WebUIToken) < minWebUITokenLength {
+		return fmt.Errorf("WEB_UI requires WEB_UI_TOKEN of at least %d characters", minWebUITokenLength)
+	}
+
+	if c.DigestWebhookURL != "" {
+		u, err := url.Parse(c.DigestWebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("DIGEST_WEBHOOK_URL must be an http or https URL")
+		}
+		if c.DigestIntervalMinutes < 1 {
+			return fmt.Errorf("DIGEST_INTERVAL_MINUTES must be a positive integer")
+		}
+	}
+
+	switch c.ReferencePolicy {
+	case "", "warn", "block", "cascade":
+	default:
+		return fmt.Errorf("REFERENCE_POLICY must be one of: warn, block, cascade; got %s", c.ReferencePolicy)
+	}
+
+	if c.ReindexDelayMs < 0 {
+		return fmt.Errorf("REINDEX_DELAY_MS must be a non-negative integer")
+	}
+
+	switch c.MetricsBackend {
+	case "", "prometheus", "otlp", "none":
+	default:
+		return fmt.Errorf("METRICS_BACKEND must be one of: prometheus, otlp, none; got %s", c.MetricsBackend)
+	}
+
+	if c.CorpusMetricsIntervalMinutes < 0 {
+		return fmt.Errorf("CORPUS_METRICS_INTERVAL_MINUTES must be a non-negative integer")
+	}
+
+	switch c.LegacyURIPolicy {
+	case "", "allow", "warn", "reject":
+	default:
+		return fmt.Errorf("LEGACY_URI_POLICY must be one of: allow, warn, reject; got %s", c.LegacyURIPolicy)
+	}
+
+	if c.WelcomeMarkdown != "" && c.WelcomeFile != "" {
+		return fmt.Errorf("set at most one of WELCOME_MARKDOWN and WELCOME_FILE")
+	}
+
+	if c.LeaderElection && c.LeaderLeaseSeconds < 3 {
+		return fmt.Errorf("LEADER_LEASE_SECONDS must be an integer of at least 3")
+	}
+
+	if c.IDStrategy != "" && !slices.Contains(util.Strategies, c.IDStrategy) {
+		return fmt.Errorf("ID_STRATEGY must be one of: %s; got %s", strings.Join(util.Strategies, ", "), c.IDStrategy)
+	}
+
+	seen := make(map[string]bool, len(c.MandatoryRulesets))
+	for _, name := range c.MandatoryRulesets {
+		if err := validation.ValidateRulesetName(name); err != nil {
+			return fmt.Errorf("invalid MANDATORY_RULESETS: %w", err)
+		}
+		if seen[name] {
+			return fmt.Errorf("invalid MANDATORY_RULESETS: '%s' is listed more than once", name)
+		}
+		seen[name] = true
+	}
+
+	if c.PackRegistryURL != "" {
+		u, err := url.Parse(c.PackRegistryURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("PACK_REGISTRY_URL must be an https URL, got %s", c.PackRegistryURL)
+		}
+	}
+
 	return nil
 }
 
+// Snapshot returns the effective configuration for reporting to clients.
+// Connection details are left out and credentials in URLs are redacted.
+func (c *Config) Snapshot() map[string]any {
+	parseMode := c.ParseMode
+	if parseMode == "" {
+		parseMode = "strict"
+	}
+
+	registry := ""
+	if c.PackRegistryURL != "" {
+		registry = c.PackRegistryURL
+		if u, err := url.Parse(c.PackRegistryURL); err == nil {
+			registry = u.Redacted()
+		}
+	}
+
+	return map[string]any{
+		"backend": map[string]any{
+			"type":               "valkey",
+			"key_prefix":         c.KeyPrefix,
+			"parse_mode":         parseMode,
+			"blob_storage":       c.BlobStorage,
+			"protocol":           "RESP3",
+			"request_timeout_ms": c.ValkeyRequestTimeoutMs,
+		},
+		"limits": map[string]any{
+			"max_markdown_bytes":    c.MaxMarkdownBytes,
+			"max_concurrent_tools":  c.MaxConcurrentTools,
+			"tool_queue_timeout_ms": c.ToolQueueTimeoutMs,
+			"write_queue_size":      c.WriteQueueSize,
+		},
+		"cache": map[string]any{
+			"size":         c.CacheSize,
+			"invalidation": c.CacheInvalidation,
+		},
+		"background_reindex": map[string]any{
+			"enabled":  c.BackgroundReindex,
+			"delay_ms": c.ReindexDelayMs,
+		},
+		"garbage_collection": map[string]any{
+			"interval_minutes":       c.GCIntervalMinutes,
+			"blob_retention_minutes": c.BlobRetentionMinutes,
+		},
+		"snapshot": map[string]any{
+			"enabled":          c.SnapshotPath != "",
+			"interval_minutes": c.SnapshotIntervalMinutes,
+			"degraded_mode":    c.DegradedMode,
+		},
+		"digest": map[string]any{
+			"enabled":          c.DigestWebhookURL != "",
+			"interval_minutes": c.DigestIntervalMinutes,
+		},
+		"mandatory_rulesets": append([]string{}, c.MandatoryRulesets...),
+		"reference_policy":   c.ReferencePolicy,
+		"id_strategy":        c.IDStrategy,
+		"legacy_uri_policy":  c.LegacyURIPolicy,
+		"metrics_backend":    c.MetricsBackend,
+		"welcome":            c.WelcomeMarkdown != "" || c.WelcomeFile != "",
+		"corpus_metrics": map[string]any{
+			"interval_minutes": c.CorpusMetricsIntervalMinutes,
+		},
+		"leader_election": map[string]any{
+			"enabled":       c.LeaderElection,
+			"lease_seconds": c.LeaderLeaseSeconds,
+		},
+		"http_enabled": c.HTTPAddr != "",
+		"web_ui":       c.HTTPAddr != "" && c.WebUI,
+		"packs": map[string]any{
+			"registry_url": registry,
+			"trusted_keys": len(splitList(c.PackTrustedKeys)),
+		},
+	}
+}
+
 // getEnvOrDefault retrieves an environment variable or returns a default value
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -66,3 +404,38 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// splitList splits a comma-separated list, dropping surrounding whitespace and empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvIntOrDefault retrieves an integer environment variable or returns a default value.
+// Unparseable values yield -1 so that Validate reports them.
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// getEnvBoolOrDefault retrieves a boolean environment variable or returns a default value.
+// Values are parsed by strconv.ParseBool; unparseable values yield the default.
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	b, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
@@ -0,0 +1,177 @@
+// Package diff produces line-based unified diffs between two texts.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// maxTableCells bounds the LCS table; larger inputs are diffed as a full replacement
+const maxTableCells = 4_000_000
+
+// opKind identifies an edit operation on a line
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is a single line of an edit script
+type op struct {
+	kind opKind
+	line string
+	// aIndex and bIndex are the positions of the line in the old and new text
+	aIndex, bIndex int
+}
+
+// Unified returns a unified diff turning a into b, or an empty string when they are equal
+func Unified(fromName, toName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := editScript(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == opEqual {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are separated by at most 2*contextLines equal lines
+		end := start
+		for end < len(ops) {
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				break
+			}
+			end = next
+			for end < len(ops) && ops[end].kind != opEqual {
+				end++
+			}
+		}
+
+		lo := max(start-contextLines, 0)
+		hi := min(end+contextLines, len(ops))
+		writeHunk(&out, ops[lo:hi])
+		start = hi
+	}
+
+	return out.String()
+}
+
+// writeHunk writes a hunk header and its lines
+func writeHunk(out *strings.Builder, ops []op) {
+	aStart, bStart, aCount, bCount := -1, -1, 0, 0
+	for _, o := range ops {
+		if o.kind != opInsert {
+			if aStart < 0 {
+				aStart = o.aIndex
+			}
+			aCount++
+		}
+		if o.kind != opDelete {
+			if bStart < 0 {
+				bStart = o.bIndex
+			}
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aStart, aCount, ops[0].aIndex), hunkRange(bStart, bCount, ops[0].bIndex))
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			out.WriteString(" ")
+		case opDelete:
+			out.WriteString("-")
+		case opInsert:
+			out.WriteString("+")
+		}
+		out.WriteString(o.line)
+		out.WriteString("\n")
+	}
+}
+
+// hunkRange formats a 1-based hunk range; empty ranges refer to the preceding line
+func hunkRange(start, count, fallback int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", fallback)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// editScript computes a minimal line edit script from a to b using the longest common subsequence
+func editScript(a, b []string) []op {
+	if len(a)*len(b) > maxTableCells {
+		return replaceScript(a, b)
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, line: a[i], aIndex: i, bIndex: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Prefer deletions so removed lines precede their replacements
+			ops = append(ops, op{kind: opDelete, line: a[i], aIndex: i, bIndex: j})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, line: b[j], aIndex: i, bIndex: j})
+			j++
+		}
+	}
+	return ops
+}
+
+// replaceScript deletes every line of a and inserts every line of b
+func replaceScript(a, b []string) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	for i, line := range a {
+		ops = append(ops, op{kind: opDelete, line: line, aIndex: i})
+	}
+	for j, line := range b {
+		ops = append(ops, op{kind: opInsert, line: line, aIndex: len(a), bIndex: j})
+	}
+	return ops
+}
+
+// splitLines splits text into lines without their terminators
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
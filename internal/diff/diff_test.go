@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name:     "equal",
+			a:        "one\ntwo\n",
+			b:        "one\ntwo\n",
+			expected: "",
+		},
+		{
+			name:     "changed line",
+			a:        "one\ntwo\nthree\n",
+			b:        "one\n2\nthree\n",
+			expected: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n",
+		},
+		{
+			name:     "insert into empty",
+			a:        "",
+			b:        "one\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+one\n",
+		},
+		{
+			name:     "separate hunks",
+			a:        "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n",
+			b:        "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n",
+			expected: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -7,4 +7,4 @@\n g\n h\n i\n-j\n+J\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Unified("old", "new", tt.a, tt.b))
+		})
+	}
+}
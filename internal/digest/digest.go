@@ -0,0 +1,171 @@
+// Package digest aggregates ruleset changes over a period into one summary
+// notification, so subscribers get a low-noise overview instead of a message
+// per mutation.
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+)
+
+// Digest summarises the net changes to the rulesets of a namespace over a
+// period. Subject and Text carry a plain-text rendering so the payload can be
+// relayed as an email by webhook-to-mail bridges without further formatting.
+type Digest struct {
+	Namespace string    `json:"namespace"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	// Mutations is the number of changes aggregated, before netting them out per ruleset
+	Mutations int      `json:"mutations"`
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	Subject   string   `json:"subject"`
+	Text      string   `json:"text"`
+}
+
+// Collector accumulates change events for the next digest. It is not safe for
+// concurrent use.
+type Collector struct {
+	namespace string
+	from      time.Time
+	mutations int
+	changes   map[string]string
+}
+
+// NewCollector creates a collector for namespace whose first period starts at from
+func NewCollector(namespace string, from time.Time) *Collector {
+	return &Collector{namespace: namespace, from: from.UTC(), changes: make(map[string]string)}
+}
+
+// Add records a change event, netting it out against earlier changes to the
+// same ruleset: a ruleset created and then updated is reported as created, one
+// created and then deleted is not reported, and one deleted and then created
+// again is reported as updated. Markers are not changes and are ignored.
+func (c *Collector) Add(e events.Event) {
+	if e.Type == events.TypeMarked {
+		return
+	}
+	c.mutations++
+	prev, seen := c.changes[e.Name]
+	switch {
+	case !seen:
+		c.changes[e.Name] = e.Type
+	case prev == events.TypeCreated && e.Type == events.TypeDeleted:
+		delete(c.changes, e.Name)
+	case prev == events.TypeCreated:
+		// Still a new ruleset at the end of the period
+	case prev == events.TypeDeleted && e.Type == events.TypeCreated:
+		c.changes[e.Name] = events.TypeUpdated
+	default:
+		c.changes[e.Name] = e.Type
+	}
+}
+
+// Digest returns the digest of the changes collected up to to, or nil when
+// there are no net changes. Collected changes are kept until Reset is called.
+func (c *Collector) Digest(to time.Time) *Digest {
+	if len(c.changes) == 0 {
+		return nil
+	}
+
+	d := &Digest{
+		Namespace: c.namespace,
+		From:      c.from,
+		To:        to.UTC(),
+		Mutations: c.mutations,
+		Created:   []string{},
+		Updated:   []string{},
+		Deleted:   []string{},
+	}
+	for name, changeType := range c.changes {
+		switch changeType {
+		case events.TypeCreated:
+			d.Created = append(d.Created, name)
+		case events.TypeUpdated:
+			d.Updated = append(d.Updated, name)
+		case events.TypeDeleted:
+			d.Deleted = append(d.Deleted, name)
+		}
+	}
+	slices.Sort(d.Created)
+	slices.Sort(d.Updated)
+	slices.Sort(d.Deleted)
+
+	d.Subject, d.Text = render(d)
+	return d
+}
+
+// Reset discards the collected changes and starts the next period at from
+func (c *Collector) Reset(from time.Time) {
+	c.from = from.UTC()
+	c.mutations = 0
+	c.changes = make(map[string]string)
+}
+
+// render formats the subject and plain-text body of d
+func render(d *Digest) (subject, text string) {
+	changed := len(d.Created) + len(d.Updated) + len(d.Deleted)
+	subject = fmt.Sprintf("[archivyr] %s: %d ruleset(s) changed", d.Namespace, changed)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Ruleset changes in %s from %s to %s (%d mutations)\n",
+		d.Namespace, d.From.Format(time.RFC3339), d.To.Format(time.RFC3339), d.Mutations)
+	for _, section := range []struct {
+		title string
+		names []string
+	}{
+		{"Created", d.Created},
+		{"Updated", d.Updated},
+		{"Deleted", d.Deleted},
+	} {
+		if len(section.names) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, name := range section.names {
+			fmt.Fprintf(&b, "  - %s\n", name)
+		}
+	}
+	return subject, b.String()
+}
+
+// Sender delivers digests
+type Sender interface {
+	Send(d *Digest) error
+}
+
+// Webhook posts digests as JSON to a URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a sender posting to url with a 10 second timeout
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Send posts d and fails unless the receiver answers with a 2xx status
+func (w *Webhook) Send(d *Digest) error {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("digest webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package digest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_Digest(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		events   []events.Event
+		expected *Digest
+	}{
+		{
+			name:     "no changes",
+			expected: nil,
+		},
+		{
+			name: "created then updated",
+			events: []events.Event{
+				{Type: events.TypeCreated, Name: "go_rules"},
+				{Type: events.TypeUpdated, Name: "go_rules"},
+			},
+			expected: &Digest{Mutations: 2, Created: []string{"go_rules"}, Updated: []string{}, Deleted: []string{}},
+		},
+		{
+			name: "created then deleted",
+			events: []events.Event{
+				{Type: events.TypeCreated, Name: "go_rules"},
+				{Type: events.TypeDeleted, Name: "go_rules"},
+			},
+			expected: nil,
+		},
+		{
+			name: "deleted then created",
+			events: []events.Event{
+				{Type: events.TypeDeleted, Name: "go_rules"},
+				{Type: events.TypeCreated, Name: "go_rules"},
+			},
+			expected: &Digest{Mutations: 2, Created: []string{}, Updated: []string{"go_rules"}, Deleted: []string{}},
+		},
+		{
+			name: "several rulesets",
+			events: []events.Event{
+				{Type: events.TypeUpdated, Name: "python_rules"},
+				{Type: events.TypeUpdated, Name: "go_rules"},
+				{Type: events.TypeUpdated, Name: "go_rules"},
+				{Type: events.TypeDeleted, Name: "old_rules"},
+				{Type: events.TypeMarked, Data: events.Marker{ID: "m1", Note: "release"}},
+			},
+			expected: &Digest{Mutations: 4, Created: []string{}, Updated: []string{"go_rules", "python_rules"}, Deleted: []string{"old_rules"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector("ruleset:", from)
+			for _, e := range tt.events {
+				c.Add(e)
+			}
+			d := c.Digest(to)
+			if tt.expected == nil {
+				assert.Nil(t, d)
+				return
+			}
+			require.NotNil(t, d)
+			assert.Equal(t, "ruleset:", d.Namespace)
+			assert.Equal(t, from, d.From)
+			assert.Equal(t, to, d.To)
+			assert.Equal(t, tt.expected.Mutations, d.Mutations)
+			assert.Equal(t, tt.expected.Created, d.Created)
+			assert.Equal(t, tt.expected.Updated, d.Updated)
+			assert.Equal(t, tt.expected.Deleted, d.Deleted)
+		})
+	}
+}
+
+func TestCollector_Reset(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCollector("ruleset:", from)
+	c.Add(events.Event{Type: events.TypeUpdated, Name: "go_rules"})
+
+	next := from.Add(24 * time.Hour)
+	require.NotNil(t, c.Digest(next))
+	require.NotNil(t, c.Digest(next), "changes are kept until reset")
+
+	c.Reset(next)
+	assert.Nil(t, c.Digest(next.Add(time.Hour)))
+
+	c.Add(events.Event{Type: events.TypeCreated, Name: "python_rules"})
+	d := c.Digest(next.Add(time.Hour))
+	require.NotNil(t, d)
+	assert.Equal(t, next, d.From)
+	assert.Equal(t, 1, d.Mutations)
+}
+
+func TestCollector_DigestText(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCollector("team-a:", from)
+	c.Add(events.Event{Type: events.TypeCreated, Name: "go_rules"})
+	c.Add(events.Event{Type: events.TypeDeleted, Name: "old_rules"})
+
+	d := c.Digest(from.Add(24 * time.Hour))
+	require.NotNil(t, d)
+	assert.Equal(t, "[archivyr] team-a:: 2 ruleset(s) changed", d.Subject)
+	assert.Equal(t, "Ruleset changes in team-a: from 2024-05-01T00:00:00Z to 2024-05-02T00:00:00Z (2 mutations)\n"+
+		"\nCreated:\n  - go_rules\n"+
+		"\nDeleted:\n  - old_rules\n", d.Text)
+}
+
+func TestWebhook_Send(t *testing.T) {
+	var received Digest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := &Digest{Namespace: "ruleset:", Mutations: 1, Updated: []string{"go_rules"}, Subject: "subject", Text: "text"}
+	require.NoError(t, NewWebhook(server.URL).Send(d))
+	assert.Equal(t, d.Updated, received.Updated)
+	assert.Equal(t, "subject", received.Subject)
+	assert.Equal(t, "text", received.Text)
+}
+
+func TestWebhook_SendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL).Send(&Digest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
@@ -0,0 +1,162 @@
+package dump
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+)
+
+// Comparison lists the rulesets added, removed and changed between two dumps,
+// for governance reviews. Its JSON form is the machine-readable summary.
+type Comparison struct {
+	// From and To are the creation times of the older and newer dump
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Added     []RulesetSummary `json:"added"`
+	Removed   []RulesetSummary `json:"removed"`
+	Changed   []RulesetChange  `json:"changed"`
+	Unchanged int              `json:"unchanged"`
+}
+
+// RulesetSummary identifies a ruleset present in only one of the dumps
+type RulesetSummary struct {
+	Name    string   `json:"name"`
+	Version int64    `json:"version"`
+	Tags    []string `json:"tags"`
+	Owner   string   `json:"owner,omitempty"`
+	Team    string   `json:"team,omitempty"`
+}
+
+// RulesetChange describes a ruleset whose content or metadata differs between the dumps
+type RulesetChange struct {
+	Name        string      `json:"name"`
+	FromVersion int64       `json:"from_version"`
+	ToVersion   int64       `json:"to_version"`
+	Diff        events.Diff `json:"diff"`
+}
+
+// Compare compares the rulesets of two dumps, in name order. A ruleset whose
+// version changed without any difference in content or metadata, e.g. after
+// an update writing the same values, counts as unchanged.
+func Compare(older, newer *Dump) *Comparison {
+	c := &Comparison{
+		From:    older.CreatedAt,
+		To:      newer.CreatedAt,
+		Added:   []RulesetSummary{},
+		Removed: []RulesetSummary{},
+		Changed: []RulesetChange{},
+	}
+
+	before := make(map[string]*ruleset.Ruleset, len(older.Rulesets))
+	for _, rs := range older.Rulesets {
+		before[rs.Name] = rs
+	}
+	after := make(map[string]*ruleset.Ruleset, len(newer.Rulesets))
+	for _, rs := range newer.Rulesets {
+		after[rs.Name] = rs
+	}
+
+	for _, rs := range newer.Rulesets {
+		prev, ok := before[rs.Name]
+		if !ok {
+			c.Added = append(c.Added, summarize(rs))
+			continue
+		}
+		diff := events.Summarize(prev, rs)
+		if len(diff.Fields) == 0 {
+			c.Unchanged++
+			continue
+		}
+		c.Changed = append(c.Changed, RulesetChange{Name: rs.Name, FromVersion: prev.Version, ToVersion: rs.Version, Diff: diff})
+	}
+	for _, rs := range older.Rulesets {
+		if _, ok := after[rs.Name]; !ok {
+			c.Removed = append(c.Removed, summarize(rs))
+		}
+	}
+
+	byName := func(a, b RulesetSummary) int { return strings.Compare(a.Name, b.Name) }
+	slices.SortFunc(c.Added, byName)
+	slices.SortFunc(c.Removed, byName)
+	slices.SortFunc(c.Changed, func(a, b RulesetChange) int { return strings.Compare(a.Name, b.Name) })
+	return c
+}
+
+// summarize returns the summary of a ruleset
+func summarize(rs *ruleset.Ruleset) RulesetSummary {
+	tags := rs.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return RulesetSummary{Name: rs.Name, Version: rs.Version, Tags: tags, Owner: rs.Owner, Team: rs.Team}
+}
+
+// WriteText writes c to w as a human-readable report
+func (c *Comparison) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rulesets changed between the dump of %s and the dump of %s\n",
+		c.From.Format(time.RFC3339), c.To.Format(time.RFC3339))
+
+	writeSummaries(&b, "Added", "+", c.Added)
+	writeSummaries(&b, "Removed", "-", c.Removed)
+	if len(c.Changed) > 0 {
+		fmt.Fprintf(&b, "\nChanged (%d):\n", len(c.Changed))
+		for _, change := range c.Changed {
+			fmt.Fprintf(&b, "  ~ %s (version %d -> %d): %s\n", change.Name, change.FromVersion, change.ToVersion, describeDiff(change.Diff))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%d added, %d removed, %d changed, %d unchanged\n", len(c.Added), len(c.Removed), len(c.Changed), c.Unchanged)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeSummaries writes a titled list of rulesets, or nothing when there are none
+func writeSummaries(b *strings.Builder, title, marker string, rulesets []RulesetSummary) {
+	if len(rulesets) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s (%d):\n", title, len(rulesets))
+	for _, rs := range rulesets {
+		fmt.Fprintf(b, "  %s %s (version %d", marker, rs.Name, rs.Version)
+		if len(rs.Tags) > 0 {
+			fmt.Fprintf(b, ", tags %s", strings.Join(rs.Tags, ", "))
+		}
+		if rs.Owner != "" {
+			fmt.Fprintf(b, ", owner %s", rs.Owner)
+		}
+		b.WriteString(")\n")
+	}
+}
+
+// describeDiff returns the changed fields, with line and tag counts where they apply
+func describeDiff(d events.Diff) string {
+	parts := make([]string, 0, len(d.Fields))
+	for _, field := range d.Fields {
+		switch field {
+		case "content":
+			parts = append(parts, fmt.Sprintf("content (+%d -%d lines)", d.LinesAdded, d.LinesRemoved))
+		case "tags":
+			var tags []string
+			for _, tag := range d.TagsAdded {
+				tags = append(tags, "+"+tag)
+			}
+			for _, tag := range d.TagsRemoved {
+				tags = append(tags, "-"+tag)
+			}
+			if len(tags) == 0 {
+				parts = append(parts, "tags (reordered)")
+			} else {
+				parts = append(parts, fmt.Sprintf("tags (%s)", strings.Join(tags, " ")))
+			}
+		default:
+			parts = append(parts, field)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,90 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	older := &Dump{
+		CreatedAt: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		Rulesets: []*ruleset.Ruleset{
+			{Name: "go_rules", Version: 2, Tags: []string{"go", "style"}, Markdown: "# Go\nUse gofmt.\n"},
+			{Name: "legacy_rules", Version: 5, Tags: []string{"legacy"}, Owner: "alice"},
+			{Name: "py_rules", Version: 1, Description: "Python", Markdown: "# Python\n"},
+			{Name: "touched", Version: 1, Description: "Same"},
+		},
+	}
+	newer := &Dump{
+		CreatedAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+		Rulesets: []*ruleset.Ruleset{
+			{Name: "api_schema", Version: 1, ContentType: ruleset.ContentTypeJSONSchema},
+			{Name: "go_rules", Version: 4, Tags: []string{"go", "testing"}, Markdown: "# Go\nUse gofmt.\nRun go vet.\n"},
+			{Name: "py_rules", Version: 1, Description: "Python", Markdown: "# Python\n"},
+			{Name: "touched", Version: 2, Description: "Same"},
+		},
+	}
+
+	c := Compare(older, newer)
+
+	assert.Equal(t, []RulesetSummary{{Name: "api_schema", Version: 1, Tags: []string{}}}, c.Added)
+	assert.Equal(t, []RulesetSummary{{Name: "legacy_rules", Version: 5, Tags: []string{"legacy"}, Owner: "alice"}}, c.Removed)
+	require.Len(t, c.Changed, 1)
+	assert.Equal(t, RulesetChange{
+		Name:        "go_rules",
+		FromVersion: 2,
+		ToVersion:   4,
+		Diff: events.Diff{
+			Fields:      []string{"tags", "content"},
+			TagsAdded:   []string{"testing"},
+			TagsRemoved: []string{"style"},
+			LinesAdded:  1,
+		},
+	}, c.Changed[0])
+	assert.Equal(t, 2, c.Unchanged)
+
+	var text bytes.Buffer
+	require.NoError(t, c.WriteText(&text))
+	assert.Equal(t, `Rulesets changed between the dump of 2025-10-01T00:00:00Z and the dump of 2025-11-01T00:00:00Z
+
+Added (1):
+  + api_schema (version 1)
+
+Removed (1):
+  - legacy_rules (version 5, tags legacy, owner alice)
+
+Changed (1):
+  ~ go_rules (version 2 -> 4): tags (+testing -style), content (+1 -0 lines)
+
+1 added, 1 removed, 1 changed, 2 unchanged
+`, text.String())
+
+	encoded, err := json.Marshal(c)
+	require.NoError(t, err)
+	var decoded Comparison
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, *c, decoded)
+}
+
+func TestCompare_Identical(t *testing.T) {
+	d := &Dump{Rulesets: []*ruleset.Ruleset{{Name: "go_rules", Version: 1}}}
+
+	c := Compare(d, d)
+
+	assert.Empty(t, c.Added)
+	assert.Empty(t, c.Removed)
+	assert.Empty(t, c.Changed)
+	assert.Equal(t, 1, c.Unchanged)
+
+	// Empty lists encode as [] so consumers need not handle null
+	encoded, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"added":[],"removed":[],"changed":[]`)
+}
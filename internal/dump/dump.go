@@ -0,0 +1,143 @@
+// Package dump exports and loads logical dumps of the corpus: versioned JSON
+// holding every ruleset, prompt template and snippet. Unlike Valkey's RDB and
+// AOF files, dumps do not depend on the storage backend or its version.
+package dump
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+)
+
+// FormatVersion is the version of the dump format written by Export. It is
+// bumped, along with SchemaID, on incompatible changes.
+const FormatVersion = 1
+
+// SchemaID identifies the JSON Schema describing dumps of FormatVersion
+const SchemaID = "urn:archivyr:schema:dump:v1"
+
+// Schema is the JSON Schema document describing a dump
+//
+//go:embed schema.json
+var Schema []byte
+
+// Dump is a logical copy of the corpus
+type Dump struct {
+	Schema        string             `json:"$schema"`
+	FormatVersion int                `json:"format_version"`
+	CreatedAt     time.Time          `json:"created_at"`
+	Rulesets      []*ruleset.Ruleset `json:"rulesets"`
+	Templates     []*prompt.Template `json:"templates,omitempty"`
+	Snippets      []*snippet.Snippet `json:"snippets,omitempty"`
+}
+
+// RulesetRestorer writes complete rulesets, keeping their timestamps and versions
+type RulesetRestorer interface {
+	Restore(rs *ruleset.Ruleset) (created bool, err error)
+}
+
+// Result summarises a loaded dump
+type Result struct {
+	Rulesets  int
+	Templates int
+	Snippets  int
+}
+
+// Export reads the corpus into a dump. The prompt and snippet services are
+// optional. Entities are sorted by name so equal corpora give equal dumps.
+func Export(rulesets ruleset.ServiceInterface, prompts prompt.ServiceInterface, snippets snippet.ServiceInterface, now time.Time) (*Dump, error) {
+	d := &Dump{Schema: SchemaID, FormatVersion: FormatVersion, CreatedAt: now.UTC()}
+
+	var err error
+	if d.Rulesets, err = rulesets.List(); err != nil {
+		return nil, fmt.Errorf("failed to list rulesets: %w", err)
+	}
+	slices.SortFunc(d.Rulesets, func(a, b *ruleset.Ruleset) int { return strings.Compare(a.Name, b.Name) })
+
+	if prompts != nil {
+		if d.Templates, err = prompts.List(); err != nil {
+			return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+		}
+		slices.SortFunc(d.Templates, func(a, b *prompt.Template) int { return strings.Compare(a.Name, b.Name) })
+	}
+
+	if snippets != nil {
+		if d.Snippets, err = snippets.List(""); err != nil {
+			return nil, fmt.Errorf("failed to list snippets: %w", err)
+		}
+		slices.SortFunc(d.Snippets, func(a, b *snippet.Snippet) int { return strings.Compare(a.Name, b.Name) })
+	}
+	return d, nil
+}
+
+// Encode writes d to w as indented JSON
+func Encode(w io.Writer, d *Dump) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("failed to encode dump: %w", err)
+	}
+	return nil
+}
+
+// Decode reads a dump from r, rejecting other formats and unknown fields
+func Decode(r io.Reader) (*Dump, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var d Dump
+	if err := dec.Decode(&d); err != nil {
+		return nil, fmt.Errorf("failed to decode dump: %w", err)
+	}
+	if d.Schema != SchemaID {
+		return nil, fmt.Errorf("not an archivyr dump: $schema is '%s' (expected '%s')", d.Schema, SchemaID)
+	}
+	if d.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported dump format version %d (expected %d)", d.FormatVersion, FormatVersion)
+	}
+	return &d, nil
+}
+
+// Load writes every entity of d, replacing stored entities of the same name.
+// Rulesets keep their timestamps and versions; prompt templates and snippets
+// are saved as if newly written, so their timestamps are those of the load.
+// The prompt and snippet services are optional, but d must not hold entities
+// of a kind without a service. Snippets are loaded first so rulesets that
+// include them are complete as soon as they are written.
+func Load(d *Dump, rulesets RulesetRestorer, prompts prompt.ServiceInterface, snippets snippet.ServiceInterface) (*Result, error) {
+	if len(d.Templates) > 0 && prompts == nil {
+		return nil, fmt.Errorf("dump contains prompt templates but no prompt service is available")
+	}
+	if len(d.Snippets) > 0 && snippets == nil {
+		return nil, fmt.Errorf("dump contains snippets but no snippet service is available")
+	}
+
+	result := &Result{}
+	for _, sn := range d.Snippets {
+		if _, err := snippets.Save(sn); err != nil {
+			return result, fmt.Errorf("failed to load snippet '%s': %w", sn.Name, err)
+		}
+		result.Snippets++
+	}
+	for _, t := range d.Templates {
+		if _, err := prompts.Save(t); err != nil {
+			return result, fmt.Errorf("failed to load prompt template '%s': %w", t.Name, err)
+		}
+		result.Templates++
+	}
+	for _, rs := range d.Rulesets {
+		if _, err := rulesets.Restore(rs); err != nil {
+			return result, fmt.Errorf("failed to load ruleset '%s': %w", rs.Name, err)
+		}
+		result.Rulesets++
+	}
+	return result, nil
+}
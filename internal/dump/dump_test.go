@@ -0,0 +1,106 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump_RoundTrip(t *testing.T) {
+	source := memstore.New()
+	rulesets := ruleset.NewService(source)
+	require.NoError(t, rulesets.Create(&ruleset.Ruleset{Name: "py_rules", Description: "Python", Markdown: "# Python"}))
+	require.NoError(t, rulesets.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go", Tags: []string{"go"}, Markdown: "# Go\n{{snippet:no_tabs}}"}))
+	changed := "Go rules v2"
+	require.NoError(t, rulesets.Update("go_rules", &ruleset.Update{Description: &changed}))
+	_, err := snippet.NewService(source).Save(&snippet.Snippet{Name: "no_tabs", Text: "Use gofmt."})
+	require.NoError(t, err)
+	_, err = prompt.NewService(source).Save(&prompt.Template{Name: "review", Template: "Review {{code}}", Variables: []prompt.Variable{{Name: "code", Required: true}}})
+	require.NoError(t, err)
+
+	d, err := Export(rulesets, prompt.NewService(source), snippet.NewService(source), time.Now())
+	require.NoError(t, err)
+	require.Len(t, d.Rulesets, 2)
+	assert.Equal(t, "go_rules", d.Rulesets[0].Name)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, d))
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+
+	// Load into a target backed by a different store, with blob storage enabled
+	target := memstore.New()
+	targetRulesets := ruleset.NewService(target, ruleset.WithBlobStorage())
+	result, err := Load(decoded, targetRulesets, prompt.NewService(target), snippet.NewService(target))
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Rulesets: 2, Templates: 1, Snippets: 1}, result)
+
+	original, err := rulesets.Get("go_rules")
+	require.NoError(t, err)
+	loaded, err := targetRulesets.Get("go_rules")
+	require.NoError(t, err)
+	assert.Equal(t, original.Markdown, loaded.Markdown)
+	assert.Equal(t, int64(2), loaded.Version)
+	assert.True(t, original.LastModified.Equal(loaded.LastModified))
+
+	tmpl, err := prompt.NewService(target).Get("review")
+	require.NoError(t, err)
+	assert.Equal(t, "Review {{code}}", tmpl.Template)
+	sn, err := snippet.NewService(target).Get("no_tabs")
+	require.NoError(t, err)
+	assert.Equal(t, "Use gofmt.", sn.Text)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"not json", "RDB", "failed to decode dump"},
+		{"other schema", `{"$schema": "urn:other", "format_version": 1, "rulesets": []}`, "not an archivyr dump"},
+		{"future version", `{"$schema": "` + SchemaID + `", "format_version": 2, "rulesets": []}`, "unsupported dump format version 2"},
+		{"unknown field", `{"$schema": "` + SchemaID + `", "format_version": 1, "rulesets": [], "extra": true}`, "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestLoad_MissingService(t *testing.T) {
+	d := &Dump{Snippets: []*snippet.Snippet{{Name: "no_tabs", Text: "Use gofmt."}}}
+	_, err := Load(d, ruleset.NewService(memstore.New()), nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no snippet service")
+}
+
+func TestSchema_MatchesFormat(t *testing.T) {
+	var schema struct {
+		ID         string         `json:"$id"`
+		Properties map[string]any `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(Schema, &schema))
+	assert.Equal(t, SchemaID, schema.ID)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, &Dump{Schema: SchemaID, FormatVersion: FormatVersion, Templates: []*prompt.Template{{}}, Snippets: []*snippet.Snippet{{}}}))
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	for field := range fields {
+		assert.Contains(t, schema.Properties, field)
+	}
+}
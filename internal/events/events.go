@@ -0,0 +1,79 @@
+// Package events defines the ruleset change events shared by every
+// integration point, such as the server-sent events feed of the HTTP interface
+// and change digests, and broadcasts them to live subscribers.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+)
+
+// Types of change events
+const (
+	TypeCreated = "created"
+	TypeUpdated = "updated"
+	TypeDeleted = "deleted"
+	// TypeMarked is a checkpoint annotated by a client rather than a change
+	TypeMarked = "marked"
+)
+
+// subscriberBuffer is the number of events buffered per subscriber; events for
+// a subscriber that falls further behind are dropped
+const subscriberBuffer = 64
+
+// Broker fans events out to subscribers. Publishing never blocks on a slow subscriber.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	clock       func() time.Time
+}
+
+// NewBroker creates a broker without subscribers
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[chan Event]struct{}),
+		clock:       time.Now,
+	}
+}
+
+// Subscribe returns a channel receiving events published from now on, and a
+// function ending the subscription, which closes the channel
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends an event to every subscriber with room in its buffer
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Hooks returns ruleset service hooks publishing an event for every successful mutation
+func (b *Broker) Hooks() ruleset.Hooks {
+	return ruleset.Hooks{
+		AfterCreate: func(rs *ruleset.Ruleset) { b.Publish(Created(rs, b.clock())) },
+		AfterUpdate: func(previous, updated *ruleset.Ruleset) { b.Publish(Updated(previous, updated, b.clock())) },
+		AfterDelete: func(name string) { b.Publish(Deleted(name, b.clock())) },
+	}
+}
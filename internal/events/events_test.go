@@ -0,0 +1,123 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_Hooks(t *testing.T) {
+	broker := NewBroker()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	broker.clock = func() time.Time { return now }
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	service := ruleset.NewService(memstore.New(), ruleset.WithHooks(broker.Hooks()))
+	require.NoError(t, service.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go", Tags: []string{"go"}, Markdown: "# Go"}))
+	markdown := "# Go\n\nUse gofmt."
+	require.NoError(t, service.Update("go_rules", &ruleset.Update{Markdown: &markdown}))
+	require.NoError(t, service.Delete("go_rules"))
+
+	created := <-ch
+	assert.Equal(t, TypeCreated, created.Type)
+	assert.Equal(t, "go_rules", created.Name)
+	assert.Equal(t, now, created.Time)
+	assert.Equal(t, SchemaVersion, created.Schema)
+	require.IsType(t, RulesetCreated{}, created.Data)
+	assert.Equal(t, int64(1), created.Data.(RulesetCreated).Version)
+	assert.Equal(t, []string{"go"}, created.Data.(RulesetCreated).Tags)
+
+	updated := <-ch
+	assert.Equal(t, TypeUpdated, updated.Type)
+	require.IsType(t, RulesetUpdated{}, updated.Data)
+	data := updated.Data.(RulesetUpdated)
+	assert.Equal(t, int64(2), data.Version)
+	assert.Equal(t, int64(1), data.PreviousVersion)
+	assert.Equal(t, Diff{Fields: []string{"content"}, LinesAdded: 2}, data.Diff)
+
+	assert.Equal(t, Event{Type: TypeDeleted, Name: "go_rules", Time: now, Schema: SchemaVersion, Data: RulesetDeleted{}}, <-ch)
+}
+
+func TestSummarize(t *testing.T) {
+	previous := &ruleset.Ruleset{Description: "Go", Tags: []string{"go", "style"}, Markdown: "# Go\nA\nB", Owner: "alice"}
+
+	tests := []struct {
+		name    string
+		updated ruleset.Ruleset
+		want    Diff
+	}{
+		{
+			name:    "unchanged",
+			updated: *previous,
+			want:    Diff{Fields: []string{}},
+		},
+		{
+			name:    "tags",
+			updated: ruleset.Ruleset{Description: "Go", Tags: []string{"go", "lint"}, Markdown: "# Go\nA\nB", Owner: "alice"},
+			want:    Diff{Fields: []string{"tags"}, TagsAdded: []string{"lint"}, TagsRemoved: []string{"style"}},
+		},
+		{
+			name:    "content and owner",
+			updated: ruleset.Ruleset{Description: "Go", Tags: []string{"go", "style"}, Markdown: "# Go\nB\nA\nC", Owner: "bob"},
+			want:    Diff{Fields: []string{"content", "owner"}, LinesAdded: 1},
+		},
+		{
+			name:    "replaced line",
+			updated: ruleset.Ruleset{Description: "Go", Tags: []string{"go", "style"}, Markdown: "# Go\nA\nD", Owner: "alice"},
+			want:    Diff{Fields: []string{"content"}, LinesAdded: 1, LinesRemoved: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Summarize(previous, &tt.updated))
+		})
+	}
+}
+
+func TestEvent_JSONRoundTrip(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	previous := &ruleset.Ruleset{Name: "go_rules", Version: 1, Markdown: "# Go"}
+	updated := &ruleset.Ruleset{Name: "go_rules", Version: 2, Markdown: "# Go\nUse gofmt."}
+
+	for _, e := range []Event{
+		Created(previous, now),
+		Updated(previous, updated, now),
+		Deleted("go_rules", now),
+		Marked("0b6c2f4e", "before refactor experiment", now),
+	} {
+		b, err := json.Marshal(e)
+		require.NoError(t, err)
+		var decoded Event
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, e, decoded)
+	}
+
+	var e Event
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"type":"renamed","name":"go_rules","data":{}}`), &e), "unknown event type")
+}
+
+func TestBroker_SlowSubscriberDoesNotBlock(t *testing.T) {
+	broker := NewBroker()
+	slow, unsubscribeSlow := broker.Subscribe()
+	defer unsubscribeSlow()
+
+	for range subscriberBuffer + 10 {
+		broker.Publish(Event{Type: TypeUpdated, Name: "go_rules"})
+	}
+	assert.Len(t, slow, subscriberBuffer)
+
+	// Ending a subscription closes its channel and stops delivery
+	ch, unsubscribe := broker.Subscribe()
+	unsubscribe()
+	unsubscribe()
+	broker.Publish(Event{Type: TypeDeleted, Name: "go_rules"})
+	_, open := <-ch
+	assert.False(t, open)
+}
@@ -0,0 +1,213 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+)
+
+// SchemaVersion is the version of the event schema. Fields may be added
+// within a version; removing or changing one requires a new version.
+const SchemaVersion = 1
+
+// Event describes a change to a ruleset. Data holds the payload of the event
+// type: a RulesetCreated, RulesetUpdated, RulesetDeleted or Marker. Name is
+// empty for markers.
+type Event struct {
+	Type   string    `json:"type"`
+	Name   string    `json:"name"`
+	Time   time.Time `json:"time"`
+	Schema int       `json:"schema"`
+	Data   Payload   `json:"data,omitempty"`
+}
+
+// Payload is the type-specific part of an event
+type Payload interface {
+	eventType() string
+}
+
+// RulesetCreated is the payload of a created event
+type RulesetCreated struct {
+	Version     int64    `json:"version"`
+	Checksum    string   `json:"checksum"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	ContentType string   `json:"content_type"`
+	Owner       string   `json:"owner,omitempty"`
+	Team        string   `json:"team,omitempty"`
+}
+
+// RulesetUpdated is the payload of an updated event
+type RulesetUpdated struct {
+	Version         int64  `json:"version"`
+	PreviousVersion int64  `json:"previous_version"`
+	Checksum        string `json:"checksum"`
+	Diff            Diff   `json:"diff"`
+}
+
+// RulesetDeleted is the payload of a deleted event
+type RulesetDeleted struct{}
+
+// Marker is the payload of a marked event, a checkpoint such as "before
+// refactor experiment" that later queries can be bracketed by
+type Marker struct {
+	ID   string `json:"id"`
+	Note string `json:"note"`
+}
+
+func (RulesetCreated) eventType() string { return TypeCreated }
+func (RulesetUpdated) eventType() string { return TypeUpdated }
+func (RulesetDeleted) eventType() string { return TypeDeleted }
+func (Marker) eventType() string         { return TypeMarked }
+
+// Diff summarises what an update changed
+type Diff struct {
+	// Fields lists the changed fields, with "content" for the body
+	Fields       []string `json:"fields"`
+	TagsAdded    []string `json:"tags_added,omitempty"`
+	TagsRemoved  []string `json:"tags_removed,omitempty"`
+	LinesAdded   int      `json:"lines_added"`
+	LinesRemoved int      `json:"lines_removed"`
+}
+
+// Created returns the event for a created ruleset
+func Created(rs *ruleset.Ruleset, at time.Time) Event {
+	return newEvent(rs.Name, at, RulesetCreated{
+		Version:     rs.Version,
+		Checksum:    rs.Checksum(),
+		Description: rs.Description,
+		Tags:        orEmpty(rs.Tags),
+		ContentType: rs.ContentType,
+		Owner:       rs.Owner,
+		Team:        rs.Team,
+	})
+}
+
+// Updated returns the event for a ruleset updated from previous to updated
+func Updated(previous, updated *ruleset.Ruleset, at time.Time) Event {
+	return newEvent(updated.Name, at, RulesetUpdated{
+		Version:         updated.Version,
+		PreviousVersion: previous.Version,
+		Checksum:        updated.Checksum(),
+		Diff:            Summarize(previous, updated),
+	})
+}
+
+// Deleted returns the event for a deleted ruleset
+func Deleted(name string, at time.Time) Event {
+	return newEvent(name, at, RulesetDeleted{})
+}
+
+// Marked returns the event for a marker
+func Marked(id, note string, at time.Time) Event {
+	return newEvent("", at, Marker{ID: id, Note: note})
+}
+
+// newEvent wraps a payload in an event
+func newEvent(name string, at time.Time, data Payload) Event {
+	return Event{Type: data.eventType(), Name: name, Time: at.UTC(), Schema: SchemaVersion, Data: data}
+}
+
+// Summarize compares two states of a ruleset. Lines are compared as multisets,
+// so a moved line counts as neither added nor removed.
+func Summarize(previous, updated *ruleset.Ruleset) Diff {
+	diff := Diff{Fields: []string{}}
+	for _, field := range []struct {
+		name          string
+		before, after string
+	}{
+		{"description", previous.Description, updated.Description},
+		{"tags", strings.Join(previous.Tags, "\x00"), strings.Join(updated.Tags, "\x00")},
+		{"content_type", previous.ContentType, updated.ContentType},
+		{"license", previous.License, updated.License},
+		{"content", previous.Markdown, updated.Markdown},
+		{"source_url", previous.SourceURL, updated.SourceURL},
+		{"owner", previous.Owner, updated.Owner},
+		{"team", previous.Team, updated.Team},
+	} {
+		if field.before != field.after {
+			diff.Fields = append(diff.Fields, field.name)
+		}
+	}
+
+	for _, tag := range updated.Tags {
+		if !slices.Contains(previous.Tags, tag) {
+			diff.TagsAdded = append(diff.TagsAdded, tag)
+		}
+	}
+	for _, tag := range previous.Tags {
+		if !slices.Contains(updated.Tags, tag) {
+			diff.TagsRemoved = append(diff.TagsRemoved, tag)
+		}
+	}
+
+	if previous.Markdown != updated.Markdown {
+		counts := make(map[string]int)
+		for _, line := range strings.Split(previous.Markdown, "\n") {
+			counts[line]++
+		}
+		for _, line := range strings.Split(updated.Markdown, "\n") {
+			counts[line]--
+		}
+		for _, n := range counts {
+			if n > 0 {
+				diff.LinesRemoved += n
+			} else {
+				diff.LinesAdded -= n
+			}
+		}
+	}
+	return diff
+}
+
+// orEmpty returns tags, or an empty slice so it encodes as [] rather than null
+func orEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// UnmarshalJSON decodes an event, decoding its data as the payload of its type
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type envelope Event
+	var raw struct {
+		envelope
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = Event(raw.envelope)
+	if len(raw.Data) == 0 {
+		return nil
+	}
+
+	var err error
+	switch e.Type {
+	case TypeCreated:
+		e.Data, err = decodePayload[RulesetCreated](raw.Data)
+	case TypeUpdated:
+		e.Data, err = decodePayload[RulesetUpdated](raw.Data)
+	case TypeDeleted:
+		e.Data, err = decodePayload[RulesetDeleted](raw.Data)
+	case TypeMarked:
+		e.Data, err = decodePayload[Marker](raw.Data)
+	default:
+		return fmt.Errorf("unknown event type '%s'", e.Type)
+	}
+	return err
+}
+
+// decodePayload decodes the data of an event as a payload of type P
+func decodePayload[P Payload](data json.RawMessage) (Payload, error) {
+	var p P
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid event data: %w", err)
+	}
+	return p, nil
+}
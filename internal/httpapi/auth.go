@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authRealm names the protection space in basic authentication challenges
+const authRealm = "archivyr"
+
+// authorized reports whether r carries the access token, either as a bearer
+// token or as the basic authentication password so browsers can prompt for
+// it. Requests are never authorized when no token is set.
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return false
+	}
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		_, presented, ok = r.BasicAuth()
+	}
+	return ok && subtle.ConstantTimeCompare([]byte(presented), []byte(h.token)) == 1
+}
+
+// requireToken only serves requests carrying the access token
+func (h *Handler) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+authRealm+`"`)
+			http.Error(w, "missing or invalid access token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// protectedWhenUI requires the access token once the web UI is enabled and
+// serves requests freely otherwise, for endpoints that predate the UI
+func (h *Handler) protectedWhenUI(next http.Handler) http.Handler {
+	protected := h.requireToken(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
+}
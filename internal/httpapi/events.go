@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/rs/zerolog/log"
+)
+
+// keepAliveInterval is how often an idle event stream receives a comment, so
+// proxies do not close it
+const keepAliveInterval = 30 * time.Second
+
+// WithEvents streams the broker's change events as server-sent events at GET /events.
+// With the web UI enabled the stream requires its access token.
+func WithEvents(broker *events.Broker) Option {
+	return func(h *Handler) {
+		h.mux.Handle("GET /events", h.protectedWhenUI(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			streamEvents(w, r, broker)
+		})))
+	}
+}
+
+// streamEvents writes each event as "event: {type}" with the JSON event as data
+// until the client disconnects
+func streamEvents(w http.ResponseWriter, r *http.Request, broker *events.Broker) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// An initial comment lets clients see the stream is open
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Debug().Err(err).Msg("Failed to encode change event")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
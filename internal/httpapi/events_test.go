@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents_Stream(t *testing.T) {
+	broker := events.NewBroker()
+	service := ruleset.NewService(memstore.New(), ruleset.WithHooks(broker.Hooks()))
+	server := httptest.NewServer(NewHandler(service, WithEvents(broker)))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	// The subscription exists once the connection comment arrives
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": connected\n", line)
+	_, err = reader.ReadString('\n')
+	require.NoError(t, err)
+
+	require.NoError(t, service.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go", Markdown: "# Go"}))
+
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: created\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, `data: {"type":"created","name":"go_rules","time":`), line)
+}
+
+func TestEvents_Disabled(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	assert.Equal(t, http.StatusNotFound, get(h, "/events", "").Code)
+}
@@ -0,0 +1,113 @@
+// Package httpapi provides an HTTP interface to stored rulesets: read-only
+// content and schema endpoints, and an optional web UI for curating them.
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// Handler serves ruleset content over HTTP
+type Handler struct {
+	service ruleset.ServiceInterface
+	mux     *http.ServeMux
+	// token is the access token of the web UI, empty while it is disabled
+	token string
+}
+
+// NewHandler creates an HTTP handler serving GET /rulesets/{name} and GET /schemas/{name}.json
+func NewHandler(service ruleset.ServiceInterface, opts ...Option) *Handler {
+	h := &Handler{
+		service: service,
+		mux:     http.NewServeMux(),
+	}
+	// The web UI's token protects this content too, so it cannot be read around the UI
+	h.mux.Handle("GET /rulesets/{name}", h.protectedWhenUI(http.HandlerFunc(h.getRuleset)))
+	h.mux.Handle("GET /schemas/{file}", h.protectedWhenUI(http.HandlerFunc(h.getSchema)))
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// getRuleset writes the ruleset body, or 304 Not Modified when the client's
+// If-None-Match already names the current checksum
+func (h *Handler) getRuleset(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := validation.ValidateRulesetName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs, err := h.service.Get(name)
+	if err != nil {
+		if exists, existsErr := h.service.Exists(name); existsErr == nil && !exists {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Str("name", name).Msg("Failed to serve ruleset over HTTP")
+		http.Error(w, "failed to retrieve ruleset", http.StatusInternalServerError)
+		return
+	}
+
+	etag := `"` + rs.Checksum() + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if !rs.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", rs.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", rs.ContentType+"; charset=utf-8")
+	if _, err := w.Write([]byte(rs.Markdown)); err != nil {
+		log.Debug().Err(err).Str("name", name).Msg("Failed to write ruleset response")
+	}
+}
+
+// getSchema writes the Ruleset or Update JSON Schema
+func (h *Handler) getSchema(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("file"), ".json")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	schema, err := ruleset.Schema(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", ruleset.ContentTypeJSONSchema)
+	if _, err := w.Write(schema); err != nil {
+		log.Debug().Err(err).Str("schema", name).Msg("Failed to write schema response")
+	}
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison RFC 9110 requires for If-None-Match
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) (*Handler, *ruleset.Service) {
+	service := ruleset.NewService(memstore.New())
+	require.NoError(t, service.Create(&ruleset.Ruleset{
+		Name:        "go_style",
+		Description: "Go style",
+		Markdown:    "# Go Style",
+	}))
+	return NewHandler(service), service
+}
+
+func get(h http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetRuleset_ETag(t *testing.T) {
+	h, service := newTestHandler(t)
+
+	rec := get(h, "/rulesets/go_style", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "# Go Style", rec.Body.String())
+	assert.Equal(t, "text/markdown; charset=utf-8", rec.Header().Get("Content-Type"))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEmpty(t, rec.Header().Get("Last-Modified"))
+
+	// The current ETag yields 304 without a body
+	rec = get(h, "/rulesets/go_style", etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+	rec = get(h, "/rulesets/go_style", `"stale", W/`+etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	// Metadata-only changes keep the ETag; content changes replace it
+	description := "Updated"
+	require.NoError(t, service.Update("go_style", &ruleset.Update{Description: &description}))
+	assert.Equal(t, http.StatusNotModified, get(h, "/rulesets/go_style", etag).Code)
+
+	markdown := "# Go Style v2"
+	require.NoError(t, service.Update("go_style", &ruleset.Update{Markdown: &markdown}))
+	rec = get(h, "/rulesets/go_style", etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, markdown, rec.Body.String())
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestGetRuleset_Errors(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	assert.Equal(t, http.StatusNotFound, get(h, "/rulesets/missing", "").Code)
+	assert.Equal(t, http.StatusBadRequest, get(h, "/rulesets/Bad-Name", "").Code)
+	assert.Equal(t, http.StatusNotFound, get(h, "/other", "").Code)
+}
+
+func TestGetSchema(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	rec := get(h, "/schemas/ruleset.json", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ruleset.ContentTypeJSONSchema, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"$id": "urn:archivyr:schema:ruleset:v1"`)
+
+	assert.Equal(t, http.StatusOK, get(h, "/schemas/update.json", "").Code)
+	assert.Equal(t, http.StatusNotFound, get(h, "/schemas/pack.json", "").Code)
+	assert.Equal(t, http.StatusNotFound, get(h, "/schemas/ruleset", "").Code)
+}
+
+func TestEtagMatches(t *testing.T) {
+	testCases := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{`*`, true},
+		{`"abcd"`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			assert.Equal(t, tc.want, etagMatches(tc.header, `"abc"`))
+		})
+	}
+}
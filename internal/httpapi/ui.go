@@ -0,0 +1,194 @@
+package httpapi
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+// maxEditBytes bounds the size of an edit request body
+const maxEditBytes = 4 << 20
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithUI serves the embedded web UI at /ui/ and the JSON API it uses under
+// /api/. Since the API can modify rulesets, the UI, the API, the event stream
+// and the ruleset and schema endpoints only serve requests carrying token; an
+// empty token refuses them all.
+func WithUI(token string) Option {
+	return func(h *Handler) {
+		ui, err := fs.Sub(uiFiles, "ui")
+		if err != nil {
+			panic(err)
+		}
+		h.token = token
+		h.mux.Handle("GET /ui/", h.requireToken(http.StripPrefix("/ui/", http.FileServerFS(ui))))
+		h.mux.Handle("GET /api/rulesets", h.requireToken(http.HandlerFunc(h.listRulesets)))
+		h.mux.Handle("GET /api/rulesets/{name}", h.requireToken(http.HandlerFunc(h.getRulesetJSON)))
+		h.mux.Handle("PUT /api/rulesets/{name}", h.requireToken(http.HandlerFunc(h.putRuleset)))
+	}
+}
+
+// rulesetSummary is a ruleset without its body, as listed by the UI
+type rulesetSummary struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Tags         []string `json:"tags"`
+	ContentType  string   `json:"content_type"`
+	Version      int64    `json:"version"`
+	LastModified string   `json:"last_modified"`
+}
+
+// rulesetEdit is the body of a PUT /api/rulesets/{name} request
+type rulesetEdit struct {
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	ContentType string   `json:"content_type"`
+	Markdown    string   `json:"markdown"`
+}
+
+// listRulesets writes the rulesets matching the q (text terms) and tag query parameters in name order
+func (h *Handler) listRulesets(w http.ResponseWriter, r *http.Request) {
+	filter := ruleset.Filter{Pattern: "*", Text: strings.Fields(r.URL.Query().Get("q"))}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		filter.Tags = []string{tag}
+	}
+
+	rulesets, err := h.service.Find(filter)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list rulesets over HTTP")
+		http.Error(w, "failed to list rulesets", http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]rulesetSummary, 0, len(rulesets))
+	for _, rs := range rulesets {
+		summaries = append(summaries, rulesetSummary{
+			Name:         rs.Name,
+			Description:  rs.Description,
+			Tags:         rs.Tags,
+			ContentType:  rs.ContentType,
+			Version:      rs.Version,
+			LastModified: validation.FormatTimestamp(rs.LastModified),
+		})
+	}
+	sortSummaries(summaries)
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// getRulesetJSON writes a ruleset with its metadata as JSON
+func (h *Handler) getRulesetJSON(w http.ResponseWriter, r *http.Request) {
+	rs, ok := h.lookup(w, r.PathValue("name"))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, rs)
+}
+
+// putRuleset creates or replaces a ruleset from a rulesetEdit body. It answers
+// 201 when the ruleset was created, 200 when it was updated and 202 when the
+// change was queued because storage is unavailable.
+func (h *Handler) putRuleset(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := validation.ValidateRulesetName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var edit rulesetEdit
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEditBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&edit); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if edit.Tags == nil {
+		edit.Tags = []string{}
+	}
+
+	rs := &ruleset.Ruleset{
+		Name:        name,
+		Description: edit.Description,
+		Tags:        edit.Tags,
+		ContentType: edit.ContentType,
+		Markdown:    edit.Markdown,
+	}
+	updates := &ruleset.Update{
+		Description: &edit.Description,
+		Tags:        &edit.Tags,
+		Markdown:    &edit.Markdown,
+	}
+	if edit.ContentType != "" {
+		updates.ContentType = &edit.ContentType
+	}
+
+	created, err := h.service.Upsert(rs, updates)
+	var queued *ruleset.QueuedError
+	switch {
+	case errors.As(err, &queued):
+		http.Error(w, err.Error(), http.StatusAccepted)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stored, ok := h.lookup(w, name)
+	if !ok {
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, stored)
+}
+
+// lookup retrieves a ruleset, writing an error response when that fails
+func (h *Handler) lookup(w http.ResponseWriter, name string) (*ruleset.Ruleset, bool) {
+	if err := validation.ValidateRulesetName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	rs, err := h.service.Get(name)
+	if err != nil {
+		if exists, existsErr := h.service.Exists(name); existsErr == nil && !exists {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return nil, false
+		}
+		log.Error().Err(err).Str("name", name).Msg("Failed to serve ruleset over HTTP")
+		http.Error(w, "failed to retrieve ruleset", http.StatusInternalServerError)
+		return nil, false
+	}
+	return rs, true
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug().Err(err).Msg("Failed to write JSON response")
+	}
+}
+
+// sortSummaries orders summaries by name, since key scans return them in arbitrary order
+func sortSummaries(summaries []rulesetSummary) {
+	slices.SortFunc(summaries, func(a, b rulesetSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
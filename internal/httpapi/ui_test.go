@@ -0,0 +1,152 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testToken is the web UI access token used by the tests
+const testToken = "0123456789abcdef"
+
+// withToken authenticates every request to h with testToken
+func withToken(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+testToken)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func put(h http.Handler, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUI_Disabled(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	assert.Equal(t, http.StatusNotFound, get(h, "/ui/", "").Code)
+	assert.Equal(t, http.StatusNotFound, get(h, "/api/rulesets", "").Code)
+}
+
+func TestUI_Page(t *testing.T) {
+	h := withToken(NewHandler(ruleset.NewService(memstore.New()), WithUI(testToken)))
+
+	rec := get(h, "/ui/", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "<title>Archivyr Rulesets</title>")
+}
+
+func TestUI_ListAndGet(t *testing.T) {
+	service := ruleset.NewService(memstore.New())
+	require.NoError(t, service.Create(&ruleset.Ruleset{Name: "py_rules", Description: "Python", Tags: []string{"python"}, Markdown: "# Python"}))
+	require.NoError(t, service.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go style", Tags: []string{"go"}, Markdown: "# Go"}))
+	h := withToken(NewHandler(service, WithUI(testToken)))
+
+	rec := get(h, "/api/rulesets", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var summaries []rulesetSummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "go_rules", summaries[0].Name)
+
+	rec = get(h, "/api/rulesets?q=python", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "py_rules", summaries[0].Name)
+
+	rec = get(h, "/api/rulesets/go_rules", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var rs ruleset.Ruleset
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rs))
+	assert.Equal(t, "# Go", rs.Markdown)
+
+	assert.Equal(t, http.StatusNotFound, get(h, "/api/rulesets/missing", "").Code)
+}
+
+func TestUI_Put(t *testing.T) {
+	service := ruleset.NewService(memstore.New())
+	h := withToken(NewHandler(service, WithUI(testToken)))
+
+	rec := put(h, "/api/rulesets/go_rules", `{"description": "Go", "tags": ["go"], "markdown": "# Go"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = put(h, "/api/rulesets/go_rules", `{"description": "Go style", "tags": [], "markdown": "# Go v2"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var rs ruleset.Ruleset
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rs))
+	assert.Equal(t, int64(2), rs.Version)
+	assert.Equal(t, "# Go v2", rs.Markdown)
+	assert.Empty(t, rs.Tags)
+
+	testCases := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"invalid name", "/api/rulesets/Bad-Name", `{"description": "Bad", "markdown": "# Bad"}`},
+		{"unknown field", "/api/rulesets/go_rules", `{"markdown": "# Go", "version": 3}`},
+		{"invalid content", "/api/rulesets/json_rules", `{"description": "JSON", "content_type": "application/json", "markdown": "{"}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, put(h, tc.path, tc.body).Code)
+		})
+	}
+}
+
+func TestUI_RequiresToken(t *testing.T) {
+	broker := events.NewBroker()
+	h := NewHandler(ruleset.NewService(memstore.New()), WithEvents(broker), WithUI(testToken))
+
+	for _, path := range []string{"/ui/", "/api/rulesets", "/api/rulesets/go_rules", "/events", "/rulesets/go_rules", "/schemas/go_rules.json"} {
+		t.Run(path, func(t *testing.T) {
+			rec := get(h, path, "")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Equal(t, `Basic realm="archivyr"`, rec.Header().Get("WWW-Authenticate"))
+
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer wrong-token")
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		})
+	}
+	assert.Equal(t, http.StatusUnauthorized, put(h, "/api/rulesets/go_rules", `{"description": "Go", "markdown": "# Go"}`).Code)
+
+	// Browsers send the token as the basic authentication password
+	req := httptest.NewRequest(http.MethodGet, "/api/rulesets", nil)
+	req.SetBasicAuth("anyone", testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Ruleset content is only served with the token
+	req = httptest.NewRequest(http.MethodGet, "/rulesets/go_rules", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestUI_EmptyTokenRefusesAll(t *testing.T) {
+	h := NewHandler(ruleset.NewService(memstore.New()), WithUI(""))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/rulesets", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,115 @@
+// Package leader elects one of several server replicas sharing a Valkey to
+// run scheduled jobs, using a lease that the leader renews and others wait out.
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LeaseStore holds leases; valkey.Client implements it
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, key, owner string) error
+}
+
+// Elector campaigns for a lease and reports whether this replica holds it.
+// The lease is renewed every third of its duration, so a leader that stops
+// renewing, e.g. because it crashed, is replaced within one lease duration.
+type Elector struct {
+	store LeaseStore
+	key   string
+	owner string
+	ttl   time.Duration
+
+	mu     sync.Mutex
+	leader bool
+	// until is when the lease held by this replica expires unless renewed
+	until time.Time
+	clock func() time.Time
+}
+
+// NewElector creates an elector for the lease at key, identifying this replica as owner
+func NewElector(store LeaseStore, key, owner string, ttl time.Duration) *Elector {
+	return &Elector{store: store, key: key, owner: owner, ttl: ttl, clock: time.Now}
+}
+
+// IsLeader reports whether this replica holds the lease. A lease that could
+// not be renewed counts as lost once it would have expired.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader && e.clock().Before(e.until)
+}
+
+// Start campaigns for the lease now and every third of its duration in the
+// background until stop is called, which releases the lease if held
+func (e *Elector) Start() (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		e.campaign()
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.campaign()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+		e.resign()
+	}
+}
+
+// campaign takes or renews the lease and records the outcome
+func (e *Elector) campaign() {
+	started := e.clock()
+	held, err := e.store.AcquireLease(context.Background(), e.key, e.owner, e.ttl)
+	if err != nil {
+		// Keep the current state: a held lease stays valid until it expires
+		log.Warn().Err(err).Str("lease", e.key).Msg("Failed to renew leader lease")
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if held != e.leader {
+		if held {
+			log.Info().Str("lease", e.key).Str("owner", e.owner).Msg("Became leader for scheduled jobs")
+		} else {
+			log.Info().Str("lease", e.key).Msg("Another replica leads scheduled jobs")
+		}
+	}
+	e.leader = held
+	if held {
+		// Count from before the request so the local view never outlives the lease
+		e.until = started.Add(e.ttl)
+	}
+}
+
+// resign releases the lease if this replica holds it, so another replica can
+// take over without waiting for it to expire
+func (e *Elector) resign() {
+	e.mu.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.mu.Unlock()
+	if !wasLeader {
+		return
+	}
+	if err := e.store.ReleaseLease(context.Background(), e.key, e.owner); err != nil {
+		log.Warn().Err(err).Str("lease", e.key).Msg("Failed to release leader lease")
+	}
+}
@@ -0,0 +1,95 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore holds leases in memory, expiring them by the shared clock
+type fakeStore struct {
+	mu     sync.Mutex
+	now    *time.Time
+	holder map[string]string
+	expiry map[string]time.Time
+	err    error
+}
+
+func newFakeStore(now *time.Time) *fakeStore {
+	return &fakeStore{now: now, holder: make(map[string]string), expiry: make(map[string]time.Time)}
+}
+
+func (f *fakeStore) AcquireLease(_ context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	if holder, ok := f.holder[key]; ok && holder != owner && f.now.Before(f.expiry[key]) {
+		return false, nil
+	}
+	f.holder[key] = owner
+	f.expiry[key] = f.now.Add(ttl)
+	return true, nil
+}
+
+func (f *fakeStore) ReleaseLease(_ context.Context, key, owner string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.holder[key] == owner {
+		delete(f.holder, key)
+	}
+	return nil
+}
+
+func TestElector(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newFakeStore(&now)
+	a := NewElector(store, "leader:ruleset:scheduler", "replica_a", 30*time.Second)
+	b := NewElector(store, "leader:ruleset:scheduler", "replica_b", 30*time.Second)
+	a.clock = func() time.Time { return now }
+	b.clock = func() time.Time { return now }
+
+	a.campaign()
+	b.campaign()
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// A leader that cannot renew keeps leading until its lease would expire
+	store.err = errors.New("connection refused")
+	now = now.Add(20 * time.Second)
+	a.campaign()
+	assert.True(t, a.IsLeader())
+	now = now.Add(11 * time.Second)
+	assert.False(t, a.IsLeader())
+
+	// Another replica takes over the expired lease
+	store.err = nil
+	b.campaign()
+	a.campaign()
+	assert.True(t, b.IsLeader())
+	assert.False(t, a.IsLeader())
+
+	// Resigning hands the lease over without waiting for it to expire
+	b.resign()
+	assert.False(t, b.IsLeader())
+	a.campaign()
+	assert.True(t, a.IsLeader())
+}
+
+func TestElector_StartStop(t *testing.T) {
+	now := time.Now()
+	store := newFakeStore(&now)
+	e := NewElector(store, "leader:ruleset:scheduler", "replica_a", time.Minute)
+
+	stop := e.Start()
+	assert.Eventually(t, e.IsLeader, time.Second, 10*time.Millisecond)
+	stop()
+
+	assert.False(t, e.IsLeader())
+	assert.Empty(t, store.holder)
+}
@@ -0,0 +1,130 @@
+// Package markdown provides heading-aware helpers for slicing markdown documents.
+package markdown
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Heading is an ATX heading ("## Title") found in a document
+type Heading struct {
+	// Level is the number of leading '#' characters (1-6)
+	Level int
+	// Text is the heading text without markers
+	Text string
+	// Offset is the byte offset of the heading line in the document
+	Offset int
+}
+
+// Headings returns the ATX headings of doc in order, skipping fenced code blocks
+func Headings(doc string) []Heading {
+	headings := make([]Heading, 0)
+	inFence := false
+	fence := ""
+	offset := 0
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inFence:
+			if strings.HasPrefix(trimmed, fence) {
+				inFence = false
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = true
+			fence = trimmed[:3]
+		default:
+			if level, text, ok := parseHeading(line); ok {
+				headings = append(headings, Heading{Level: level, Text: text, Offset: offset})
+			}
+		}
+		offset += len(line)
+	}
+	return headings
+}
+
+// parseHeading parses an ATX heading line with at most three spaces of indentation
+func parseHeading(line string) (int, string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return 0, "", false
+	}
+	line = line[indent:]
+
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+
+	// Drop an optional closing sequence of '#'
+	text := strings.TrimSpace(rest)
+	if trimmed := strings.TrimRight(text, "#"); trimmed != text && (trimmed == "" || strings.HasSuffix(trimmed, " ")) {
+		text = strings.TrimSpace(trimmed)
+	}
+	return level, text, true
+}
+
+// Section returns the part of doc under the first heading matching name, up to
+// the next heading of the same or a higher level. The heading line is included.
+// Headings match on their text ignoring case, or on their slug ("Unit Tests" is "unit-tests").
+func Section(doc, name string) (string, bool) {
+	headings := Headings(doc)
+	for i, h := range headings {
+		if !strings.EqualFold(h.Text, name) && Slug(h.Text) != Slug(name) {
+			continue
+		}
+		end := len(doc)
+		for _, next := range headings[i+1:] {
+			if next.Level <= h.Level {
+				end = next.Offset
+				break
+			}
+		}
+		return doc[h.Offset:end], true
+	}
+	return "", false
+}
+
+// Slug converts heading text to a lowercase, hyphen-separated anchor
+func Slug(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '_':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '\t':
+			pendingHyphen = true
+		}
+	}
+	return b.String()
+}
+
+// Split cuts doc at every heading, so each part starts with a heading and runs
+// up to the next one. Text before the first heading forms a leading part of its
+// own. Concatenating the parts yields doc.
+func Split(doc string) []string {
+	parts := make([]string, 0)
+	start := 0
+	for _, h := range Headings(doc) {
+		if h.Offset > start {
+			parts = append(parts, doc[start:h.Offset])
+		}
+		start = h.Offset
+	}
+	if start < len(doc) {
+		parts = append(parts, doc[start:])
+	}
+	return parts
+}
@@ -0,0 +1,82 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const doc = `# Conventions
+
+Intro.
+
+## Testing
+
+Write table tests.
+
+### Unit Tests
+
+Use testify.
+
+## Style ##
+
+` + "```sh\n# not a heading\n```" + `
+
+Run gofmt.
+`
+
+func TestHeadings(t *testing.T) {
+	headings := Headings(doc)
+
+	assert.Equal(t, []Heading{
+		{Level: 1, Text: "Conventions", Offset: 0},
+		{Level: 2, Text: "Testing", Offset: strings.Index(doc, "## Testing")},
+		{Level: 3, Text: "Unit Tests", Offset: strings.Index(doc, "### Unit")},
+		{Level: 2, Text: "Style", Offset: strings.Index(doc, "## Style")},
+	}, headings)
+}
+
+func TestSection(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+		found bool
+	}{
+		{name: "includes subsections", query: "Testing", want: "## Testing\n\nWrite table tests.\n\n### Unit Tests\n\nUse testify.\n\n", found: true},
+		{name: "case-insensitive text", query: "unit tests", want: "### Unit Tests\n\nUse testify.\n\n", found: true},
+		{name: "slug", query: "unit-tests", want: "### Unit Tests\n\nUse testify.\n\n", found: true},
+		{name: "closing hashes and fenced code", query: "style", want: "## Style ##\n\n```sh\n# not a heading\n```\n\nRun gofmt.\n", found: true},
+		{name: "missing", query: "deployment"},
+		{name: "code block line is not a heading", query: "not a heading"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			section, found := Section(doc, tt.query)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, section)
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	parts := Split("Preamble.\n\n" + doc)
+
+	assert.Equal(t, []string{
+		"Preamble.\n\n",
+		"# Conventions\n\nIntro.\n\n",
+		"## Testing\n\nWrite table tests.\n\n",
+		"### Unit Tests\n\nUse testify.\n\n",
+		"## Style ##\n\n```sh\n# not a heading\n```\n\nRun gofmt.\n",
+	}, parts)
+	assert.Equal(t, "Preamble.\n\n"+doc, strings.Join(parts, ""))
+	assert.Empty(t, Split(""))
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "unit-tests", Slug("Unit Tests"))
+	assert.Equal(t, "api-v2-naming", Slug("API v2: Naming!"))
+	assert.Equal(t, "snake_case", Slug("  snake_case  "))
+}
@@ -0,0 +1,9 @@
+package marker
+
+import "time"
+
+// ServiceInterface defines the interface for marker operations
+type ServiceInterface interface {
+	Record(m *Marker) error
+	List(after, before time.Time) ([]*Marker, error)
+}
@@ -0,0 +1,12 @@
+// Package marker persists annotated checkpoints, so changes can be told apart
+// by the markers recorded before and after them.
+package marker
+
+import "time"
+
+// Marker is an annotated checkpoint such as "before refactor experiment"
+type Marker struct {
+	ID   string    `json:"id"`
+	Note string    `json:"note"`
+	Time time.Time `json:"time"`
+}
@@ -0,0 +1,69 @@
+package marker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+)
+
+// KeyPrefix is the Valkey key prefix of the sorted set holding markers
+const KeyPrefix = "marker:"
+
+// Service stores markers in a sorted set scored by their time in milliseconds
+type Service struct {
+	storage ruleset.Storage
+	ctx     context.Context
+	key     string
+}
+
+// NewService creates a marker service backed by storage, keeping the markers
+// of namespace, e.g. the ruleset key prefix, at marker:{namespace}
+func NewService(storage ruleset.Storage, namespace string) *Service {
+	return &Service{
+		storage: storage,
+		ctx:     context.Background(),
+		key:     KeyPrefix + namespace,
+	}
+}
+
+// Record stores a marker
+func (s *Service) Record(m *Marker) error {
+	member, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode marker: %w", err)
+	}
+	if err := s.storage.ZAdd(s.ctx, s.key, string(member), float64(m.Time.UnixMilli())); err != nil {
+		return fmt.Errorf("failed to record marker: %w", err)
+	}
+	return nil
+}
+
+// List returns the markers recorded from after to before, both inclusive, in
+// time order. A zero bound leaves that end of the range open.
+func (s *Service) List(after, before time.Time) ([]*Marker, error) {
+	minScore, maxScore := math.Inf(-1), math.Inf(1)
+	if !after.IsZero() {
+		minScore = float64(after.UnixMilli())
+	}
+	if !before.IsZero() {
+		maxScore = float64(before.UnixMilli())
+	}
+
+	members, err := s.storage.ZRangeByScore(s.ctx, s.key, minScore, maxScore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list markers: %w", err)
+	}
+	markers := make([]*Marker, 0, len(members))
+	for _, member := range members {
+		var m Marker
+		if err := json.Unmarshal([]byte(member), &m); err != nil {
+			return nil, fmt.Errorf("failed to decode marker: %w", err)
+		}
+		markers = append(markers, &m)
+	}
+	return markers, nil
+}
@@ -0,0 +1,42 @@
+package marker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RecordAndList(t *testing.T) {
+	store := memstore.New()
+	service := NewService(store, "ruleset:")
+	base := time.Date(2025, 10, 28, 15, 0, 0, 0, time.UTC)
+
+	for i, note := range []string{"before refactor", "after refactor", "release"} {
+		require.NoError(t, service.Record(&Marker{ID: note, Note: note, Time: base.Add(time.Duration(i) * time.Hour)}))
+	}
+
+	markers, err := service.List(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, markers, 3)
+	assert.Equal(t, &Marker{ID: "before refactor", Note: "before refactor", Time: base}, markers[0])
+	assert.Equal(t, "release", markers[2].Note)
+
+	// Bounds are inclusive
+	markers, err = service.List(base.Add(time.Hour), base.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, markers, 2)
+	assert.Equal(t, "after refactor", markers[0].Note)
+
+	markers, err = service.List(time.Time{}, base.Add(30*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, "before refactor", markers[0].Note)
+
+	// Markers are kept per namespace
+	markers, err = NewService(store, "team_a:").List(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, markers)
+}
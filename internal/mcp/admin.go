@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// WithAdmin enables the operator-only rebuild_indexes and flush_cache tools.
+// Only enable it for servers whose clients are trusted to run maintenance.
+func WithAdmin(maintainer ruleset.Maintainer) Option {
+	return func(h *Handler) {
+		h.maintainer = maintainer
+	}
+}
+
+// registerAdminTools registers the maintenance tools
+func (h *Handler) registerAdminTools(s *server.MCPServer) {
+	rebuildTool := mcp.NewTool("rebuild_indexes",
+		mcp.WithDescription("Admin: re-record the search indexes of every stored ruleset and drop entries of deleted ones, to recover from index drift. Reports progress when the request carries a progress token."),
+	)
+	s.AddTool(rebuildTool, h.handleRebuildIndexes)
+
+	flushTool := mcp.NewTool("flush_cache",
+		mcp.WithDescription("Admin: drop every cached ruleset so subsequent reads go to Valkey"),
+	)
+	s.AddTool(flushTool, h.handleFlushCache)
+}
+
+// HandleRebuildIndexes handles the rebuild_indexes tool invocation (exported for testing)
+func (h *Handler) HandleRebuildIndexes(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleRebuildIndexes(ctx, req)
+}
+
+// handleRebuildIndexes rebuilds the ruleset indexes, notifying the client of progress
+func (h *Handler) handleRebuildIndexes(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	count, err := h.maintainer.RebuildIndexesWithProgress(func(done, total int) {
+		notifyProgress(ctx, req, done, total, fmt.Sprintf("Indexed %d of %d ruleset(s)", done, total))
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to rebuild indexes: %v", err)), nil
+	}
+
+	log.Info().Int("rulesets", count).Msg("Ruleset indexes rebuilt on request")
+	return mcp.NewToolResultText(fmt.Sprintf("Rebuilt indexes for %d ruleset(s)", count)), nil
+}
+
+// HandleFlushCache handles the flush_cache tool invocation (exported for testing)
+func (h *Handler) HandleFlushCache(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleFlushCache(ctx, req)
+}
+
+// handleFlushCache clears the ruleset read cache
+func (h *Handler) handleFlushCache(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !h.maintainer.FlushCache() {
+		return mcp.NewToolResultText("No ruleset cache is configured; nothing to flush"), nil
+	}
+
+	log.Info().Msg("Ruleset cache flushed on request")
+	return mcp.NewToolResultText("Ruleset cache flushed"), nil
+}
+
+// notifyProgress sends a progress notification when the request carries a progress token.
+// Notifications are best effort, so delivery failures are only logged.
+func notifyProgress(ctx context.Context, req mcp.CallToolRequest, done, total int, message string) {
+	if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return
+	}
+
+	err := srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": req.Params.Meta.ProgressToken,
+		"progress":      done,
+		"total":         total,
+		"message":       message,
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to send progress notification")
+	}
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMaintainer simulates index rebuilds over a fixed number of rulesets
+type stubMaintainer struct {
+	rulesets int
+	err      error
+	cached   bool
+	flushed  bool
+}
+
+func (m *stubMaintainer) RebuildIndexesWithProgress(progress func(done, total int)) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	for i := 1; i <= m.rulesets; i++ {
+		progress(i, m.rulesets)
+	}
+	return m.rulesets, nil
+}
+
+func (m *stubMaintainer) FlushCache() bool {
+	m.flushed = true
+	return m.cached
+}
+
+func TestRegisterAdminTools(t *testing.T) {
+	s := server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true))
+	NewHandler(new(MockRulesetService)).RegisterTools(s)
+	assert.Nil(t, s.GetTool("rebuild_indexes"))
+	assert.Nil(t, s.GetTool("flush_cache"))
+
+	s = server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true))
+	NewHandler(new(MockRulesetService), WithAdmin(&stubMaintainer{})).RegisterTools(s)
+	assert.NotNil(t, s.GetTool("rebuild_indexes"))
+	assert.NotNil(t, s.GetTool("flush_cache"))
+}
+
+func TestHandleRebuildIndexes(t *testing.T) {
+	t.Run("reports rebuilt rulesets", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService), WithAdmin(&stubMaintainer{rulesets: 3}))
+
+		req := mcp.CallToolRequest{}
+		req.Params.Meta = &mcp.Meta{ProgressToken: "rebuild-1"}
+		result, err := handler.HandleRebuildIndexes(context.TODO(), req)
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Equal(t, "Rebuilt indexes for 3 ruleset(s)", result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("reports failure", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService), WithAdmin(&stubMaintainer{err: fmt.Errorf("connection refused")}))
+
+		result, err := handler.HandleRebuildIndexes(context.TODO(), mcp.CallToolRequest{})
+
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to rebuild indexes: connection refused")
+	})
+}
+
+func TestHandleFlushCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		cached   bool
+		expected string
+	}{
+		{name: "cache configured", cached: true, expected: "Ruleset cache flushed"},
+		{name: "no cache", cached: false, expected: "No ruleset cache is configured; nothing to flush"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintainer := &stubMaintainer{cached: tt.cached}
+			handler := NewHandler(new(MockRulesetService), WithAdmin(maintainer))
+
+			result, err := handler.HandleFlushCache(context.TODO(), mcp.CallToolRequest{})
+
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			assert.True(t, maintainer.flushed)
+			assert.Equal(t, tt.expected, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+}
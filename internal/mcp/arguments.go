@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Length limits applied to tool arguments in the input schemas
+const (
+	// maxNameLength bounds entity names
+	maxNameLength = 128
+	// maxTextLength bounds short free-text arguments such as descriptions, patterns and queries
+	maxTextLength = 1024
+	// maxURLLength bounds URL arguments
+	maxURLLength = 2048
+)
+
+// integer narrows a number property to integers
+func integer() mcp.PropertyOption {
+	return func(schema map[string]any) {
+		schema["type"] = "integer"
+	}
+}
+
+// validateTools is tool middleware that checks call arguments against the
+// tool's input schema before the handler runs, so handlers can rely on the
+// declared types. Violations are reported per field in a single error result.
+func (h *Handler) validateTools(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if h.server == nil {
+			return next(ctx, req)
+		}
+		tool := h.server.GetTool(req.Params.Name)
+		if tool == nil {
+			return next(ctx, req)
+		}
+		if problems := validateArguments(tool.Tool.InputSchema, req.GetArguments()); len(problems) > 0 {
+			return mcp.NewToolResultError("invalid arguments: " + strings.Join(problems, "; ")), nil
+		}
+		return next(ctx, req)
+	}
+}
+
+// validateArguments checks args against schema and returns one "field: problem"
+// message per violation, ordered by field name
+func validateArguments(schema mcp.ToolInputSchema, args map[string]any) []string {
+	return checkFields("", schema.Properties, schema.Required, args)
+}
+
+// checkFields validates the fields of an object, prefixing messages with prefix
+func checkFields(prefix string, properties map[string]any, required []string, args map[string]any) []string {
+	var problems []string
+	for _, name := range required {
+		if value, ok := args[name]; !ok || value == nil {
+			problems = append(problems, fmt.Sprintf("%s%s: is required", prefix, name))
+		}
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := properties[name].(map[string]any)
+		if !ok || args[name] == nil {
+			continue
+		}
+		problems = append(problems, checkValue(prefix+name, property, args[name])...)
+	}
+	return problems
+}
+
+// checkValue validates a single value against a property schema
+func checkValue(path string, schema map[string]any, value any) []string {
+	problem := func(format string, a ...any) []string {
+		return []string{path + ": " + fmt.Sprintf(format, a...)}
+	}
+
+	if types := stringList(schema["type"]); types != nil {
+		// A list of types accepts a value of any of them
+		kind := jsonType(value)
+		for _, t := range types {
+			if t == kind || (t == "integer" && kind == "number") {
+				narrowed := make(map[string]any, len(schema))
+				for k, v := range schema {
+					narrowed[k] = v
+				}
+				narrowed["type"] = t
+				return checkValue(path, narrowed, value)
+			}
+		}
+		return problem("must be of type %s", strings.Join(types, " or "))
+	}
+
+	switch schema["type"] {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return problem("must be a string")
+		}
+		if values := stringList(schema["enum"]); values != nil && !slices.Contains(values, s) {
+			return problem("must be one of: %s", strings.Join(values, ", "))
+		}
+		if limit, ok := schemaInt(schema["maxLength"]); ok && utf8.RuneCountInString(s) > limit {
+			return problem("must be at most %d characters", limit)
+		}
+		if limit, ok := schemaInt(schema["minLength"]); ok && utf8.RuneCountInString(s) < limit {
+			return problem("must be at least %d characters", limit)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+				return problem("must match pattern %s", pattern)
+			}
+		}
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			return problem("must be a number")
+		}
+		if schema["type"] == "integer" && n != float64(int64(n)) {
+			return problem("must be an integer")
+		}
+		if limit, ok := schema["minimum"].(float64); ok && n < limit {
+			return problem("must be at least %g", limit)
+		}
+		if limit, ok := schema["maximum"].(float64); ok && n > limit {
+			return problem("must be at most %g", limit)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return problem("must be a boolean")
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return problem("must be an array")
+		}
+		if limit, ok := schemaInt(schema["maxItems"]); ok && len(items) > limit {
+			return problem("must have at most %d items", limit)
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		if itemSchema == nil {
+			return nil
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, checkValue(fmt.Sprintf("%s[%d]", path, i), itemSchema, item)...)
+		}
+		return problems
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return problem("must be an object")
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		return checkFields(path+".", properties, stringList(schema["required"]), obj)
+	}
+	return nil
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value
+func jsonType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "null"
+}
+
+// schemaInt reads an integer schema keyword, which is an int when built in Go
+// and a float64 when decoded from JSON
+func schemaInt(raw any) (int, bool) {
+	switch n := raw.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// stringList reads a string list schema keyword
+func stringList(raw any) []string {
+	switch values := raw.(type) {
+	case []string:
+		return values
+	case []any:
+		list := make([]string, 0, len(values))
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArguments(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService), WithPromptService(prompt.NewService(memstore.New())))
+	s := server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true))
+	handler.RegisterTools(s)
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     map[string]any
+		problems []string
+	}{
+		{
+			name: "valid arguments",
+			tool: "upsert_ruleset",
+			args: map[string]any{"name": "go_style", "content_type": "text/markdown", "force": true},
+		},
+		{
+			name:     "missing required",
+			tool:     "get_ruleset",
+			args:     map[string]any{},
+			problems: []string{"name: is required"},
+		},
+		{
+			name: "wrong types",
+			tool: "get_ruleset",
+			args: map[string]any{"name": 42, "expand_snippets": "yes", "if_version_not": "3"},
+			problems: []string{
+				"expand_snippets: must be a boolean",
+				"if_version_not: must be a number",
+				"name: must be a string",
+			},
+		},
+		{
+			name:     "enum",
+			tool:     "upsert_ruleset",
+			args:     map[string]any{"name": "go_style", "content_type": "text/html"},
+			problems: []string{"content_type: must be one of: " + strings.Join(ruleset.SupportedContentTypes, ", ")},
+		},
+		{
+			name:     "max length",
+			tool:     "upsert_ruleset",
+			args:     map[string]any{"name": strings.Repeat("a", maxNameLength+1)},
+			problems: []string{"name: must be at most 128 characters"},
+		},
+		{
+			name: "tags as comma-separated string",
+			tool: "upsert_ruleset",
+			args: map[string]any{"name": "go_style", "tags": "go,style"},
+		},
+		{
+			name:     "tags item pattern",
+			tool:     "upsert_ruleset",
+			args:     map[string]any{"name": "go_style", "tags": []any{"go", "code style"}},
+			problems: []string{"tags[1]: must match pattern " + tagListPattern},
+		},
+		{
+			name: "comma-separated tag items",
+			tool: "search_rulesets",
+			args: map[string]any{"tags": []any{"go,style", "lint"}},
+		},
+		{
+			name:     "type list",
+			tool:     "upsert_ruleset",
+			args:     map[string]any{"name": "go_style", "tags": true},
+			problems: []string{"tags: must be of type array or string"},
+		},
+		{
+			name:     "minimum",
+			tool:     "get_ruleset",
+			args:     map[string]any{"name": "go_style", "if_version_not": float64(0)},
+			problems: []string{"if_version_not: must be at least 1"},
+		},
+		{
+			name:     "array items",
+			tool:     "search_rulesets",
+			args:     map[string]any{"tags": []any{"go", 7}},
+			problems: []string{"tags[1]: must be a string"},
+		},
+		{
+			name:     "nested object fields",
+			tool:     "upsert_prompt_template",
+			args:     map[string]any{"name": "review", "template": "x", "variables": []any{map[string]any{"required": "no"}}},
+			problems: []string{"variables[0].name: is required", "variables[0].required: must be a boolean"},
+		},
+		{
+			name: "unknown arguments are ignored",
+			tool: "delete_ruleset",
+			args: map[string]any{"name": "go_style", "extra": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := s.GetTool(tt.tool)
+			require.NotNil(t, tool)
+			assert.Equal(t, tt.problems, validateArguments(tool.Tool.InputSchema, tt.args))
+		})
+	}
+}
+
+func TestValidateTools(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService))
+	s := server.NewMCPServer("Test Server", "1.0.0", server.WithToolCapabilities(true))
+	handler.server = s
+	handler.RegisterTools(s)
+
+	called := false
+	next := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "delete_ruleset"
+	req.Params.Arguments = map[string]any{"name": "go_style", "force": "true"}
+	result, err := handler.validateTools(next)(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "invalid arguments: force: must be a boolean", result.Content[0].(mcp.TextContent).Text)
+	assert.False(t, called)
+
+	req.Params.Arguments = map[string]any{"name": "go_style", "force": true}
+	result, err = handler.validateTools(next)(context.TODO(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.True(t, called)
+}
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerBulkTool registers the bulk_update_rulesets tool
+func (h *Handler) registerBulkTool(s *server.MCPServer) {
+	bulkTool := mcp.NewTool("bulk_update_rulesets",
+		mcp.WithDescription("Apply the same metadata change to every ruleset whose name matches a glob pattern, e.g. tag everything matching 'legacy_*' as deprecated. Reports the outcome per ruleset. Use dry_run to preview the changes first."),
+		mcp.WithString("pattern", mcp.Required(), mcp.Description("Glob pattern selecting rulesets by name, e.g. 'legacy_*'"), mcp.MaxLength(maxTextLength)),
+		namedTagsParam("add_tags", "Tags to add to each ruleset, keeping its other tags"),
+		namedTagsParam("remove_tags", "Tags to remove from each ruleset (ignoring case)"),
+		mcp.WithString("license", mcp.Description("SPDX license expression to set. Pass an empty string to clear it."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("owner", mcp.Description("Owner to set. Pass an empty string to clear it."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("team", mcp.Description("Team to assign. Pass an empty string to clear it."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("current_owner", mcp.Description("Current owner of the matched rulesets; required to change the owner or team of owned rulesets"), mcp.MaxLength(maxTextLength)),
+		mcp.WithBoolean("force", mcp.Description("Allow modifying rulesets imported from an external source")),
+		mcp.WithBoolean("dry_run", mcp.Description("Report what would change without modifying anything")),
+	)
+	s.AddTool(bulkTool, h.handleBulkUpdateRulesets)
+}
+
+// bulkChanges is the metadata change applied to each matched ruleset
+type bulkChanges struct {
+	addTags    []string
+	removeTags []string
+	license    *string
+	owner      *string
+	team       *string
+}
+
+// bulkChangesFromRequest reads the requested change, failing when nothing would change
+func bulkChangesFromRequest(req mcp.CallToolRequest) (*bulkChanges, error) {
+	args := req.GetArguments()
+	changes := &bulkChanges{}
+
+	var err error
+	if changes.addTags, err = parseTags(args["add_tags"]); err != nil {
+		return nil, fmt.Errorf("invalid add_tags: %w", err)
+	}
+	if changes.removeTags, err = parseTags(args["remove_tags"]); err != nil {
+		return nil, fmt.Errorf("invalid remove_tags: %w", err)
+	}
+	for _, field := range []struct {
+		param  string
+		target **string
+	}{
+		{"license", &changes.license},
+		{"owner", &changes.owner},
+		{"team", &changes.team},
+	} {
+		if _, ok := args[field.param]; ok {
+			value := req.GetString(field.param, "")
+			*field.target = &value
+		}
+	}
+
+	if len(changes.addTags) == 0 && len(changes.removeTags) == 0 && changes.license == nil && changes.owner == nil && changes.team == nil {
+		return nil, fmt.Errorf("no changes given: pass at least one of add_tags, remove_tags, license, owner or team")
+	}
+	return changes, nil
+}
+
+// update returns the update applying the change to rs, and a description of
+// what it changes; the update is nil when rs already matches
+func (c *bulkChanges) update(rs *ruleset.Ruleset) (*ruleset.Update, string) {
+	updates := &ruleset.Update{}
+	var described []string
+
+	tags := make([]string, 0, len(rs.Tags)+len(c.addTags))
+	var added, removed []string
+	for _, tag := range rs.Tags {
+		if containsFold(c.removeTags, tag) {
+			removed = append(removed, "-"+tag)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	for _, tag := range c.addTags {
+		if !containsFold(tags, tag) && !containsFold(c.removeTags, tag) {
+			tags = append(tags, tag)
+			added = append(added, "+"+tag)
+		}
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		updates.Tags = &tags
+		described = append(described, "tags "+strings.Join(append(added, removed...), " "))
+	}
+
+	for _, field := range []struct {
+		name    string
+		value   *string
+		current string
+		target  **string
+	}{
+		{"license", c.license, rs.License, &updates.License},
+		{"owner", c.owner, rs.Owner, &updates.Owner},
+		{"team", c.team, rs.Team, &updates.Team},
+	} {
+		if field.value != nil && *field.value != field.current {
+			*field.target = field.value
+			described = append(described, fmt.Sprintf("%s %s -> %s", field.name, orNone(field.current), orNone(*field.value)))
+		}
+	}
+
+	if len(described) == 0 {
+		return nil, ""
+	}
+	return updates, strings.Join(described, ", ")
+}
+
+// containsFold reports whether tags contains tag, ignoring case
+func containsFold(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// HandleBulkUpdateRulesets handles the bulk_update_rulesets tool invocation (exported for testing)
+func (h *Handler) HandleBulkUpdateRulesets(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleBulkUpdateRulesets(ctx, req)
+}
+
+// handleBulkUpdateRulesets applies a metadata change to every ruleset matching
+// a pattern. Each ruleset is updated on its own, so a failure is reported for
+// that ruleset and the others are still updated.
+func (h *Handler) handleBulkUpdateRulesets(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pattern, err := req.RequireString("pattern")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'pattern': %v", err)), nil
+	}
+	changes, err := bulkChangesFromRequest(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	dryRun := req.GetBool("dry_run", false)
+
+	rulesets, err := h.rulesetService.Search(pattern)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search rulesets: %v", err)), nil
+	}
+	if len(rulesets) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No rulesets match pattern '%s'", pattern)), nil
+	}
+	sortByName(rulesets)
+
+	var updated, unchanged, failed int
+	lines := make([]string, 0, len(rulesets))
+	for _, rs := range rulesets {
+		updates, description := changes.update(rs)
+		switch {
+		case updates == nil:
+			unchanged++
+			lines = append(lines, fmt.Sprintf("- %s: unchanged", rs.Name))
+			continue
+		case dryRun:
+			updated++
+			lines = append(lines, fmt.Sprintf("- %s: would update %s", rs.Name, description))
+			continue
+		}
+
+		updates.CurrentOwner = req.GetString("current_owner", "")
+		updates.Force = req.GetBool("force", false)
+		err := h.rulesetService.Update(rs.Name, updates)
+		var queued *ruleset.QueuedError
+		switch {
+		case errors.As(err, &queued):
+			updated++
+			lines = append(lines, fmt.Sprintf("- %s: queued %s", rs.Name, description))
+		case err != nil:
+			failed++
+			lines = append(lines, fmt.Sprintf("- %s: failed: %v", rs.Name, err))
+		default:
+			updated++
+			lines = append(lines, fmt.Sprintf("- %s: updated %s", rs.Name, description))
+		}
+	}
+
+	summary := fmt.Sprintf("Updated %d of %d ruleset(s) matching '%s' (%d unchanged, %d failed)", updated, len(rulesets), pattern, unchanged, failed)
+	if dryRun {
+		summary = fmt.Sprintf("Dry run: would update %d of %d ruleset(s) matching '%s' (%d unchanged)", updated, len(rulesets), pattern, unchanged)
+	}
+	return mcp.NewToolResultText(summary + "\n\n" + strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func legacyRulesets() []*ruleset.Ruleset {
+	return []*ruleset.Ruleset{
+		{Name: "legacy_java", Tags: []string{"java", "deprecated"}},
+		{Name: "legacy_api", Tags: []string{"api", "Draft"}, License: "MIT"},
+		{Name: "legacy_go", Tags: []string{"go"}},
+	}
+}
+
+func TestHandleBulkUpdateRulesets(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	mockService.On("Search", "legacy_*").Return(legacyRulesets(), nil)
+	mockService.On("Update", "legacy_api", mock.MatchedBy(func(u *ruleset.Update) bool {
+		return u.Tags != nil && assert.ObjectsAreEqual([]string{"api", "deprecated"}, *u.Tags) && u.License == nil && u.Force
+	})).Return(nil)
+	mockService.On("Update", "legacy_go", mock.MatchedBy(func(u *ruleset.Update) bool {
+		return u.Tags != nil && assert.ObjectsAreEqual([]string{"go", "deprecated"}, *u.Tags)
+	})).Return(errors.New("ruleset 'legacy_go' is owned by 'alice'"))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"pattern":     "legacy_*",
+		"add_tags":    []interface{}{"deprecated"},
+		"remove_tags": "draft",
+		"force":       true,
+	}
+	result, err := handler.HandleBulkUpdateRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "Updated 1 of 3 ruleset(s) matching 'legacy_*' (1 unchanged, 1 failed)\n\n"+
+		"- legacy_api: updated tags +deprecated -Draft\n"+
+		"- legacy_go: failed: ruleset 'legacy_go' is owned by 'alice'\n"+
+		"- legacy_java: unchanged", result.Content[0].(mcp.TextContent).Text)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleBulkUpdateRulesets_DryRun(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	mockService.On("Search", "legacy_*").Return(legacyRulesets(), nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"pattern": "legacy_*",
+		"team":    "platform",
+		"dry_run": true,
+	}
+	result, err := handler.HandleBulkUpdateRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "Dry run: would update 3 of 3 ruleset(s) matching 'legacy_*' (0 unchanged)\n\n"+
+		"- legacy_api: would update team none -> platform\n"+
+		"- legacy_go: would update team none -> platform\n"+
+		"- legacy_java: would update team none -> platform", result.Content[0].(mcp.TextContent).Text)
+	mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestHandleBulkUpdateRulesets_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{"missing pattern", map[string]interface{}{"add_tags": "x"}, "missing required parameter 'pattern'"},
+		{"no changes", map[string]interface{}{"pattern": "*"}, "no changes given"},
+		{"invalid tag", map[string]interface{}{"pattern": "*", "add_tags": "bad tag"}, "invalid add_tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(new(MockRulesetService))
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handler.HandleBulkUpdateRulesets(context.TODO(), req)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.expected)
+		})
+	}
+}
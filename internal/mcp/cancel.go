@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// methodNotificationCancelled is sent by clients that abandon a request
+const methodNotificationCancelled = "notifications/cancelled"
+
+// requestIDHeader carries a tool call's JSON-RPC id from the BeforeCallTool hook
+// to the middleware, since tool handlers do not otherwise see the id
+const requestIDHeader = "X-Archivyr-Request-Id"
+
+// cancelRegistry tracks the cancel functions of running tool calls by request key
+type cancelRegistry struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// newCancelRegistry creates an empty registry
+func newCancelRegistry() *cancelRegistry {
+	return &cancelRegistry{cancels: make(map[string]context.CancelFunc)}
+}
+
+// requestKey identifies a request within its session, as ids are only unique per session
+func requestKey(ctx context.Context, id any) string {
+	session := ""
+	if s := server.ClientSessionFromContext(ctx); s != nil {
+		session = s.SessionID()
+	}
+	return fmt.Sprintf("%s/%v", session, id)
+}
+
+// tagRequest is a BeforeCallTool hook recording the request key on the call
+func (r *cancelRegistry) tagRequest(ctx context.Context, id any, req *mcp.CallToolRequest) {
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(requestIDHeader, requestKey(ctx, id))
+	req.Header = header
+}
+
+// cancelTools is tool middleware giving each call a context that is
+// cancelled when the client sends notifications/cancelled for it
+func (r *cancelRegistry) cancelTools(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		key := req.Header.Get(requestIDHeader)
+		if key == "" {
+			return next(ctx, req)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		r.mu.Lock()
+		r.cancels[key] = cancel
+		r.mu.Unlock()
+		defer func() {
+			r.mu.Lock()
+			delete(r.cancels, key)
+			r.mu.Unlock()
+			cancel()
+		}()
+
+		return next(ctx, req)
+	}
+}
+
+// handleCancelled cancels the running call named by a notifications/cancelled message
+func (r *cancelRegistry) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+
+	key := requestKey(ctx, id)
+	r.mu.Lock()
+	cancel, ok := r.cancels[key]
+	r.mu.Unlock()
+	if !ok {
+		// The call already finished or never reached a handler
+		return
+	}
+
+	log.Debug().Interface("request_id", id).Interface("reason", notification.Params.AdditionalFields["reason"]).
+		Msg("Cancelling tool call at client request")
+	cancel()
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelledNotification builds a notifications/cancelled message for requestID
+func cancelledNotification(requestID any) mcp.JSONRPCNotification {
+	notification := mcp.JSONRPCNotification{}
+	notification.Method = methodNotificationCancelled
+	notification.Params.AdditionalFields = map[string]any{"requestId": requestID, "reason": "user aborted"}
+	return notification
+}
+
+func TestCancelRegistry(t *testing.T) {
+	t.Run("cancels the running call", func(t *testing.T) {
+		registry := newCancelRegistry()
+		started := make(chan struct{})
+		handler := registry.cancelTools(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			return mcp.NewToolResultError(ctx.Err().Error()), nil
+		})
+
+		req := mcp.CallToolRequest{}
+		registry.tagRequest(context.TODO(), float64(7), &req)
+
+		done := make(chan *mcp.CallToolResult)
+		go func() {
+			result, _ := handler(context.TODO(), req)
+			done <- result
+		}()
+
+		<-started
+		registry.handleCancelled(context.TODO(), cancelledNotification(float64(8)))
+		registry.handleCancelled(context.TODO(), cancelledNotification(float64(7)))
+
+		select {
+		case result := <-done:
+			assert.Equal(t, context.Canceled.Error(), result.Content[0].(mcp.TextContent).Text)
+		case <-time.After(time.Second):
+			t.Fatal("tool call was not cancelled")
+		}
+
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		assert.Empty(t, registry.cancels)
+	})
+
+	t.Run("keeps client headers", func(t *testing.T) {
+		registry := newCancelRegistry()
+		req := mcp.CallToolRequest{Header: map[string][]string{"Authorization": {"Bearer token"}}}
+		registry.tagRequest(context.TODO(), "req-1", &req)
+
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		assert.Equal(t, "/req-1", req.Header.Get(requestIDHeader))
+	})
+
+	t.Run("passes untagged calls through", func(t *testing.T) {
+		registry := newCancelRegistry()
+		handler := registry.cancelTools(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			require.NoError(t, ctx.Err())
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		result, err := handler(context.TODO(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result.Content[0].(mcp.TextContent).Text)
+	})
+}
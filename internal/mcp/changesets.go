@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/util"
+	"github.com/jbrinkman/archivyr/internal/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Limits on staged changesets, which are held in memory until committed or aborted
+const (
+	maxOpenChangesets   = 64
+	maxChangesetChanges = 100
+	// changesetTTL is how long a changeset stays open without changes being staged
+	changesetTTL = time.Hour
+)
+
+// changeset is an open changeset
+type changeset struct {
+	changes []ruleset.Change
+	touched time.Time
+}
+
+// changesetRegistry holds the open changesets by ID
+type changesetRegistry struct {
+	applier ruleset.ChangesetApplier
+	clock   func() time.Time
+
+	mu   sync.Mutex
+	open map[string]*changeset
+}
+
+// WithChangesets enables the begin_changeset, commit_changeset and
+// abort_changeset tools, and staging upsert_ruleset and delete_ruleset calls
+// in a changeset so a multi-step workflow is applied all or nothing
+func WithChangesets(applier ruleset.ChangesetApplier) Option {
+	return func(h *Handler) {
+		h.changesets = &changesetRegistry{applier: applier, clock: time.Now, open: make(map[string]*changeset)}
+	}
+}
+
+// changesetParams declares the changeset parameter of the mutation tools when changesets are enabled
+func (h *Handler) changesetParams() []mcp.ToolOption {
+	if h.changesets == nil {
+		return nil
+	}
+	return []mcp.ToolOption{
+		mcp.WithString("changeset", mcp.Description("ID from begin_changeset; stages the change instead of applying it, until commit_changeset"), mcp.MaxLength(maxTextLength)),
+	}
+}
+
+// registerChangesetTools registers the changeset tools
+func (h *Handler) registerChangesetTools(s *server.MCPServer) {
+	beginTool := mcp.NewTool("begin_changeset",
+		mcp.WithDescription("Start a changeset for a multi-step workflow, e.g. renaming a ruleset and updating the rulesets referencing it. Pass the returned ID as 'changeset' to upsert_ruleset and delete_ruleset to stage changes, then call commit_changeset to apply them all or nothing, or abort_changeset to discard them."),
+	)
+	s.AddTool(beginTool, h.handleBeginChangeset)
+
+	commitTool := mcp.NewTool("commit_changeset",
+		mcp.WithDescription("Apply the staged changes of a changeset in order. If any change fails, the earlier ones are rolled back and nothing is changed."),
+		mcp.WithString("changeset", mcp.Required(), mcp.Description("Changeset ID"), mcp.MaxLength(maxTextLength)),
+	)
+	s.AddTool(commitTool, h.handleCommitChangeset)
+
+	abortTool := mcp.NewTool("abort_changeset",
+		mcp.WithDescription("Discard a changeset and its staged changes"),
+		mcp.WithString("changeset", mcp.Required(), mcp.Description("Changeset ID"), mcp.MaxLength(maxTextLength)),
+	)
+	s.AddTool(abortTool, h.handleAbortChangeset)
+}
+
+// begin opens a changeset with an ID from ids and returns the ID, first dropping expired ones
+func (r *changesetRegistry) begin(ids util.IDGenerator) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock()
+	for id, cs := range r.open {
+		if now.Sub(cs.touched) > changesetTTL {
+			delete(r.open, id)
+		}
+	}
+	if len(r.open) >= maxOpenChangesets {
+		return "", fmt.Errorf("too many open changesets (%d); commit or abort one first", maxOpenChangesets)
+	}
+
+	id, err := ids.NewID("changeset")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate changeset ID: %w", err)
+	}
+	if _, ok := r.open[id]; ok {
+		return "", fmt.Errorf("generated changeset ID '%s' is already in use; please retry", id)
+	}
+	r.open[id] = &changeset{touched: now}
+	return id, nil
+}
+
+// get returns the open changeset with the given ID; r.mu must be held
+func (r *changesetRegistry) get(id string) (*changeset, error) {
+	cs, ok := r.open[id]
+	if !ok || r.clock().Sub(cs.touched) > changesetTTL {
+		delete(r.open, id)
+		return nil, fmt.Errorf("changeset '%s' not found; it may have been committed, aborted or expired", id)
+	}
+	return cs, nil
+}
+
+// stage appends a change to a changeset and returns the number of staged changes
+func (r *changesetRegistry) stage(id string, change ruleset.Change) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cs, err := r.get(id)
+	if err != nil {
+		return 0, err
+	}
+	if len(cs.changes) >= maxChangesetChanges {
+		return 0, fmt.Errorf("changeset '%s' already holds the maximum of %d changes", id, maxChangesetChanges)
+	}
+	cs.changes = append(cs.changes, change)
+	cs.touched = r.clock()
+	return len(cs.changes), nil
+}
+
+// take removes a changeset and returns its staged changes
+func (r *changesetRegistry) take(id string) ([]ruleset.Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cs, err := r.get(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(r.open, id)
+	return cs.changes, nil
+}
+
+// stageChange handles a mutation tool call carrying a changeset parameter
+func (h *Handler) stageChange(id string, change ruleset.Change) *mcp.CallToolResult {
+	if h.changesets == nil {
+		return mcp.NewToolResultError("changesets are not enabled on this server")
+	}
+	if err := validation.ValidateRulesetName(change.Name); err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	staged, err := h.changesets.stage(id, change)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Staged %s in changeset '%s' (%d change(s) staged); it is applied by commit_changeset", change.Describe(), id, staged))
+}
+
+// HandleBeginChangeset handles the begin_changeset tool invocation (exported for testing)
+func (h *Handler) HandleBeginChangeset(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleBeginChangeset(ctx, req)
+}
+
+// handleBeginChangeset opens a changeset
+func (h *Handler) handleBeginChangeset(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := h.changesets.begin(h.ids)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(
+		"Started changeset '%s'. Pass changeset: \"%s\" to upsert_ruleset and delete_ruleset to stage changes, then call commit_changeset or abort_changeset. It expires after %s without changes.",
+		id, id, changesetTTL)), nil
+}
+
+// HandleCommitChangeset handles the commit_changeset tool invocation (exported for testing)
+func (h *Handler) HandleCommitChangeset(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleCommitChangeset(ctx, req)
+}
+
+// handleCommitChangeset applies a changeset. The changeset is closed whether
+// or not it applies, since a failed commit is rolled back.
+func (h *Handler) handleCommitChangeset(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("changeset")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'changeset': %v", err)), nil
+	}
+	changes, err := h.changesets.take(id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(changes) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Changeset '%s' had no staged changes; nothing was applied", id)), nil
+	}
+
+	// Deletions are checked against the reference policy as delete_ruleset does
+	var notes []string
+	if h.referencePolicy != "" {
+		if changes, notes, err = h.changesetReferences(changes); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to commit changeset '%s': %v", id, err)), nil
+		}
+	}
+
+	if err := h.changesets.applier.ApplyChangeset(changes); err != nil {
+		if result := queuedResult(err, fmt.Sprintf("changeset '%s' (%d change(s))", id, len(changes))); result != nil {
+			return result, nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to commit changeset '%s': %v", id, err)), nil
+	}
+
+	lines := make([]string, 0, len(changes)+len(notes))
+	for _, c := range changes {
+		lines = append(lines, "- "+c.Describe())
+	}
+	lines = append(lines, notes...)
+	return mcp.NewToolResultText(fmt.Sprintf("Committed changeset '%s' (%d change(s)):\n%s", id, len(changes), strings.Join(lines, "\n"))), nil
+}
+
+// HandleAbortChangeset handles the abort_changeset tool invocation (exported for testing)
+func (h *Handler) HandleAbortChangeset(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleAbortChangeset(ctx, req)
+}
+
+// handleAbortChangeset discards a changeset
+func (h *Handler) handleAbortChangeset(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("changeset")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'changeset': %v", err)), nil
+	}
+	changes, err := h.changesets.take(id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Aborted changeset '%s'; %d staged change(s) discarded", id, len(changes))), nil
+}
@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeApplier records applied changesets
+type fakeApplier struct {
+	applied [][]ruleset.Change
+	err     error
+}
+
+func (f *fakeApplier) ApplyChangeset(changes []ruleset.Change) error {
+	f.applied = append(f.applied, changes)
+	return f.err
+}
+
+var changesetIDRegex = regexp.MustCompile(`changeset '([0-9A-Za-z_-]+)'`)
+
+// beginChangeset starts a changeset and returns its ID
+func beginChangeset(t *testing.T, handler *Handler) string {
+	t.Helper()
+	result, err := handler.HandleBeginChangeset(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	match := changesetIDRegex.FindStringSubmatch(result.Content[0].(mcp.TextContent).Text)
+	require.NotNil(t, match)
+	return match[1]
+}
+
+func callTool(t *testing.T, handle func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := handle(context.TODO(), req)
+	require.NoError(t, err)
+	return result
+}
+
+func TestChangesets_Commit(t *testing.T) {
+	mockService := new(MockRulesetService)
+	applier := &fakeApplier{}
+	handler := NewHandler(mockService, WithChangesets(applier))
+	id := beginChangeset(t, handler)
+
+	result := callTool(t, handler.HandleUpsertRuleset, map[string]interface{}{
+		"name": "golang_rules", "description": "Go", "markdown": "# Go", "changeset": id,
+	})
+	require.False(t, result.IsError)
+	assert.Equal(t, "Staged upsert golang_rules in changeset '"+id+"' (1 change(s) staged); it is applied by commit_changeset",
+		result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id, "force": true})
+	require.False(t, result.IsError)
+
+	// Nothing is applied while staging
+	mockService.AssertNotCalled(t, "Upsert")
+	mockService.AssertNotCalled(t, "Delete")
+	assert.Empty(t, applier.applied)
+
+	result = callTool(t, handler.HandleCommitChangeset, map[string]interface{}{"changeset": id})
+	require.False(t, result.IsError)
+	assert.Equal(t, "Committed changeset '"+id+"' (2 change(s)):\n- upsert golang_rules\n- delete go_rules", result.Content[0].(mcp.TextContent).Text)
+
+	require.Len(t, applier.applied, 1)
+	changes := applier.applied[0]
+	require.Len(t, changes, 2)
+	assert.Equal(t, ruleset.ChangeUpsert, changes[0].Kind)
+	assert.Equal(t, "# Go", changes[0].Ruleset.Markdown)
+	assert.Equal(t, ruleset.Change{Kind: ruleset.ChangeDelete, Name: "go_rules", DeleteOptions: ruleset.DeleteOptions{Force: true}}, changes[1])
+
+	// A committed changeset is closed
+	result = callTool(t, handler.HandleCommitChangeset, map[string]interface{}{"changeset": id})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "changeset '"+id+"' not found")
+}
+
+func TestChangesets_CommitFails(t *testing.T) {
+	applier := &fakeApplier{err: errors.New("change 2 (delete go_rules) failed: ruleset 'go_rules' not found; earlier changes were rolled back")}
+	handler := NewHandler(new(MockRulesetService), WithChangesets(applier))
+	id := beginChangeset(t, handler)
+
+	callTool(t, handler.HandleUpsertRuleset, map[string]interface{}{"name": "golang_rules", "description": "Go", "markdown": "# Go", "changeset": id})
+	callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+
+	result := callTool(t, handler.HandleCommitChangeset, map[string]interface{}{"changeset": id})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "failed to commit changeset '"+id+"': "+applier.err.Error(), result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestChangesets_CommitQueued(t *testing.T) {
+	applier := &fakeApplier{err: &ruleset.QueuedError{Pending: 2, Err: errors.New("connection refused")}}
+	handler := NewHandler(new(MockRulesetService), WithChangesets(applier))
+	id := beginChangeset(t, handler)
+
+	callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+
+	result := callTool(t, handler.HandleCommitChangeset, map[string]interface{}{"changeset": id})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Valkey is unreachable, so the changeset '"+id+"' (1 change(s)) was queued (2 change(s) pending). It will be applied when the connection returns.",
+		result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestChangesets_Abort(t *testing.T) {
+	applier := &fakeApplier{}
+	handler := NewHandler(new(MockRulesetService), WithChangesets(applier))
+	id := beginChangeset(t, handler)
+	callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+
+	result := callTool(t, handler.HandleAbortChangeset, map[string]interface{}{"changeset": id})
+	require.False(t, result.IsError)
+	assert.Equal(t, "Aborted changeset '"+id+"'; 1 staged change(s) discarded", result.Content[0].(mcp.TextContent).Text)
+	assert.Empty(t, applier.applied)
+
+	result = callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+	assert.True(t, result.IsError)
+}
+
+func TestChangesets_Expire(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService), WithChangesets(&fakeApplier{}))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	handler.changesets.clock = func() time.Time { return now }
+	id := beginChangeset(t, handler)
+
+	now = now.Add(changesetTTL + time.Minute)
+	result := callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "may have been committed, aborted or expired")
+}
+
+func TestChangesets_Disabled(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService))
+
+	result := callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": "abc"})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "changesets are not enabled on this server", result.Content[0].(mcp.TextContent).Text)
+}
+
+// fixedIDs returns the same ID every time
+type fixedIDs string
+
+func (f fixedIDs) NewID(string) (string, error) { return string(f), nil }
+
+func TestChangesets_IDGenerator(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService), WithChangesets(&fakeApplier{}), WithIDGenerator(fixedIDs("2025-10-28-changeset-k3x9qa")))
+	assert.Equal(t, "2025-10-28-changeset-k3x9qa", beginChangeset(t, handler))
+
+	// A generated ID never replaces an open changeset
+	result, err := handler.HandleBeginChangeset(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "already in use")
+}
+
+func TestChangesets_CommitReferencePolicy(t *testing.T) {
+	corpus := func() []*ruleset.Ruleset {
+		return []*ruleset.Ruleset{
+			{Name: "go_rules", Markdown: "# Go"},
+			{Name: "api_rules", Markdown: "Follow [the Go rules](ruleset://go_rules)."},
+			{Name: "cli_rules", Markdown: "See ruleset://go_rules."},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		policy   ruleset.ReferencePolicy
+		edits    map[string]string
+		isError  bool
+		expected string
+		unlinks  map[string]string
+	}{
+		{
+			name:     "block",
+			policy:   ruleset.ReferencePolicyBlock,
+			isError:  true,
+			expected: "ruleset 'go_rules' is still referenced by: api_rules, cli_rules; remove the references before deleting it",
+		},
+		{
+			name:     "block allows links removed in the changeset",
+			policy:   ruleset.ReferencePolicyBlock,
+			edits:    map[string]string{"api_rules": "# API", "cli_rules": "# CLI"},
+			expected: "- delete go_rules",
+		},
+		{
+			name:     "warn",
+			policy:   ruleset.ReferencePolicyWarn,
+			edits:    map[string]string{"cli_rules": "# CLI"},
+			expected: "- delete go_rules\n- upsert cli_rules\nWarning: ruleset 'go_rules' is still referenced by: api_rules",
+		},
+		{
+			name:     "cascade",
+			policy:   ruleset.ReferencePolicyCascade,
+			expected: "- delete go_rules\n- upsert api_rules\n- upsert cli_rules\nRemoved links to ruleset 'go_rules' from: api_rules, cli_rules",
+			unlinks:  map[string]string{"api_rules": "Follow the Go rules.", "cli_rules": "See go_rules."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRulesetService)
+			mockService.On("List").Return(corpus(), nil)
+			applier := &fakeApplier{}
+			handler := NewHandler(mockService, WithChangesets(applier), WithReferencePolicy(tt.policy))
+			id := beginChangeset(t, handler)
+
+			result := callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+			require.False(t, result.IsError)
+			for _, name := range []string{"api_rules", "cli_rules"} {
+				if markdown, ok := tt.edits[name]; ok {
+					result = callTool(t, handler.HandleUpsertRuleset, map[string]interface{}{"name": name, "markdown": markdown, "changeset": id})
+					require.False(t, result.IsError)
+				}
+			}
+
+			result = callTool(t, handler.HandleCommitChangeset, map[string]interface{}{"changeset": id})
+			assert.Equal(t, tt.isError, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.expected)
+			if tt.isError {
+				assert.Empty(t, applier.applied)
+				return
+			}
+
+			// Links are removed in the same changeset, so they are rolled back with it
+			require.Len(t, applier.applied, 1)
+			changes := applier.applied[0]
+			unlinked := map[string]string{}
+			for _, c := range changes[1+len(tt.edits):] {
+				require.True(t, c.Updates.Force)
+				unlinked[c.Name] = *c.Updates.Markdown
+			}
+			if tt.unlinks == nil {
+				assert.Empty(t, unlinked)
+			} else {
+				assert.Equal(t, tt.unlinks, unlinked)
+			}
+		})
+	}
+}
+
+func TestChangesets_CommitReferenceCheckFails(t *testing.T) {
+	mockService := new(MockRulesetService)
+	mockService.On("List").Return(nil, errors.New("connection refused"))
+	applier := &fakeApplier{}
+	handler := NewHandler(mockService, WithChangesets(applier), WithReferencePolicy(ruleset.ReferencePolicyBlock))
+	id := beginChangeset(t, handler)
+
+	callTool(t, handler.HandleDeleteRuleset, map[string]interface{}{"name": "go_rules", "changeset": id})
+	result := callTool(t, handler.HandleCommitChangeset, map[string]interface{}{"changeset": id})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to check references to the rulesets deleted by the changeset")
+	assert.Empty(t, applier.applied)
+}
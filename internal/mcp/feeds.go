@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Well-known resource URIs giving resource-oriented clients entry points beyond single-name lookups.
+// They take precedence over a ruleset of the same name, which stays reachable through get_ruleset.
+const (
+	catalogURI      = "ruleset://catalog"
+	tagFeedPrefix   = "ruleset://tag/"
+	tagFeedSuffix   = "/combined"
+	tagFeedTemplate = tagFeedPrefix + "{tag}" + tagFeedSuffix
+)
+
+// registerFeedResources exposes the catalog and per-tag combined feeds as resources
+func (h *Handler) registerFeedResources(s *server.MCPServer) {
+	catalog := mcp.NewResource(
+		catalogURI,
+		"Ruleset Catalog",
+		mcp.WithResourceDescription("Index of every ruleset with its description, tags, version and resource URI"),
+		mcp.WithMIMEType(ruleset.ContentTypeMarkdown),
+	)
+	s.AddResource(catalog, h.handleCatalogRead)
+
+	tagFeed := mcp.NewResourceTemplate(
+		tagFeedTemplate,
+		"Rulesets by Tag",
+		mcp.WithTemplateDescription("All rulesets carrying a tag, combined into one document in name order"),
+		mcp.WithTemplateMIMEType(ruleset.ContentTypeMarkdown),
+	)
+	s.AddResourceTemplate(tagFeed, h.handleTagFeedRead)
+}
+
+// HandleCatalogRead handles resource reads of the ruleset catalog (exported for testing)
+func (h *Handler) HandleCatalogRead(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return h.handleCatalogRead(ctx, req)
+}
+
+// handleCatalogRead lists every ruleset with a link to its resource
+func (h *Handler) handleCatalogRead(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	rulesets, err := h.rulesetService.Search("*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rulesets: %w", err)
+	}
+
+	sortByName(rulesets)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Ruleset Catalog\n\n%d ruleset(s).\n", len(rulesets))
+	for _, rs := range rulesets {
+		fmt.Fprintf(&b, "\n- [%s](ruleset://%s): %s\n", rs.Name, rs.Name, rs.Description)
+		fmt.Fprintf(&b, "  Tags: %s; Type: %s; Version: %d; Modified: %s\n",
+			strings.Join(rs.Tags, ", "), mimeType(rs), rs.Version, rs.LastModified.Format("2006-01-02 15:04:05"))
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: ruleset.ContentTypeMarkdown,
+			Text:     b.String(),
+		},
+	}, nil
+}
+
+// HandleTagFeedRead handles resource reads of a per-tag combined feed (exported for testing)
+func (h *Handler) HandleTagFeedRead(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return h.handleTagFeedRead(ctx, req)
+}
+
+// handleTagFeedRead combines every ruleset carrying the tag into one markdown document.
+// Each ruleset keeps its metadata header; non-markdown content is fenced with its content type.
+func (h *Handler) handleTagFeedRead(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	tag, err := tagFromFeedURI(req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesets, err := h.rulesetService.Find(ruleset.Filter{Pattern: "*", Tags: []string{tag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rulesets tagged '%s': %w", tag, err)
+	}
+	if len(rulesets) == 0 {
+		return nil, fmt.Errorf("no rulesets tagged '%s'", tag)
+	}
+
+	sortByName(rulesets)
+
+	parts := make([]string, 0, len(rulesets))
+	for _, rs := range rulesets {
+		content := h.expandSnippets(rs)
+		if mimeType(rs) != ruleset.ContentTypeMarkdown {
+			content = fmt.Sprintf("```%s\n%s\n```\n", rs.ContentType, strings.TrimRight(content, "\n"))
+		}
+		parts = append(parts, formatRuleset(rs, content, nil, ""))
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: ruleset.ContentTypeMarkdown,
+			Text:     strings.Join(parts, "\n\n"),
+		},
+	}, nil
+}
+
+// sortByName orders rulesets by name, since key scans return them in arbitrary order
+func sortByName(rulesets []*ruleset.Ruleset) {
+	slices.SortFunc(rulesets, func(a, b *ruleset.Ruleset) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// tagFromFeedURI extracts the unescaped tag from ruleset://tag/{tag}/combined
+func tagFromFeedURI(uri string) (string, error) {
+	rest, ok := strings.CutPrefix(uri, tagFeedPrefix)
+	if !ok {
+		return "", fmt.Errorf("invalid URI format: %s", uri)
+	}
+	escaped, ok := strings.CutSuffix(rest, tagFeedSuffix)
+	if !ok || escaped == "" || strings.Contains(escaped, "/") {
+		return "", fmt.Errorf("invalid URI format: %s", uri)
+	}
+	tag, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", fmt.Errorf("invalid tag in URI %s: %w", uri, err)
+	}
+	return tag, nil
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCatalogRead(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	modified := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	mockService.On("Search", "*").Return([]*ruleset.Ruleset{
+		{Name: "python_style", Description: "Python style", Tags: []string{"python"}, Version: 1, LastModified: modified},
+		{Name: "go_style", Description: "Go style", Tags: []string{"go", "style"}, Version: 3, LastModified: modified},
+	}, nil)
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = catalogURI
+
+	result, err := handler.HandleCatalogRead(context.TODO(), req)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	contents := result[0].(mcp.TextResourceContents)
+	assert.Equal(t, catalogURI, contents.URI)
+	assert.Equal(t, "# Ruleset Catalog\n\n2 ruleset(s).\n"+
+		"\n- [go_style](ruleset://go_style): Go style\n"+
+		"  Tags: go, style; Type: text/markdown; Version: 3; Modified: 2025-03-01 09:30:00\n"+
+		"\n- [python_style](ruleset://python_style): Python style\n"+
+		"  Tags: python; Type: text/markdown; Version: 1; Modified: 2025-03-01 09:30:00\n", contents.Text)
+}
+
+func TestHandleTagFeedRead(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	mockService.On("Find", ruleset.Filter{Pattern: "*", Tags: []string{"c++"}}).Return([]*ruleset.Ruleset{
+		{Name: "cpp_style", Description: "C++ style", Tags: []string{"c++"}, Markdown: "# C++ Style\n"},
+		{Name: "cpp_lint", Description: "Lint config", Tags: []string{"c++"}, ContentType: ruleset.ContentTypeJSON, Markdown: `{"strict":true}`},
+	}, nil)
+	mockService.On("Find", ruleset.Filter{Pattern: "*", Tags: []string{"cobol"}}).Return([]*ruleset.Ruleset{}, nil)
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "ruleset://tag/c%2B%2B/combined"
+
+	result, err := handler.HandleTagFeedRead(context.TODO(), req)
+	require.NoError(t, err)
+	text := result[0].(mcp.TextResourceContents).Text
+	assert.Contains(t, text, "name: cpp_lint")
+	assert.Contains(t, text, "```application/json\n{\"strict\":true}\n```\n")
+	assert.Contains(t, text, "name: cpp_style")
+	assert.Less(t, strings.Index(text, "cpp_lint"), strings.Index(text, "cpp_style"))
+
+	req.Params.URI = "ruleset://tag/cobol/combined"
+	_, err = handler.HandleTagFeedRead(context.TODO(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no rulesets tagged 'cobol'")
+}
+
+func TestTagFromFeedURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{uri: "ruleset://tag/go/combined", want: "go"},
+		{uri: "ruleset://tag/c%23/combined", want: "c#"},
+		{uri: "ruleset://tag//combined", wantErr: true},
+		{uri: "ruleset://tag/go/style/combined", wantErr: true},
+		{uri: "ruleset://tag/go", wantErr: true},
+		{uri: "ruleset://go", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			tag, err := tagFromFeedURI(tt.uri)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tag)
+		})
+	}
+}
@@ -3,9 +3,22 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
-
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/marker"
+	"github.com/jbrinkman/archivyr/internal/metrics"
+	"github.com/jbrinkman/archivyr/internal/pack"
+	"github.com/jbrinkman/archivyr/internal/prompt"
 	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/search"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/jbrinkman/archivyr/internal/source"
+	"github.com/jbrinkman/archivyr/internal/util"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog/log"
@@ -13,31 +26,110 @@ import (
 
 // Handler manages MCP protocol interactions for ruleset operations
 type Handler struct {
-	rulesetService ruleset.ServiceInterface
-	server         *server.MCPServer
+	rulesetService  ruleset.ServiceInterface
+	promptService   prompt.ServiceInterface
+	snippetService  snippet.ServiceInterface
+	searchService   search.ServiceInterface
+	packInstaller   pack.Installer
+	packRegistry    pack.RegistryClient
+	sourceFetcher   source.Fetcher
+	server          *server.MCPServer
+	metrics         *metrics.Metrics
+	limiter         *toolLimiter
+	maintainer      ruleset.Maintainer
+	serverConfig    map[string]any
+	cancels         *cancelRegistry
+	mandatory       []string
+	referencePolicy ruleset.ReferencePolicy
+	changesets      *changesetRegistry
+	ids             util.IDGenerator
+	markers         *events.Broker
+	markerStore     marker.ServiceInterface
+	welcome         string
+	legacyURIPolicy LegacyURIPolicy
+	indexStatus     ruleset.IndexStatusReporter
+}
+
+// Name and version the server reports to clients
+const (
+	serverName    = "MCP Ruleset Server"
+	serverVersion = "1.0.0"
+)
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithMetrics records tool activity in the given Prometheus metrics
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(h *Handler) {
+		h.metrics = m
+	}
+}
+
+// WithIDGenerator sets how the server generates IDs, e.g. of changesets
+func WithIDGenerator(ids util.IDGenerator) Option {
+	return func(h *Handler) {
+		h.ids = ids
+	}
 }
 
 // NewHandler creates a new MCP handler with the given ruleset service
-func NewHandler(service ruleset.ServiceInterface) *Handler {
-	return &Handler{
+func NewHandler(service ruleset.ServiceInterface, opts ...Option) *Handler {
+	h := &Handler{
 		rulesetService: service,
+		cancels:        newCancelRegistry(),
+		ids:            util.MustIDGenerator(util.StrategyUUID),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Start initializes the MCP server with stdio transport and starts serving requests
 func (h *Handler) Start() error {
 	log.Info().Msg("Initializing MCP server")
+	s := h.NewServer()
+
+	log.Info().Msg("Starting MCP server with stdio transport")
+
+	// Start server with stdio transport
+	// This is a blocking call that handles MCP protocol communication
+	if err := server.ServeStdio(s); err != nil {
+		log.Error().Err(err).Msg("MCP server error")
+		return fmt.Errorf("failed to serve stdio: %w", err)
+	}
+
+	log.Info().Msg("MCP server stopped")
+	return nil
+}
+
+// NewServer creates the MCP server Start serves, with its hooks, middleware,
+// resources, tools and prompts, without connecting a transport. A handler
+// backs one server at a time, since prompt changes are applied to the latest.
+func (h *Handler) NewServer() *server.MCPServer {
+	// Give tool calls a context the client can cancel
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(h.cancels.tagRequest)
 
 	// Create MCP server with capabilities
-	s := server.NewMCPServer(
-		"MCP Ruleset Server",
-		"1.0.0",
+	serverOptions := []server.ServerOption{
 		server.WithToolCapabilities(true),
 		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(true),
 		server.WithLogging(),
-	)
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(h.cancels.cancelTools),
+		server.WithToolHandlerMiddleware(h.validateTools),
+		server.WithToolHandlerMiddleware(h.limitTools),
+	}
+	if h.welcome != "" {
+		serverOptions = append(serverOptions, server.WithInstructions(welcomeInstructions))
+	}
+	s := server.NewMCPServer(serverName, serverVersion, serverOptions...)
 
 	h.server = s
+	s.AddNotificationHandler(methodNotificationCancelled, h.cancels.handleCancelled)
 
 	log.Info().Msg("Registering resources")
 	h.RegisterResources(s)
@@ -45,30 +137,31 @@ func (h *Handler) Start() error {
 	log.Info().Msg("Registering tools")
 	h.RegisterTools(s)
 
-	log.Info().Msg("Starting MCP server with stdio transport")
-
-	// Start server with stdio transport
-	// This is a blocking call that handles MCP protocol communication
-	if err := server.ServeStdio(s); err != nil {
-		log.Error().Err(err).Msg("MCP server error")
-		return fmt.Errorf("failed to serve stdio: %w", err)
-	}
+	log.Info().Msg("Registering prompts")
+	h.RegisterPrompts(s)
 
-	log.Info().Msg("MCP server stopped")
-	return nil
+	return s
 }
 
-// RegisterResources registers ruleset resources with the MCP server
+// RegisterResources registers ruleset, feed, schema and welcome resources with the MCP server
 func (h *Handler) RegisterResources(s *server.MCPServer) {
 	// Register resource template for ruleset retrieval by name
-	resource := mcp.NewResource(
-		"ruleset://{name}",
+	resource := mcp.NewResourceTemplate(
+		"ruleset://{name}{?section,raw,vars,expand_snippets,offset,limit,unit,usage_header}",
 		"Ruleset",
-		mcp.WithResourceDescription("AI editor ruleset with metadata and markdown content"),
-		mcp.WithMIMEType("text/markdown"),
+		mcp.WithTemplateDescription("AI editor ruleset with metadata and markdown content; non-markdown artifacts are returned verbatim with their own MIME type"),
+		mcp.WithTemplateMIMEType("text/markdown"),
 	)
 
-	s.AddResource(resource, h.handleResourceRead)
+	s.AddResourceTemplate(resource, h.handleResourceRead)
+	h.registerLegacyURIResource(s)
+
+	h.registerFeedResources(s)
+	h.registerSchemaResources(s)
+
+	if h.welcome != "" {
+		h.registerWelcomeResource(s)
+	}
 }
 
 // HandleResourceRead handles resource read requests for rulesets (exported for testing)
@@ -78,31 +171,72 @@ func (h *Handler) HandleResourceRead(ctx context.Context, req mcp.ReadResourceRe
 
 // handleResourceRead handles resource read requests for rulesets
 func (h *Handler) handleResourceRead(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	// Extract ruleset name from URI
-	// URI format: "ruleset://{name}" or "ruleset:{name}"
+	// Extract ruleset name and read options from URI
+	// URI format: "ruleset://{name}" or "ruleset:{name}", optionally followed by a query
 	uri := req.Params.URI
-	name := extractNameFromURI(uri)
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	name := extractNameFromURI(path)
 
 	if name == "" {
 		return nil, fmt.Errorf("invalid URI format: %s", uri)
 	}
 
+	var deprecation map[string]any
+	if isLegacyURI(path) {
+		var err error
+		if deprecation, err = h.checkLegacyURI(uri, name); err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI query: %w", err)
+	}
+	opts, err := readOptionsFromQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
 	// Retrieve ruleset from service
 	rs, err := h.rulesetService.Get(name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve ruleset: %w", err)
 	}
+	content, page, err := h.applyReadOptions(rs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Markdown gets a metadata header unless raw is requested; other content
+	// types are returned verbatim so the body stays valid for its MIME type
+	if !opts.Raw && (rs.ContentType == ruleset.ContentTypeMarkdown || rs.ContentType == "") {
+		content = formatRuleset(rs, content, page, "")
+	}
 
-	// Format response with metadata and markdown content
-	content := formatRulesetAsMarkdown(rs)
+	contents := mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: mimeType(rs),
+		Text:     content,
+	}
+	if page != nil {
+		contents.Meta = page.meta()
+	}
+	if deprecation != nil {
+		if contents.Meta == nil {
+			contents.Meta = make(map[string]any)
+		}
+		contents.Meta["deprecation"] = deprecation
+	}
+	return []mcp.ResourceContents{contents}, nil
+}
 
-	return []mcp.ResourceContents{
-		mcp.TextResourceContents{
-			URI:      uri,
-			MIMEType: "text/markdown",
-			Text:     content,
-		},
-	}, nil
+// mimeType returns the MIME type of a ruleset's content, defaulting to markdown
+func mimeType(rs *ruleset.Ruleset) string {
+	if rs.ContentType == "" {
+		return ruleset.ContentTypeMarkdown
+	}
+	return rs.ContentType
 }
 
 // extractNameFromURI extracts the ruleset name from the URI
@@ -121,52 +255,151 @@ func extractNameFromURI(uri string) string {
 
 // formatRulesetAsMarkdown formats a ruleset with metadata as markdown
 func formatRulesetAsMarkdown(rs *ruleset.Ruleset) string {
+	return formatRuleset(rs, rs.Markdown, nil, "")
+}
+
+// formatRuleset formats the metadata of rs followed by content, a rendering of
+// its body. The header describes the stored ruleset and, for a chunk, the page.
+// A non-empty contentChecksum is reported when it differs from the stored one.
+func formatRuleset(rs *ruleset.Ruleset, content string, page *readPage, contentChecksum string) string {
+	// Optional metadata is only called out in the header when set
+	optional := ""
+	if mimeType(rs) != ruleset.ContentTypeMarkdown {
+		optional += fmt.Sprintf("content_type: %s\n", rs.ContentType)
+	}
+	if rs.License != "" {
+		optional += fmt.Sprintf("license: %s\n", rs.License)
+	}
+	if rs.Owner != "" {
+		optional += fmt.Sprintf("owner: %s\n", rs.Owner)
+	}
+	if rs.Team != "" {
+		optional += fmt.Sprintf("team: %s\n", rs.Team)
+	}
+	if rs.SourceURL != "" {
+		optional += fmt.Sprintf("source_url: %s\nimported_at: %s\n", rs.SourceURL, rs.ImportedAt.Format("2006-01-02 15:04:05"))
+	}
+	// The content checksum and page follow the stored checksum they qualify
+	trailer := ""
+	if contentChecksum != "" && contentChecksum != rs.Checksum() {
+		trailer = fmt.Sprintf("content_checksum: %s\n", contentChecksum)
+	}
+	if page != nil {
+		trailer += page.header()
+	}
+
 	// Format metadata header
 	metadata := fmt.Sprintf(`---
 name: %s
 description: %s
 tags: %v
-created_at: %s
+%screated_at: %s
 last_modified: %s
----
+version: %d
+checksum: %s
+%s---
 
-`, rs.Name, rs.Description, rs.Tags, rs.CreatedAt.Format("2006-01-02 15:04:05"), rs.LastModified.Format("2006-01-02 15:04:05"))
+`, rs.Name, rs.Description, rs.Tags, optional, rs.CreatedAt.Format("2006-01-02 15:04:05"), rs.LastModified.Format("2006-01-02 15:04:05"),
+		rs.Version, rs.Checksum(), trailer)
 
 	// Append markdown content
-	return metadata + rs.Markdown
+	return metadata + content
 }
 
 // RegisterTools registers all CRUD tools with the MCP server
 func (h *Handler) RegisterTools(s *server.MCPServer) {
 	// Register upsert_ruleset tool (replaces create_ruleset and update_ruleset)
-	upsertTool := mcp.NewTool("upsert_ruleset",
+	upsertTool := mcp.NewTool("upsert_ruleset", append([]mcp.ToolOption{
 		mcp.WithDescription("Create a new ruleset or update an existing one. For new rulesets, all fields are required. For existing rulesets, only name is required and other fields are optional updates."),
-		mcp.WithString("name", mcp.Required(), mcp.Description("Snake_case ruleset name")),
-		mcp.WithString("description", mcp.Description("Brief description of the ruleset (required for new rulesets)")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snake_case ruleset name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithString("description", mcp.Description("Brief description of the ruleset (required for new rulesets)"), mcp.MaxLength(maxTextLength)),
 		mcp.WithString("markdown", mcp.Description("Ruleset content in markdown format (required for new rulesets)")),
-	)
+		mcp.WithString("content_type",
+			mcp.Description("MIME type of the content. Defaults to text/markdown for new rulesets."),
+			mcp.Enum(ruleset.SupportedContentTypes...),
+		),
+		mcp.WithString("license", mcp.Description("SPDX license expression describing reuse terms (e.g., 'MIT', 'Apache-2.0 OR MIT'). Pass an empty string to clear it."), mcp.MaxLength(maxTextLength)),
+		tagsParam("Tags for categorization; replaces the existing tags when given"),
+		mcp.WithString("source_url", mcp.Description("URL the content was imported from (file, Git or web). Recording a source marks the ruleset as imported; pass an empty string to detach it. Changing or detaching the source of an imported ruleset requires force."), mcp.MaxLength(maxURLLength)),
+		mcp.WithBoolean("force", mcp.Description("Allow modifying a ruleset imported from an external source")),
+	}, h.changesetParams()...)...)
 	s.AddTool(upsertTool, h.handleUpsertRuleset)
 
 	// Register get_ruleset tool
-	getTool := mcp.NewTool("get_ruleset",
+	getTool := mcp.NewTool("get_ruleset", append([]mcp.ToolOption{
 		mcp.WithDescription("Retrieve a ruleset by exact name"),
-		mcp.WithString("name", mcp.Required(), mcp.Description("Exact ruleset name")),
-	)
+		mcp.WithString("name", mcp.Required(), mcp.Description("Exact ruleset name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithNumber("if_version_not", mcp.Description("Version the client already has; returns a short 'unchanged' response instead of the content when it is still current"), mcp.Min(1)),
+		mcp.WithString("if_checksum_not", mcp.Description("Content checksum the client already has (content_checksum from the header when present, else checksum); returns a short 'unchanged' response instead of the content when it is still current"), mcp.MaxLength(maxTextLength)),
+	}, readOptionParams()...)...)
 	s.AddTool(getTool, h.handleGetRuleset)
 
 	// Register delete_ruleset tool
-	deleteTool := mcp.NewTool("delete_ruleset",
-		mcp.WithDescription("Delete a ruleset by name"),
-		mcp.WithString("name", mcp.Required(), mcp.Description("Ruleset name to delete")),
-	)
+	deleteTool := mcp.NewTool("delete_ruleset", append([]mcp.ToolOption{
+		mcp.WithDescription("Delete a ruleset by name. Links to it from other rulesets (ruleset://name) are handled per the server's reference policy: the deletion is refused, reported, or the links are removed."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Ruleset name to delete"), mcp.MaxLength(maxNameLength)),
+		mcp.WithBoolean("force", mcp.Description("Allow deleting a ruleset imported from an external source")),
+	}, h.changesetParams()...)...)
 	s.AddTool(deleteTool, h.handleDeleteRuleset)
 
 	// Register search_rulesets tool (replaces list_rulesets)
-	searchTool := mcp.NewTool("search_rulesets",
-		mcp.WithDescription("Search rulesets by name pattern. Omit pattern or use '*' to list all rulesets."),
-		mcp.WithString("pattern", mcp.Description("Glob pattern (e.g., '*python*', 'style_*'). Defaults to '*' to list all rulesets.")),
-	)
+	searchTool := mcp.NewTool("search_rulesets", append([]mcp.ToolOption{
+		mcp.WithDescription("Search rulesets by name pattern. Omit pattern or use '*' to list all rulesets. All given filters are combined with AND."),
+	}, searchFilterParams()...)...)
 	s.AddTool(searchTool, h.handleSearchRulesets)
+
+	h.registerOwnershipTools(s)
+	h.registerBulkTool(s)
+	h.registerMandatoryTool(s)
+
+	if h.changesets != nil {
+		h.registerChangesetTools(s)
+	}
+
+	if h.markers != nil {
+		h.registerMarkerTools(s)
+	}
+
+	if h.promptService != nil {
+		h.registerPromptTools(s)
+	}
+
+	if h.snippetService != nil {
+		h.registerSnippetTools(s)
+	}
+
+	if h.searchService != nil {
+		h.registerSearchTools(s)
+	}
+
+	if h.packInstaller != nil {
+		h.registerPackTools(s)
+	}
+
+	if h.sourceFetcher != nil {
+		h.registerRefreshTools(s)
+	}
+
+	if h.maintainer != nil {
+		h.registerAdminTools(s)
+	}
+
+	h.registerServerConfigTool(s)
+}
+
+// searchFilterParams declares the filter parameters shared by search_rulesets and save_search
+func searchFilterParams() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("query", mcp.Description("Query combining all filters in one string instead of the parameters below, e.g. 'tag:python license:MIT modified:>-7d style'. Fields: name:, tag:, license:, owner:, team:, created:>, modified:> and modified:<; bare words match the name, description or tags."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("pattern", mcp.Description("Glob pattern (e.g., '*python*', 'style_*'). Defaults to '*' to list all rulesets."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("license", mcp.Description("Only return rulesets whose license expression references this SPDX identifier (e.g., 'MIT')"), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("owner", mcp.Description("Only return rulesets owned by this owner"), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("team", mcp.Description("Only return rulesets assigned to this team"), mcp.MaxLength(maxTextLength)),
+		tagsParam("Only return rulesets carrying all of these tags"),
+		mcp.WithString("created_after", mcp.Description("Only return rulesets created after this date (YYYY-MM-DD, RFC3339 or relative like '-7d')"), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("modified_after", mcp.Description("Only return rulesets last modified after this date (YYYY-MM-DD, RFC3339 or relative like '-7d')"), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("modified_before", mcp.Description("Only return rulesets last modified before this date (YYYY-MM-DD, RFC3339 or relative like '-7d')"), mcp.MaxLength(maxTextLength)),
+	}
 }
 
 // HandleUpsertRuleset handles the upsert_ruleset tool invocation (exported for testing)
@@ -203,35 +436,54 @@ func (h *Handler) handleUpsertRuleset(_ context.Context, req mcp.CallToolRequest
 		updates.Markdown = &markdown
 	}
 
+	if contentType, ok := args["content_type"].(string); ok && contentType != "" {
+		rs.ContentType = contentType
+		updates.ContentType = &contentType
+	}
+
+	if license, ok := args["license"].(string); ok {
+		rs.License = license
+		updates.License = &license
+	}
+
+	if sourceURL, ok := args["source_url"].(string); ok {
+		rs.SourceURL = sourceURL
+		updates.SourceURL = &sourceURL
+	}
+
+	updates.Force = req.GetBool("force", false)
+
 	// Extract optional tags parameter
-	if tagsParam, ok := args["tags"]; ok {
-		if tagsList, ok := tagsParam.([]interface{}); ok {
-			tags := make([]string, 0, len(tagsList))
-			for _, tag := range tagsList {
-				if tagStr, ok := tag.(string); ok {
-					tags = append(tags, tagStr)
-				}
-			}
-			rs.Tags = tags
-			updates.Tags = &tags
+	if raw, ok := args["tags"]; ok {
+		tags, err := parseTags(raw)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
+		rs.Tags = tags
+		updates.Tags = &tags
 	} else {
 		rs.Tags = []string{}
 	}
 
+	if id := req.GetString("changeset", ""); id != "" {
+		return h.stageChange(id, ruleset.Change{Kind: ruleset.ChangeUpsert, Name: name, Ruleset: rs, Updates: updates}), nil
+	}
+
 	// Perform upsert
-	err = h.rulesetService.Upsert(rs, updates)
+	created, err := h.rulesetService.Upsert(rs, updates)
+	if result := queuedResult(err, fmt.Sprintf("upsert of ruleset '%s'", name)); result != nil {
+		return result, nil
+	}
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to upsert ruleset: %v", err)), nil
 	}
 
-	// Check if it was a create or update to provide appropriate message
-	exists, _ := h.rulesetService.Exists(name)
-	if exists {
-		return mcp.NewToolResultText(fmt.Sprintf("Successfully upserted ruleset '%s'", name)), nil
+	// Report whether the atomic upsert created or updated the ruleset
+	action := "updated"
+	if created {
+		action = "created"
 	}
-
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully upserted ruleset '%s'", name)), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully upserted ruleset '%s' (%s)", name, action)), nil
 }
 
 // HandleGetRuleset handles the get_ruleset tool invocation (exported for testing)
@@ -253,9 +505,63 @@ func (h *Handler) handleGetRuleset(_ context.Context, req mcp.CallToolRequest) (
 		return mcp.NewToolResultError(fmt.Sprintf("failed to retrieve ruleset: %v", err)), nil
 	}
 
+	opts, err := readOptionsFromRequest(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	checksum := h.contentChecksum(rs, opts)
+	if unchanged(req, rs, checksum) {
+		return mcp.NewToolResultText(fmt.Sprintf("Ruleset '%s' is unchanged (version %d, checksum %s)", rs.Name, rs.Version, checksum)), nil
+	}
+	content, page, err := h.applyReadOptions(rs, opts)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Format response
-	content := formatRulesetAsMarkdown(rs)
-	return mcp.NewToolResultText(content), nil
+	if opts.Raw {
+		result := mcp.NewToolResultText(content)
+		if page != nil {
+			result.Meta = mcp.NewMetaFromMap(page.meta())
+		}
+		return result, nil
+	}
+	return mcp.NewToolResultText(formatRuleset(rs, content, page, checksum)), nil
+}
+
+// contentChecksum returns the checksum of the body get_ruleset serves for rs:
+// that of the stored content, or of the content with included snippets
+// expanded when expansion is requested and changes it
+func (h *Handler) contentChecksum(rs *ruleset.Ruleset, opts readOptions) string {
+	if !opts.ExpandSnippets {
+		return rs.Checksum()
+	}
+	expanded := h.expandSnippets(rs)
+	if expanded == rs.Markdown {
+		return rs.Checksum()
+	}
+	served := *rs
+	served.Markdown = expanded
+	return served.Checksum()
+}
+
+// unchanged reports whether the client already holds the content it would be
+// served: at least one of if_version_not and if_checksum_not is given and all
+// given values match. checksum is the content checksum; as the version does
+// not change when an included snippet does, it only vouches for content
+// without expanded snippets.
+func unchanged(req mcp.CallToolRequest, rs *ruleset.Ruleset, checksum string) bool {
+	args := req.GetArguments()
+	_, hasVersion := args["if_version_not"]
+	ifChecksumNot := req.GetString("if_checksum_not", "")
+	if !hasVersion && ifChecksumNot == "" {
+		return false
+	}
+	if hasVersion && (int64(req.GetFloat("if_version_not", 0)) != rs.Version || checksum != rs.Checksum()) {
+		return false
+	}
+	return ifChecksumNot == "" || ifChecksumNot == checksum
 }
 
 // HandleDeleteRuleset handles the delete_ruleset tool invocation (exported for testing)
@@ -271,13 +577,61 @@ func (h *Handler) handleDeleteRuleset(_ context.Context, req mcp.CallToolRequest
 		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
 	}
 
+	if id := req.GetString("changeset", ""); id != "" {
+		opts := ruleset.DeleteOptions{Force: req.GetBool("force", false)}
+		return h.stageChange(id, ruleset.Change{Kind: ruleset.ChangeDelete, Name: name, DeleteOptions: opts}), nil
+	}
+
+	// Check links to the ruleset before it is gone
+	var referencing []string
+	if h.referencePolicy != "" {
+		referencing, err = h.references(name)
+		if err != nil && h.referencePolicy == ruleset.ReferencePolicyBlock {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("ruleset", name).Msg("Deleting ruleset without checking references")
+		}
+		if len(referencing) > 0 && h.referencePolicy == ruleset.ReferencePolicyBlock {
+			return mcp.NewToolResultError(fmt.Sprintf("ruleset '%s' is still referenced by: %s; remove the references before deleting it",
+				name, strings.Join(referencing, ", "))), nil
+		}
+	}
+
 	// Delete ruleset
-	err = h.rulesetService.Delete(name)
+	if req.GetBool("force", false) {
+		err = h.rulesetService.DeleteWithOptions(name, ruleset.DeleteOptions{Force: true})
+	} else {
+		err = h.rulesetService.Delete(name)
+	}
+	if result := queuedResult(err, fmt.Sprintf("deletion of ruleset '%s'", name)); result != nil {
+		return result, nil
+	}
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to delete ruleset: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted ruleset '%s'", name)), nil
+	message := fmt.Sprintf("Successfully deleted ruleset '%s'", name)
+	switch {
+	case len(referencing) == 0:
+	case h.referencePolicy == ruleset.ReferencePolicyCascade:
+		message += "\n" + h.unlink(name, referencing)
+	default:
+		message += fmt.Sprintf("\nWarning: it is still referenced by: %s", strings.Join(referencing, ", "))
+	}
+	return mcp.NewToolResultText(message), nil
+}
+
+// queuedResult reports a change accepted into the write queue while Valkey is
+// unreachable, or returns nil when err is not a *ruleset.QueuedError
+func queuedResult(err error, change string) *mcp.CallToolResult {
+	var queued *ruleset.QueuedError
+	if !errors.As(err, &queued) {
+		return nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(
+		"Valkey is unreachable, so the %s was queued (%d change(s) pending). It will be applied when the connection returns.",
+		change, queued.Pending))
 }
 
 // HandleSearchRulesets handles the search_rulesets tool invocation (exported for testing)
@@ -287,25 +641,72 @@ func (h *Handler) HandleSearchRulesets(ctx context.Context, req mcp.CallToolRequ
 
 // handleSearchRulesets handles the search_rulesets tool invocation
 func (h *Handler) handleSearchRulesets(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Extract optional pattern parameter, default to "*" for listing all
-	args := req.GetArguments()
-	pattern := "*"
-	if patternArg, ok := args["pattern"].(string); ok && patternArg != "" {
-		pattern = patternArg
+	criteria, err := searchCriteria(req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid search criteria: %v", err)), nil
 	}
 
-	// Search rulesets
-	rulesets, err := h.rulesetService.Search(pattern)
+	filter, err := criteria.Filter(time.Now())
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to search rulesets: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("invalid search criteria: %v", err)), nil
 	}
 
+	return h.runSearch(filter), nil
+}
+
+// searchCriteria reads the search filter parameters shared by search_rulesets and save_search.
+// A query string replaces the individual filter parameters.
+func searchCriteria(req mcp.CallToolRequest) (search.Criteria, error) {
+	criteria := search.Criteria{
+		Pattern:        req.GetString("pattern", ""),
+		License:        req.GetString("license", ""),
+		Owner:          req.GetString("owner", ""),
+		Team:           req.GetString("team", ""),
+		CreatedAfter:   req.GetString("created_after", ""),
+		ModifiedAfter:  req.GetString("modified_after", ""),
+		ModifiedBefore: req.GetString("modified_before", ""),
+	}
+	tags, err := parseTags(req.GetArguments()["tags"])
+	if err != nil {
+		return search.Criteria{}, err
+	}
+	criteria.Tags = tags
+
+	query := req.GetString("query", "")
+	if query == "" {
+		return criteria, nil
+	}
+	if criteria.Pattern != "" || criteria.License != "" || criteria.Owner != "" || criteria.Team != "" || len(criteria.Tags) > 0 ||
+		criteria.CreatedAfter != "" || criteria.ModifiedAfter != "" || criteria.ModifiedBefore != "" {
+		return search.Criteria{}, fmt.Errorf("use either query or the individual filter parameters, not both")
+	}
+	return search.Parse(query)
+}
+
+// runSearch finds the rulesets selected by filter and formats them as a tool result
+func (h *Handler) runSearch(filter ruleset.Filter) *mcp.CallToolResult {
+	pattern := filter.Pattern
+
+	// Search rulesets, applying metadata filters when given
+	var rulesets []*ruleset.Ruleset
+	var err error
+	if !filter.HasCriteria() {
+		rulesets, err = h.rulesetService.Search(pattern)
+	} else {
+		rulesets, err = h.rulesetService.Find(filter)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search rulesets: %v", err))
+	}
+
+	h.metrics.ObserveSearchResults(len(rulesets))
+
 	// Format response
 	if len(rulesets) == 0 {
 		if pattern == "*" {
-			return mcp.NewToolResultText("No rulesets found"), nil
+			return mcp.NewToolResultText("No rulesets found")
 		}
-		return mcp.NewToolResultText(fmt.Sprintf("No rulesets found matching pattern '%s'", pattern)), nil
+		return mcp.NewToolResultText(fmt.Sprintf("No rulesets found matching pattern '%s'", pattern))
 	}
 
 	var result string
@@ -320,10 +721,16 @@ func (h *Handler) handleSearchRulesets(_ context.Context, req mcp.CallToolReques
 		if len(rs.Tags) > 0 {
 			result += fmt.Sprintf("  Tags: %v\n", rs.Tags)
 		}
+		if rs.License != "" {
+			result += fmt.Sprintf("  License: %s\n", rs.License)
+		}
+		if rs.Owner != "" || rs.Team != "" {
+			result += fmt.Sprintf("  Owner: %s, Team: %s\n", orNone(rs.Owner), orNone(rs.Team))
+		}
 		result += fmt.Sprintf("  Created: %s, Modified: %s\n\n",
 			rs.CreatedAt.Format("2006-01-02 15:04:05"),
 			rs.LastModified.Format("2006-01-02 15:04:05"))
 	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultText(result)
 }
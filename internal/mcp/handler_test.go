@@ -2,13 +2,20 @@ package mcp
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jbrinkman/archivyr/internal/metrics"
 	"github.com/jbrinkman/archivyr/internal/ruleset"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockRulesetService is a mock implementation of the ruleset service interface
@@ -37,9 +44,9 @@ func (m *MockRulesetService) Update(name string, updates *ruleset.Update) error
 	return args.Error(0)
 }
 
-func (m *MockRulesetService) Upsert(rs *ruleset.Ruleset, updates *ruleset.Update) error {
+func (m *MockRulesetService) Upsert(rs *ruleset.Ruleset, updates *ruleset.Update) (bool, error) {
 	args := m.Called(rs, updates)
-	return args.Error(0)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *MockRulesetService) Delete(name string) error {
@@ -47,6 +54,11 @@ func (m *MockRulesetService) Delete(name string) error {
 	return args.Error(0)
 }
 
+func (m *MockRulesetService) DeleteWithOptions(name string, opts ruleset.DeleteOptions) error {
+	args := m.Called(name, opts)
+	return args.Error(0)
+}
+
 func (m *MockRulesetService) List() ([]*ruleset.Ruleset, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -63,6 +75,14 @@ func (m *MockRulesetService) Search(pattern string) ([]*ruleset.Ruleset, error)
 	return args.Get(0).([]*ruleset.Ruleset), args.Error(1)
 }
 
+func (m *MockRulesetService) Find(filter ruleset.Filter) ([]*ruleset.Ruleset, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ruleset.Ruleset), args.Error(1)
+}
+
 func (m *MockRulesetService) Exists(name string) (bool, error) {
 	args := m.Called(name)
 	return args.Bool(0), args.Error(1)
@@ -140,6 +160,21 @@ func TestFormatRulesetAsMarkdown(t *testing.T) {
 	assert.Contains(t, result, "Some content here")
 }
 
+// Test non-markdown content types are called out in the metadata header
+func TestFormatRulesetAsMarkdown_ContentType(t *testing.T) {
+	rs := &ruleset.Ruleset{
+		Name:        "plain_notes",
+		ContentType: ruleset.ContentTypePlainText,
+		Markdown:    "plain text",
+	}
+
+	result := formatRulesetAsMarkdown(rs)
+	assert.Contains(t, result, "content_type: text/plain\n")
+
+	rs.ContentType = ruleset.ContentTypeMarkdown
+	assert.NotContains(t, formatRulesetAsMarkdown(rs), "content_type")
+}
+
 // Test RegisterTools doesn't panic
 func TestRegisterTools(t *testing.T) {
 	mockService := new(MockRulesetService)
@@ -197,9 +232,8 @@ func TestHandleUpsertRuleset_Create(t *testing.T) {
 	mockService := new(MockRulesetService)
 	handler := NewHandler(mockService)
 
-	// Mock the Upsert call to succeed
-	mockService.On("Upsert", mock.AnythingOfType("*ruleset.Ruleset"), mock.AnythingOfType("*ruleset.Update")).Return(nil)
-	mockService.On("Exists", "new_ruleset").Return(true, nil)
+	// Mock the Upsert call to create the ruleset
+	mockService.On("Upsert", mock.AnythingOfType("*ruleset.Ruleset"), mock.AnythingOfType("*ruleset.Update")).Return(true, nil)
 
 	// Create a mock request
 	req := mcp.CallToolRequest{}
@@ -216,7 +250,7 @@ func TestHandleUpsertRuleset_Create(t *testing.T) {
 	// Verify
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully upserted ruleset 'new_ruleset'")
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully upserted ruleset 'new_ruleset' (created)")
 	mockService.AssertExpectations(t)
 }
 
@@ -225,9 +259,8 @@ func TestHandleUpsertRuleset_Update(t *testing.T) {
 	mockService := new(MockRulesetService)
 	handler := NewHandler(mockService)
 
-	// Mock the Upsert call to succeed
-	mockService.On("Upsert", mock.AnythingOfType("*ruleset.Ruleset"), mock.AnythingOfType("*ruleset.Update")).Return(nil)
-	mockService.On("Exists", "existing_ruleset").Return(true, nil)
+	// Mock the Upsert call to update the existing ruleset
+	mockService.On("Upsert", mock.AnythingOfType("*ruleset.Ruleset"), mock.AnythingOfType("*ruleset.Update")).Return(false, nil)
 
 	// Create a mock request with only partial updates
 	req := mcp.CallToolRequest{}
@@ -242,10 +275,43 @@ func TestHandleUpsertRuleset_Update(t *testing.T) {
 	// Verify
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully upserted ruleset 'existing_ruleset'")
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully upserted ruleset 'existing_ruleset' (updated)")
 	mockService.AssertExpectations(t)
 }
 
+// Test HandleUpsertRuleset normalizes comma-separated tags
+func TestHandleUpsertRuleset_CommaSeparatedTags(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	mockService.On("Upsert", mock.MatchedBy(func(rs *ruleset.Ruleset) bool {
+		return assert.ObjectsAreEqual([]string{"go", "style"}, rs.Tags)
+	}), mock.MatchedBy(func(u *ruleset.Update) bool {
+		return u.Tags != nil && assert.ObjectsAreEqual([]string{"go", "style"}, *u.Tags)
+	})).Return(false, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"name": "existing_ruleset",
+		"tags": "go, style",
+	}
+
+	result, err := handler.HandleUpsertRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	mockService.AssertExpectations(t)
+
+	// Invalid tags are rejected before reaching the service
+	req.Params.Arguments = map[string]interface{}{
+		"name": "existing_ruleset",
+		"tags": []interface{}{"code style"},
+	}
+	result, err = handler.HandleUpsertRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid tag 'code style'")
+}
+
 // Test HandleUpsertRuleset with missing name
 func TestHandleUpsertRuleset_MissingName(t *testing.T) {
 	mockService := new(MockRulesetService)
@@ -274,7 +340,7 @@ func TestHandleUpsertRuleset_ServiceError(t *testing.T) {
 	handler := NewHandler(mockService)
 
 	// Mock the Upsert call to fail
-	mockService.On("Upsert", mock.AnythingOfType("*ruleset.Ruleset"), mock.AnythingOfType("*ruleset.Update")).Return(assert.AnError)
+	mockService.On("Upsert", mock.AnythingOfType("*ruleset.Ruleset"), mock.AnythingOfType("*ruleset.Update")).Return(false, assert.AnError)
 
 	// Create a mock request
 	req := mcp.CallToolRequest{}
@@ -323,6 +389,55 @@ func TestHandleGetRuleset_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestHandleGetRuleset_Conditional(t *testing.T) {
+	rs := &ruleset.Ruleset{
+		Name:        "test_ruleset",
+		Description: "Test description",
+		ContentType: ruleset.ContentTypeMarkdown,
+		Markdown:    "# Test",
+		Version:     3,
+	}
+
+	testCases := []struct {
+		name      string
+		args      map[string]interface{}
+		unchanged bool
+	}{
+		{"no condition", map[string]interface{}{}, false},
+		{"current version", map[string]interface{}{"if_version_not": float64(3)}, true},
+		{"older version", map[string]interface{}{"if_version_not": float64(2)}, false},
+		{"current checksum", map[string]interface{}{"if_checksum_not": rs.Checksum()}, true},
+		{"stale checksum", map[string]interface{}{"if_checksum_not": "abc"}, false},
+		{"version matches but checksum does not", map[string]interface{}{"if_version_not": float64(3), "if_checksum_not": "abc"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockRulesetService)
+			handler := NewHandler(mockService)
+			mockService.On("Get", "test_ruleset").Return(rs, nil)
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]interface{}{"name": "test_ruleset"}
+			for k, v := range tc.args {
+				req.Params.Arguments.(map[string]interface{})[k] = v
+			}
+
+			result, err := handler.HandleGetRuleset(context.TODO(), req)
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tc.unchanged {
+				assert.Equal(t, "Ruleset 'test_ruleset' is unchanged (version 3, checksum "+rs.Checksum()+")", text)
+			} else {
+				assert.Contains(t, text, "version: 3\nchecksum: "+rs.Checksum())
+				assert.Contains(t, text, "# Test")
+			}
+		})
+	}
+}
+
 // Test HandleGetRuleset with missing name
 func TestHandleGetRuleset_MissingName(t *testing.T) {
 	mockService := new(MockRulesetService)
@@ -381,6 +496,27 @@ func TestHandleDeleteRuleset_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// Test HandleDeleteRuleset while Valkey is unreachable and the change is queued
+func TestHandleDeleteRuleset_Queued(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	mockService.On("Delete", "test_ruleset").Return(&ruleset.QueuedError{Pending: 2, Err: errors.New("connection refused")})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"name": "test_ruleset",
+	}
+
+	result, err := handler.HandleDeleteRuleset(context.TODO(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Valkey is unreachable, so the deletion of ruleset 'test_ruleset' was queued (2 change(s) pending). It will be applied when the connection returns.",
+		result.Content[0].(mcp.TextContent).Text)
+	mockService.AssertExpectations(t)
+}
+
 // Test HandleDeleteRuleset with missing name
 func TestHandleDeleteRuleset_MissingName(t *testing.T) {
 	mockService := new(MockRulesetService)
@@ -574,6 +710,117 @@ func TestHandleResourceRead_Success(t *testing.T) {
 }
 
 // Test HandleResourceRead with invalid URI
+func TestHandleResourceRead_NonMarkdownContentType(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	rs := &ruleset.Ruleset{
+		Name:        "api_schema",
+		Description: "Request schema",
+		ContentType: ruleset.ContentTypeJSONSchema,
+		Markdown:    `{"type":"object"}`,
+	}
+
+	mockService.On("Get", "api_schema").Return(rs, nil)
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "ruleset://api_schema"
+
+	result, err := handler.HandleResourceRead(context.TODO(), req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	contents := result[0].(mcp.TextResourceContents)
+	assert.Equal(t, ruleset.ContentTypeJSONSchema, contents.MIMEType)
+	assert.Equal(t, `{"type":"object"}`, contents.Text)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleResourceRead_QueryOptions(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	rs := &ruleset.Ruleset{
+		Name:        "go_rules",
+		Description: "Go conventions",
+		Markdown:    "# Go\n\n## Testing\n\nRun tests for {{project}}.\n\n## Style\n\nUse gofmt.\n",
+	}
+	mockService.On("Get", "go_rules").Return(rs, nil)
+
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "section, raw and vars",
+			uri:  "ruleset://go_rules?section=testing&raw=true&vars=project%3Dacme",
+			want: "## Testing\n\nRun tests for acme.\n\n",
+		},
+		{
+			name: "header kept without raw",
+			uri:  "ruleset:go_rules?section=style",
+			want: "---\nname: go_rules\n",
+		},
+		{name: "unknown section", uri: "ruleset://go_rules?section=deploy", wantErr: "section 'deploy' not found in ruleset 'go_rules'"},
+		{name: "invalid boolean", uri: "ruleset://go_rules?raw=maybe", wantErr: "invalid raw 'maybe'"},
+		{name: "invalid vars", uri: "ruleset://go_rules?vars=project", wantErr: "invalid vars 'project'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.ReadResourceRequest{}
+			req.Params.URI = tt.uri
+
+			result, err := handler.HandleResourceRead(context.TODO(), req)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			contents := result[0].(mcp.TextResourceContents)
+			assert.Equal(t, tt.uri, contents.URI)
+			if strings.HasPrefix(tt.want, "---") {
+				assert.True(t, strings.HasPrefix(contents.Text, tt.want))
+				assert.True(t, strings.HasSuffix(contents.Text, "## Style\n\nUse gofmt.\n"))
+			} else {
+				assert.Equal(t, tt.want, contents.Text)
+			}
+		})
+	}
+}
+
+func TestHandleGetRuleset_ReadOptions(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	rs := &ruleset.Ruleset{
+		Name:     "go_rules",
+		Markdown: "# Go\n\n## Testing\n\nRun tests for {{project}} in {{ env }}.\n",
+	}
+	mockService.On("Get", "go_rules").Return(rs, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"name":    "go_rules",
+		"section": "Testing",
+		"raw":     true,
+		"vars":    map[string]interface{}{"project": "acme"},
+	}
+	result, err := handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "## Testing\n\nRun tests for acme in {{ env }}.\n", result.Content[0].(mcp.TextContent).Text)
+
+	req.Params.Arguments = map[string]interface{}{"name": "go_rules", "vars": map[string]interface{}{"project": 1.0}}
+	result, err = handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "vars.project must be a string")
+}
+
 func TestHandleResourceRead_InvalidURI(t *testing.T) {
 	mockService := new(MockRulesetService)
 	handler := NewHandler(mockService)
@@ -605,3 +852,203 @@ func TestHandleResourceRead_ServiceError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to retrieve ruleset")
 	mockService.AssertExpectations(t)
 }
+
+// Test that search records metrics; content sizes are recorded by service hooks
+func TestHandler_RecordsMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.New(reg)
+	require.NoError(t, err)
+
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService, WithMetrics(m))
+
+	mockService.On("Search", "*").Return([]*ruleset.Ruleset{{Name: "a"}, {Name: "b"}}, nil)
+
+	_, err = handler.HandleSearchRulesets(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+
+	expected := `
+# HELP archivyr_search_results Number of rulesets returned per search.
+# TYPE archivyr_search_results histogram
+archivyr_search_results_bucket{le="0"} 0
+archivyr_search_results_bucket{le="1"} 0
+archivyr_search_results_bucket{le="2"} 1
+archivyr_search_results_bucket{le="5"} 1
+archivyr_search_results_bucket{le="10"} 1
+archivyr_search_results_bucket{le="20"} 1
+archivyr_search_results_bucket{le="50"} 1
+archivyr_search_results_bucket{le="100"} 1
+archivyr_search_results_bucket{le="200"} 1
+archivyr_search_results_bucket{le="500"} 1
+archivyr_search_results_bucket{le="1000"} 1
+archivyr_search_results_bucket{le="+Inf"} 1
+archivyr_search_results_sum 2
+archivyr_search_results_count 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "archivyr_search_results"))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSearchRulesets_LicenseFilter(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	rulesets := []*ruleset.Ruleset{
+		{Name: "mit_rules", Description: "MIT rules", License: "MIT"},
+	}
+	mockService.On("Find", ruleset.Filter{Pattern: "*", License: "MIT"}).Return(rulesets, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"license": "MIT"}
+
+	result, err := handler.HandleSearchRulesets(context.TODO(), req)
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "mit_rules")
+	assert.Contains(t, text, "License: MIT")
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSearchRulesets_CombinedFilters(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	filter := ruleset.Filter{
+		Pattern:        "go_*",
+		Tags:           []string{"go", "style"},
+		CreatedAfter:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ModifiedAfter:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		ModifiedBefore: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+	}
+	mockService.On("Find", filter).Return([]*ruleset.Ruleset{{Name: "go_rules", Description: "Go"}}, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"pattern":         "go_*",
+		"tags":            []interface{}{"go", "style"},
+		"created_after":   "2024-01-01",
+		"modified_after":  "2024-03-01",
+		"modified_before": "2024-03-15T12:00:00Z",
+	}
+
+	result, err := handler.HandleSearchRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "go_rules")
+	mockService.AssertExpectations(t)
+
+	// Comma-separated and repeated tags normalize to the same filter
+	req.Params.Arguments = map[string]interface{}{
+		"pattern":         "go_*",
+		"tags":            "go, style,Go",
+		"created_after":   "2024-01-01",
+		"modified_after":  "2024-03-01",
+		"modified_before": "2024-03-15T12:00:00Z",
+	}
+	result, err = handler.HandleSearchRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	mockService.AssertNumberOfCalls(t, "Find", 2)
+
+	req.Params.Arguments = map[string]interface{}{"modified_after": "last tuesday"}
+	result, err = handler.HandleSearchRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid modified_after")
+}
+
+func TestHandleSearchRulesets_Query(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	filter := ruleset.Filter{
+		Pattern:       "*",
+		Tags:          []string{"python"},
+		Text:          []string{"style"},
+		ModifiedAfter: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("Find", filter).Return([]*ruleset.Ruleset{{Name: "python_style", Description: "Python"}}, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"query": "tag:python modified:>2024-01-01 style"}
+	result, err := handler.HandleSearchRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "python_style")
+	mockService.AssertExpectations(t)
+
+	testCases := []struct {
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{"query": "status:published"}, "unknown field 'status'"},
+		{map[string]interface{}{"query": "tag:go", "license": "MIT"}, "use either query or the individual filter parameters"},
+	}
+	for _, tc := range testCases {
+		req.Params.Arguments = tc.args
+		result, err = handler.HandleSearchRulesets(context.TODO(), req)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.wantErr)
+	}
+}
+
+func TestHandleUpsertRuleset_License(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	license := "Apache-2.0"
+	mockService.On("Upsert", mock.MatchedBy(func(rs *ruleset.Ruleset) bool {
+		return rs.License == license
+	}), mock.MatchedBy(func(u *ruleset.Update) bool {
+		return u.License != nil && *u.License == license
+	})).Return(false, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "licensed_rules", "license": license}
+
+	result, err := handler.HandleUpsertRuleset(context.TODO(), req)
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	mockService.AssertExpectations(t)
+}
+
+func TestFormatRulesetAsMarkdown_License(t *testing.T) {
+	rs := &ruleset.Ruleset{Name: "licensed_rules", ContentType: ruleset.ContentTypeMarkdown, License: "MIT", Markdown: "# Rules"}
+	assert.Contains(t, formatRulesetAsMarkdown(rs), "license: MIT\n")
+
+	rs.License = ""
+	assert.NotContains(t, formatRulesetAsMarkdown(rs), "license:")
+}
+
+func TestHandleDeleteRuleset_Force(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	mockService.On("DeleteWithOptions", "community_rules", ruleset.DeleteOptions{Force: true}).Return(nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "community_rules", "force": true}
+
+	result, err := handler.HandleDeleteRuleset(context.TODO(), req)
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	mockService.AssertExpectations(t)
+}
+
+func TestFormatRulesetAsMarkdown_Provenance(t *testing.T) {
+	rs := &ruleset.Ruleset{
+		Name:        "community_rules",
+		ContentType: ruleset.ContentTypeMarkdown,
+		SourceURL:   "https://example.com/rules.md",
+		ImportedAt:  time.Date(2025, 10, 29, 10, 0, 0, 0, time.UTC),
+	}
+
+	formatted := formatRulesetAsMarkdown(rs)
+	assert.Contains(t, formatted, "source_url: https://example.com/rules.md\n")
+	assert.Contains(t, formatted, "imported_at: 2025-10-29 10:00:00\n")
+}
@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// NewInProcessClient creates the server Start serves and returns a client
+// connected to it through an in-memory transport, already initialized. Tests
+// and programs embedding the server use it to exercise every registered tool,
+// resource and prompt, including the middleware, without spawning a stdio
+// subprocess. Progress notifications are not delivered to the client. Close
+// the client when done.
+func (h *Handler) NewInProcessClient(ctx context.Context) (*client.Client, error) {
+	c, err := client.NewInProcessClient(h.NewServer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-process client: %w", err)
+	}
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start in-process client: %w", err)
+	}
+
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "archivyr-in-process", Version: serverVersion}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("failed to initialize in-process client: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInProcessClient(t *testing.T) {
+	ctx := context.Background()
+	mockService := new(MockRulesetService)
+	rs := &ruleset.Ruleset{Name: "go_rules", Description: "Go conventions", Markdown: "# Go\n"}
+	mockService.On("Get", "go_rules").Return(rs, nil)
+
+	c, err := NewHandler(mockService, WithWelcome("# Welcome\n")).NewInProcessClient(ctx)
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+
+	t.Run("lists registered tools and resources", func(t *testing.T) {
+		tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+		require.NoError(t, err)
+		names := make([]string, 0, len(tools.Tools))
+		for _, tool := range tools.Tools {
+			names = append(names, tool.Name)
+		}
+		assert.Contains(t, names, "get_ruleset")
+		assert.Contains(t, names, "server_config")
+
+		resources, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(resources.Resources))
+		for _, resource := range resources.Resources {
+			uris = append(uris, resource.URI)
+		}
+		assert.Contains(t, uris, welcomeURI)
+	})
+
+	t.Run("calls tools through the middleware", func(t *testing.T) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "get_ruleset"
+		req.Params.Arguments = map[string]any{"name": "go_rules", "raw": true}
+		result, err := c.CallTool(ctx, req)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Equal(t, "# Go\n", result.Content[0].(mcp.TextContent).Text)
+
+		// Arguments are validated against the tool schema before the handler runs
+		req.Params.Arguments = map[string]any{}
+		result, err = c.CallTool(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid arguments: name: is required")
+	})
+
+	t.Run("reads resources", func(t *testing.T) {
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = welcomeURI
+		result, err := c.ReadResource(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, result.Contents, 1)
+		assert.Equal(t, "# Welcome\n", result.Contents[0].(mcp.TextResourceContents).Text)
+	})
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNewInProcessClient_RulesetResource(t *testing.T) {
+	ctx := context.Background()
+	mockService := new(MockRulesetService)
+	rs := &ruleset.Ruleset{Name: "go_rules", Description: "Go conventions", Tags: []string{"go"}, Markdown: "# Go\n\n## Testing\n\nRun go test.\n"}
+	mockService.On("Get", "go_rules").Return(rs, nil)
+	mockService.On("Find", ruleset.Filter{Pattern: "*", Tags: []string{"go"}}).Return([]*ruleset.Ruleset{rs}, nil)
+
+	c, err := NewHandler(mockService).NewInProcessClient(ctx)
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+
+	templates, err := c.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	require.NoError(t, err)
+	uris := make([]string, 0, len(templates.ResourceTemplates))
+	for _, template := range templates.ResourceTemplates {
+		uris = append(uris, template.URITemplate.Raw())
+	}
+	assert.Contains(t, uris, "ruleset://{name}{?section,raw,vars,expand_snippets,offset,limit,unit,usage_header}")
+
+	read := func(uri string) string {
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = uri
+		result, err := c.ReadResource(ctx, req)
+		require.NoError(t, err, uri)
+		require.Len(t, result.Contents, 1)
+		return result.Contents[0].(mcp.TextResourceContents).Text
+	}
+
+	assert.Equal(t, "## Testing\n\nRun go test.\n", read("ruleset://go_rules?section=testing&raw=true"))
+	assert.Contains(t, read("ruleset://go_rules"), "name: go_rules\n")
+
+	// Tag feeds and static resources are not taken for rulesets, whatever the
+	// order templates are matched in
+	for range 10 {
+		assert.Contains(t, read("ruleset://tag/go/combined"), "Run go test.")
+		assert.Contains(t, read(schemaURIPrefix+"ruleset"), "$schema")
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// LegacyURIPolicy selects how resource reads using the legacy single-colon
+// form ruleset:{name} are handled
+type LegacyURIPolicy string
+
+// Legacy URI policies
+const (
+	// LegacyURIAllow serves legacy URIs without notice
+	LegacyURIAllow LegacyURIPolicy = "allow"
+	// LegacyURIWarn serves legacy URIs, logging a warning and flagging the result
+	LegacyURIWarn LegacyURIPolicy = "warn"
+	// LegacyURIReject fails reads of legacy URIs
+	LegacyURIReject LegacyURIPolicy = "reject"
+)
+
+// WithLegacyURIPolicy sets how reads of ruleset:{name} URIs are handled.
+// Without it they are allowed.
+func WithLegacyURIPolicy(policy LegacyURIPolicy) Option {
+	return func(h *Handler) {
+		h.legacyURIPolicy = policy
+	}
+}
+
+// registerLegacyURIResource routes reads of legacy ruleset:{name} URIs to the
+// ruleset resource handler, which applies the legacy URI policy
+func (h *Handler) registerLegacyURIResource(s *server.MCPServer) {
+	legacy := mcp.NewResourceTemplate(
+		"ruleset:{name}{?section,raw,vars,expand_snippets,offset,limit,unit,usage_header}",
+		"Ruleset (deprecated URI form)",
+		mcp.WithTemplateDescription("Deprecated form of ruleset://{name}; use ruleset://{name} instead"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+	s.AddResourceTemplate(legacy, h.handleResourceRead)
+}
+
+// isLegacyURI reports whether uri uses the single-colon form
+func isLegacyURI(uri string) bool {
+	return strings.HasPrefix(uri, "ruleset:") && !strings.HasPrefix(uri, "ruleset://")
+}
+
+// checkLegacyURI applies the legacy URI policy to a read of uri, a legacy URI
+// naming name. It returns the deprecation notice to attach to the result, if any.
+func (h *Handler) checkLegacyURI(uri, name string) (map[string]any, error) {
+	h.metrics.ObserveLegacyURI()
+	replacement := "ruleset://" + name
+
+	switch h.legacyURIPolicy {
+	case LegacyURIReject:
+		return nil, fmt.Errorf("the URI form ruleset:{name} is no longer supported; use %s", replacement)
+	case LegacyURIWarn:
+		log.Warn().Str("uri", uri).Str("replacement", replacement).Msg("Resource read with deprecated URI form")
+		return map[string]any{
+			"message":     "the URI form ruleset:{name} is deprecated and will be removed; use ruleset://{name}",
+			"replacement": replacement,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/metrics"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleResourceRead_LegacyURIPolicy(t *testing.T) {
+	rs := &ruleset.Ruleset{Name: "go_rules", Markdown: "# Go\n"}
+
+	tests := []struct {
+		name            string
+		policy          LegacyURIPolicy
+		uri             string
+		wantErr         string
+		wantDeprecation bool
+	}{
+		{name: "unset allows", uri: "ruleset:go_rules"},
+		{name: "allow", policy: LegacyURIAllow, uri: "ruleset:go_rules"},
+		{name: "warn", policy: LegacyURIWarn, uri: "ruleset:go_rules", wantDeprecation: true},
+		{name: "reject", policy: LegacyURIReject, uri: "ruleset:go_rules", wantErr: "use ruleset://go_rules"},
+		{name: "current form unaffected by reject", policy: LegacyURIReject, uri: "ruleset://go_rules"},
+		{name: "current form unaffected by warn", policy: LegacyURIWarn, uri: "ruleset://go_rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRulesetService)
+			mockService.On("Get", "go_rules").Return(rs, nil).Maybe()
+			handler := NewHandler(mockService, WithLegacyURIPolicy(tt.policy))
+
+			req := mcp.ReadResourceRequest{}
+			req.Params.URI = tt.uri
+			result, err := handler.HandleResourceRead(context.TODO(), req)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			contents := result[0].(mcp.TextResourceContents)
+			if !tt.wantDeprecation {
+				assert.NotContains(t, contents.Meta, "deprecation")
+				return
+			}
+			deprecation, ok := contents.Meta["deprecation"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "ruleset://go_rules", deprecation["replacement"])
+			assert.Contains(t, deprecation["message"], "deprecated")
+		})
+	}
+}
+
+func TestHandleResourceRead_LegacyURIKeepsPageMeta(t *testing.T) {
+	mockService := new(MockRulesetService)
+	mockService.On("Get", "go_rules").Return(&ruleset.Ruleset{Name: "go_rules", Markdown: "# Go\n\nUse gofmt.\n"}, nil)
+	handler := NewHandler(mockService, WithLegacyURIPolicy(LegacyURIWarn))
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "ruleset:go_rules?limit=4"
+	result, err := handler.HandleResourceRead(context.TODO(), req)
+	require.NoError(t, err)
+
+	meta := result[0].(mcp.TextResourceContents).Meta
+	assert.Contains(t, meta, "deprecation")
+	assert.Greater(t, len(meta), 1)
+}
+
+func TestHandleResourceRead_LegacyURIMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.New(reg)
+	require.NoError(t, err)
+
+	mockService := new(MockRulesetService)
+	mockService.On("Get", "go_rules").Return(&ruleset.Ruleset{Name: "go_rules", Markdown: "# Go\n"}, nil)
+	handler := NewHandler(mockService, WithMetrics(m), WithLegacyURIPolicy(LegacyURIReject))
+
+	for _, uri := range []string{"ruleset:go_rules", "ruleset://go_rules", "ruleset:go_rules"} {
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = uri
+		_, _ = handler.HandleResourceRead(context.TODO(), req)
+	}
+
+	expected := `
+# HELP archivyr_legacy_uri_reads_total Resource reads using the deprecated ruleset:{name} URI form.
+# TYPE archivyr_legacy_uri_reads_total counter
+archivyr_legacy_uri_reads_total 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "archivyr_legacy_uri_reads_total"))
+}
+
+func TestLegacyURI_InProcess(t *testing.T) {
+	ctx := context.Background()
+	read := func(policy LegacyURIPolicy, m *metrics.Metrics, uri string) (*mcp.ReadResourceResult, error) {
+		mockService := new(MockRulesetService)
+		mockService.On("Get", "go_rules").Return(&ruleset.Ruleset{Name: "go_rules", Markdown: "# Go\n\n## Style\n\nUse gofmt.\n"}, nil).Maybe()
+		c, err := NewHandler(mockService, WithMetrics(m), WithLegacyURIPolicy(policy)).NewInProcessClient(ctx)
+		require.NoError(t, err)
+		defer func() { _ = c.Close() }()
+
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = uri
+		return c.ReadResource(ctx, req)
+	}
+
+	reg := prometheus.NewRegistry()
+	m, err := metrics.New(reg)
+	require.NoError(t, err)
+
+	result, err := read(LegacyURIWarn, m, "ruleset:go_rules?section=style&raw=true")
+	require.NoError(t, err)
+	contents := result.Contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, "## Style\n\nUse gofmt.\n", contents.Text)
+	deprecation, ok := contents.Meta["deprecation"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "ruleset://go_rules", deprecation["replacement"])
+
+	_, err = read(LegacyURIReject, m, "ruleset:go_rules")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use ruleset://go_rules")
+
+	// The current form is never counted
+	_, err = read(LegacyURIReject, m, "ruleset://go_rules")
+	require.NoError(t, err)
+
+	expected := `
+# HELP archivyr_legacy_uri_reads_total Resource reads using the deprecated ruleset:{name} URI form.
+# TYPE archivyr_legacy_uri_reads_total counter
+archivyr_legacy_uri_reads_total 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "archivyr_legacy_uri_reads_total"))
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// toolLimiter bounds the number of tool invocations running at once.
+// Calls beyond the limit queue for a free slot until the timeout expires.
+type toolLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// WithConcurrencyLimit caps concurrent tool invocations at limit. Further calls
+// wait up to timeout for a slot and are then rejected with a "server busy" error.
+// A limit of zero or less disables the cap.
+func WithConcurrencyLimit(limit int, timeout time.Duration) Option {
+	return func(h *Handler) {
+		if limit <= 0 {
+			h.limiter = nil
+			return
+		}
+		h.limiter = &toolLimiter{
+			slots:   make(chan struct{}, limit),
+			timeout: timeout,
+		}
+	}
+}
+
+// acquire waits for a free slot and returns its release function, or an error
+// when the timeout expires or ctx is cancelled first
+func (l *toolLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() { <-l.slots }
+
+	// Fast path without allocating a timer
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("server is busy: %d tool calls already running; retry later", cap(l.slots))
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// limitTools is tool middleware enforcing the concurrency limit
+func (h *Handler) limitTools(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if h.limiter == nil {
+		return next
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		release, err := h.limiter.acquire(ctx)
+		if err != nil {
+			log.Warn().Err(err).Str("tool", req.Params.Name).Msg("Rejected tool call")
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer release()
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitTools(t *testing.T) {
+	t.Run("caps concurrent calls and queues the rest", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService), WithConcurrencyLimit(2, time.Second))
+
+		var running, peak atomic.Int32
+		tool := handler.limitTools(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		var wg sync.WaitGroup
+		results := make([]*mcp.CallToolResult, 6)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = tool(context.TODO(), mcp.CallToolRequest{})
+			}()
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			require.NotNil(t, result)
+			assert.False(t, result.IsError)
+		}
+		assert.LessOrEqual(t, peak.Load(), int32(2))
+	})
+
+	t.Run("rejects calls after the queue timeout", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService), WithConcurrencyLimit(1, 20*time.Millisecond))
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		tool := handler.limitTools(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-release
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = tool(context.TODO(), mcp.CallToolRequest{})
+		}()
+		<-started
+
+		result, err := tool(context.TODO(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "server is busy: 1 tool calls already running")
+
+		close(release)
+		<-done
+	})
+
+	t.Run("stops waiting when the request is cancelled", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService), WithConcurrencyLimit(1, time.Minute))
+		handler.limiter.slots <- struct{}{}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		result, err := handler.limitTools(nil)(ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "context canceled")
+	})
+
+	t.Run("zero limit leaves tools unwrapped", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService), WithConcurrencyLimit(0, time.Second))
+		assert.Nil(t, handler.limiter)
+	})
+}
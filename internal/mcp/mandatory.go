@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// WithMandatoryRulesets sets the rulesets every agent must follow, highest
+// priority first, returned together by get_mandatory_rulesets
+func WithMandatoryRulesets(names []string) Option {
+	return func(h *Handler) {
+		h.mandatory = names
+	}
+}
+
+// registerMandatoryTool registers the get_mandatory_rulesets tool
+func (h *Handler) registerMandatoryTool(s *server.MCPServer) {
+	mandatoryTool := mcp.NewTool("get_mandatory_rulesets",
+		mcp.WithDescription("Retrieve the rulesets this organisation requires every agent to follow (e.g. security and licensing policies), highest priority first. Call it at the start of every task; where rulesets conflict, the earlier one wins."),
+		mcp.WithBoolean("usage_header", mcp.Description("Prepend a machine-readable usage header to the content of each ruleset, as in get_ruleset")),
+	)
+	s.AddTool(mandatoryTool, h.handleGetMandatoryRulesets)
+}
+
+// HandleGetMandatoryRulesets handles the get_mandatory_rulesets tool invocation (exported for testing)
+func (h *Handler) HandleGetMandatoryRulesets(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleGetMandatoryRulesets(ctx, req)
+}
+
+// handleGetMandatoryRulesets combines every mandatory ruleset into one markdown
+// document in priority order, like the tag feeds. Rulesets that cannot be read
+// are reported at the top rather than failing the call, so agents still receive
+// the remaining policies.
+func (h *Handler) handleGetMandatoryRulesets(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if len(h.mandatory) == 0 {
+		return mcp.NewToolResultText("No mandatory rulesets are configured"), nil
+	}
+
+	withUsage := req.GetBool("usage_header", false)
+	now := time.Now()
+
+	var b strings.Builder
+	b.WriteString("Mandatory rulesets, highest priority first:\n")
+	parts := make([]string, 0, len(h.mandatory))
+	for i, name := range h.mandatory {
+		rs, err := h.rulesetService.Get(name)
+		if err != nil {
+			log.Warn().Err(err).Str("ruleset", name).Msg("Mandatory ruleset is unavailable")
+			fmt.Fprintf(&b, "%d. %s (WARNING: unavailable: %v)\n", i+1, name, err)
+			continue
+		}
+		fmt.Fprintf(&b, "%d. %s\n", i+1, name)
+
+		content := h.expandSnippets(rs)
+		if mimeType(rs) != ruleset.ContentTypeMarkdown {
+			content = fmt.Sprintf("```%s\n%s\n```\n", rs.ContentType, strings.TrimRight(content, "\n"))
+		}
+		if withUsage {
+			content = usageHeader(rs, now) + content
+		}
+		parts = append(parts, formatRuleset(rs, content, nil, ""))
+	}
+
+	return mcp.NewToolResultText(b.String() + "\n" + strings.Join(parts, "\n\n")), nil
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetMandatoryRulesets(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService, WithMandatoryRulesets([]string{"security_policy", "licensing_policy", "lint_config"}))
+
+	security := &ruleset.Ruleset{Name: "security_policy", Version: 2, Markdown: "# Security\nNever log secrets.\n"}
+	lint := &ruleset.Ruleset{Name: "lint_config", Version: 1, ContentType: "application/json", Markdown: `{"strict": true}`}
+	mockService.On("Get", "security_policy").Return(security, nil)
+	mockService.On("Get", "licensing_policy").Return(nil, errors.New("ruleset 'licensing_policy' not found"))
+	mockService.On("Get", "lint_config").Return(lint, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"usage_header": true}
+	result, err := handler.HandleGetMandatoryRulesets(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+
+	assert.Contains(t, text, "Mandatory rulesets, highest priority first:\n"+
+		"1. security_policy\n"+
+		"2. licensing_policy (WARNING: unavailable: ruleset 'licensing_policy' not found)\n"+
+		"3. lint_config\n")
+	assert.Less(t, indexOf(t, text, "name: security_policy"), indexOf(t, text, "name: lint_config"))
+	assert.Contains(t, text, "---\n\n<!-- archivyr-usage: {\"uri\":\"ruleset://security_policy\"")
+	// Non-markdown content is fenced, so the usage header fits JSON too
+	assert.Contains(t, text, "<!-- archivyr-usage: {\"uri\":\"ruleset://lint_config\"")
+	assert.Contains(t, text, "```application/json\n{\"strict\": true}\n```\n")
+}
+
+func TestHandleGetMandatoryRulesets_NoneConfigured(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService))
+
+	result, err := handler.HandleGetMandatoryRulesets(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "No mandatory rulesets are configured", result.Content[0].(mcp.TextContent).Text)
+}
+
+// indexOf returns the position of substr in s, failing the test when it is missing
+func indexOf(t *testing.T, s, substr string) int {
+	t.Helper()
+	i := strings.Index(s, substr)
+	require.GreaterOrEqual(t, i, 0, "missing %q", substr)
+	return i
+}
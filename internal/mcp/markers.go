@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/marker"
+	"github.com/jbrinkman/archivyr/internal/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// maxMarkerNoteLength bounds marker notes, which are short labels
+const maxMarkerNoteLength = 200
+
+// WithMarkers enables the mark tool, which records annotated checkpoints in
+// store and publishes them to broker alongside the change events, and the
+// list_markers tool reading them back
+func WithMarkers(broker *events.Broker, store marker.ServiceInterface) Option {
+	return func(h *Handler) {
+		h.markers = broker
+		h.markerStore = store
+	}
+}
+
+// registerMarkerTools registers the mark and list_markers tools
+func (h *Handler) registerMarkerTools(s *server.MCPServer) {
+	markTool := mcp.NewTool("mark",
+		mcp.WithDescription("Record an annotated checkpoint, e.g. 'before refactor experiment', in the change event stream, so the changes before and after it can be told apart later without relying on timestamps"),
+		mcp.WithString("note", mcp.Required(), mcp.Description("What the checkpoint marks"), mcp.MaxLength(maxMarkerNoteLength)),
+	)
+	s.AddTool(markTool, h.handleMark)
+
+	listTool := mcp.NewTool("list_markers",
+		mcp.WithDescription("List recorded markers in time order. Their times bracket searches with modified_after and modified_before."),
+		mcp.WithString("after", mcp.Description("Only list markers recorded at or after this date (YYYY-MM-DD, RFC3339 or relative like '-7d')"), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("before", mcp.Description("Only list markers recorded at or before this date (YYYY-MM-DD, RFC3339 or relative like '-7d')"), mcp.MaxLength(maxTextLength)),
+	)
+	s.AddTool(listTool, h.handleListMarkers)
+}
+
+// HandleMark handles the mark tool invocation (exported for testing)
+func (h *Handler) HandleMark(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleMark(ctx, req)
+}
+
+// handleMark records a marker, publishes its event and logs it, so the
+// checkpoint also appears in the server log
+func (h *Handler) handleMark(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	note, err := req.RequireString("note")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'note': %v", err)), nil
+	}
+	if note == "" {
+		return mcp.NewToolResultError("note cannot be empty"), nil
+	}
+	if len(note) > maxMarkerNoteLength {
+		return mcp.NewToolResultError(fmt.Sprintf("note exceeds the maximum length of %d bytes", maxMarkerNoteLength)), nil
+	}
+
+	id, err := h.ids.NewID(note)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to record marker: %v", err)), nil
+	}
+	e := events.Marked(id, note, time.Now())
+	if err := h.markerStore.Record(&marker.Marker{ID: id, Note: note, Time: e.Time}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to record marker: %v", err)), nil
+	}
+	h.markers.Publish(e)
+	log.Info().Str("marker", id).Str("note", note).Time("time", e.Time).Msg("Marker recorded")
+
+	return mcp.NewToolResultText(fmt.Sprintf("Recorded marker '%s' at %s: %s", id, e.Time.Format(time.RFC3339), note)), nil
+}
+
+// HandleListMarkers handles the list_markers tool invocation (exported for testing)
+func (h *Handler) HandleListMarkers(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleListMarkers(ctx, req)
+}
+
+// handleListMarkers lists the markers recorded within the requested range
+func (h *Handler) handleListMarkers(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	now := time.Now()
+	var bounds [2]time.Time
+	for i, param := range []string{"after", "before"} {
+		value := req.GetString(param, "")
+		if value == "" {
+			continue
+		}
+		t, err := validation.ParseDate(value, now)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid %s: %v", param, err)), nil
+		}
+		bounds[i] = t
+	}
+
+	markers, err := h.markerStore.List(bounds[0], bounds[1])
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list markers: %v", err)), nil
+	}
+	if len(markers) == 0 {
+		return mcp.NewToolResultText("No markers found"), nil
+	}
+
+	lines := make([]string, 0, len(markers))
+	for _, m := range markers {
+		lines = append(lines, fmt.Sprintf("- %s '%s': %s", m.Time.UTC().Format(time.RFC3339), m.ID, m.Note))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Found %d marker(s):\n%s", len(markers), strings.Join(lines, "\n"))), nil
+}
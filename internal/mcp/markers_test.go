@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/events"
+	"github.com/jbrinkman/archivyr/internal/marker"
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMark(t *testing.T) {
+	broker := events.NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	store := marker.NewService(memstore.New(), "ruleset:")
+	handler := NewHandler(new(MockRulesetService), WithMarkers(broker, store), WithIDGenerator(fixedIDs("m1")))
+
+	result := callTool(t, handler.HandleMark, map[string]interface{}{"note": "before refactor experiment"})
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Recorded marker 'm1' at ")
+
+	e := <-ch
+	assert.Equal(t, events.TypeMarked, e.Type)
+	assert.Equal(t, events.Marker{ID: "m1", Note: "before refactor experiment"}, e.Data)
+
+	// The marker outlives the event
+	markers, err := store.List(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+	assert.Equal(t, "m1", markers[0].ID)
+	assert.True(t, e.Time.Equal(markers[0].Time))
+
+	for _, note := range []string{"", string(make([]byte, maxMarkerNoteLength+1))} {
+		result := callTool(t, handler.HandleMark, map[string]interface{}{"note": note})
+		assert.True(t, result.IsError)
+	}
+	assert.Empty(t, ch)
+}
+
+func TestHandleListMarkers(t *testing.T) {
+	store := marker.NewService(memstore.New(), "ruleset:")
+	handler := NewHandler(new(MockRulesetService), WithMarkers(events.NewBroker(), store))
+
+	result := callTool(t, handler.HandleListMarkers, map[string]interface{}{})
+	assert.Equal(t, "No markers found", result.Content[0].(mcp.TextContent).Text)
+
+	base := time.Date(2025, 10, 28, 15, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Record(&marker.Marker{ID: "m1", Note: "before refactor", Time: base}))
+	require.NoError(t, store.Record(&marker.Marker{ID: "m2", Note: "after refactor", Time: base.Add(2 * time.Hour)}))
+
+	result = callTool(t, handler.HandleListMarkers, map[string]interface{}{})
+	assert.Equal(t, "Found 2 marker(s):\n"+
+		"- 2025-10-28T15:00:00Z 'm1': before refactor\n"+
+		"- 2025-10-28T17:00:00Z 'm2': after refactor", result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, handler.HandleListMarkers, map[string]interface{}{"after": "2025-10-28T16:00:00Z"})
+	assert.Equal(t, "Found 1 marker(s):\n- 2025-10-28T17:00:00Z 'm2': after refactor", result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, handler.HandleListMarkers, map[string]interface{}{"before": "2025-10-28"})
+	assert.Equal(t, "No markers found", result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, handler.HandleListMarkers, map[string]interface{}{"after": "yesterday"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid after")
+}
+
+// failingMarkers fails to record and list markers
+type failingMarkers struct{}
+
+func (failingMarkers) Record(*marker.Marker) error { return errors.New("connection refused") }
+
+func (failingMarkers) List(time.Time, time.Time) ([]*marker.Marker, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestHandleMark_StoreFails(t *testing.T) {
+	broker := events.NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	handler := NewHandler(new(MockRulesetService), WithMarkers(broker, failingMarkers{}))
+
+	result := callTool(t, handler.HandleMark, map[string]interface{}{"note": "release"})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "failed to record marker: connection refused", result.Content[0].(mcp.TextContent).Text)
+	assert.Empty(t, ch)
+
+	result = callTool(t, handler.HandleListMarkers, map[string]interface{}{})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerOwnershipTools registers the set_owner and assign_team tools
+func (h *Handler) registerOwnershipTools(s *server.MCPServer) {
+	ownerTool := mcp.NewTool("set_owner",
+		mcp.WithDescription("Set or transfer the owner responsible for a ruleset. Transferring an owned ruleset requires naming its current owner."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Ruleset name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("New owner, e.g. 'alice@example.com'. Pass an empty string to clear it."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("current_owner", mcp.Description("Current owner of the ruleset; required when it already has one"), mcp.MaxLength(maxTextLength)),
+	)
+	s.AddTool(ownerTool, h.handleSetOwner)
+
+	teamTool := mcp.NewTool("assign_team",
+		mcp.WithDescription("Assign a ruleset to the team responsible for it. Reassigning an owned ruleset requires naming its current owner."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Ruleset name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithString("team", mcp.Required(), mcp.Description("Team, e.g. 'platform-team'. Pass an empty string to clear it."), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("current_owner", mcp.Description("Current owner of the ruleset; required when it has one"), mcp.MaxLength(maxTextLength)),
+	)
+	s.AddTool(teamTool, h.handleAssignTeam)
+}
+
+// HandleSetOwner handles the set_owner tool invocation (exported for testing)
+func (h *Handler) HandleSetOwner(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleSetOwner(ctx, req)
+}
+
+// handleSetOwner handles the set_owner tool invocation
+func (h *Handler) handleSetOwner(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.reassign(req, "owner", func(updates *ruleset.Update, value *string) { updates.Owner = value }), nil
+}
+
+// HandleAssignTeam handles the assign_team tool invocation (exported for testing)
+func (h *Handler) HandleAssignTeam(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleAssignTeam(ctx, req)
+}
+
+// handleAssignTeam handles the assign_team tool invocation
+func (h *Handler) handleAssignTeam(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.reassign(req, "team", func(updates *ruleset.Update, value *string) { updates.Team = value }), nil
+}
+
+// reassign updates the stewardship field named by param, which set stores in the update
+func (h *Handler) reassign(req mcp.CallToolRequest, param string, set func(*ruleset.Update, *string)) *mcp.CallToolResult {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err))
+	}
+	value, err := req.RequireString(param)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter '%s': %v", param, err))
+	}
+
+	updates := &ruleset.Update{CurrentOwner: req.GetString("current_owner", "")}
+	set(updates, &value)
+
+	err = h.rulesetService.Update(name, updates)
+	if result := queuedResult(err, fmt.Sprintf("%s change of ruleset '%s'", param, name)); result != nil {
+		return result
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set %s: %v", param, err))
+	}
+
+	if value == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully cleared the %s of ruleset '%s'", param, name))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully set the %s of ruleset '%s' to '%s'", param, name, value))
+}
+
+// orNone returns s, or "none" when it is empty
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
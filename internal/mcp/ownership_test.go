@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSetOwnerAndAssignTeam(t *testing.T) {
+	service := ruleset.NewService(memstore.New())
+	require.NoError(t, service.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go", Markdown: "# Go"}))
+	handler := NewHandler(service)
+
+	call := func(handle func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handle(context.TODO(), req)
+		require.NoError(t, err)
+		return result
+	}
+
+	// An unowned ruleset can be claimed without naming a current owner
+	result := call(handler.HandleSetOwner, map[string]any{"name": "go_rules", "owner": "alice@example.com"})
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "to 'alice@example.com'")
+
+	// Reassigning an owned ruleset requires its current owner
+	result = call(handler.HandleAssignTeam, map[string]any{"name": "go_rules", "team": "platform"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "is owned by 'alice@example.com'")
+
+	result = call(handler.HandleAssignTeam, map[string]any{"name": "go_rules", "team": "platform", "current_owner": "Alice@example.com"})
+	require.False(t, result.IsError)
+
+	result = call(handler.HandleSetOwner, map[string]any{"name": "go_rules", "owner": "bob", "current_owner": "alice@example.com"})
+	require.False(t, result.IsError)
+
+	rs, err := service.Get("go_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", rs.Owner)
+	assert.Equal(t, "platform", rs.Team)
+
+	// Invalid identifiers and missing rulesets are reported
+	result = call(handler.HandleSetOwner, map[string]any{"name": "go_rules", "owner": "bob smith", "current_owner": "bob"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "owner must be")
+
+	result = call(handler.HandleAssignTeam, map[string]any{"name": "missing", "team": "platform"})
+	require.True(t, result.IsError)
+
+	// Searches filter by owner and team
+	result = call(handler.HandleSearchRulesets, map[string]any{"query": "owner:BOB team:platform"})
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "**go_rules**")
+	assert.Contains(t, text, "Owner: bob, Team: platform")
+}
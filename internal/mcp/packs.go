@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jbrinkman/archivyr/internal/pack"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithPackInstaller enables the install_pack tool
+func WithPackInstaller(installer pack.Installer) Option {
+	return func(h *Handler) {
+		h.packInstaller = installer
+	}
+}
+
+// WithPackRegistry enables the search_packs tool and installing packs by name from a registry
+func WithPackRegistry(registry pack.RegistryClient) Option {
+	return func(h *Handler) {
+		h.packRegistry = registry
+	}
+}
+
+// registerPackTools registers the pack installation and registry tools
+func (h *Handler) registerPackTools(s *server.MCPServer) {
+	installOpts := []mcp.ToolOption{
+		mcp.WithDescription("Install a signed knowledge pack, creating or replacing every ruleset, prompt template and snippet it contains. The pack must be signed by a trusted key. Existing rulesets not installed from the same pack are left untouched and reported unless force is set."),
+		mcp.WithString("bundle", mcp.Description("Signed pack bundle JSON as produced by 'pack export'")),
+		mcp.WithBoolean("force", mcp.Description("Replace rulesets that were not installed from this pack, such as locally authored rulesets")),
+	}
+	if h.packRegistry != nil {
+		installOpts = append(installOpts,
+			mcp.WithString("name", mcp.Description("Name of a pack to download from the registry instead of passing a bundle"), mcp.MaxLength(maxNameLength)),
+			mcp.WithString("version", mcp.Description("Registry pack version (defaults to the latest)"), mcp.MaxLength(maxTextLength)),
+		)
+	}
+	s.AddTool(mcp.NewTool("install_pack", installOpts...), h.handleInstallPack)
+
+	if h.packRegistry != nil {
+		searchTool := mcp.NewTool("search_packs",
+			mcp.WithDescription("Search the pack registry for published knowledge packs"),
+			mcp.WithString("query", mcp.Description("Search terms; omit to list all packs"), mcp.MaxLength(maxTextLength)),
+		)
+		s.AddTool(searchTool, h.handleSearchPacks)
+	}
+}
+
+// HandleInstallPack handles the install_pack tool invocation (exported for testing)
+func (h *Handler) HandleInstallPack(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleInstallPack(ctx, req)
+}
+
+// handleInstallPack handles the install_pack tool invocation
+func (h *Handler) handleInstallPack(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	bundle := req.GetString("bundle", "")
+	name := req.GetString("name", "")
+
+	switch {
+	case bundle != "" && name != "":
+		return mcp.NewToolResultError("provide either 'bundle' or 'name', not both"), nil
+	case name != "":
+		if h.packRegistry == nil {
+			return mcp.NewToolResultError("no pack registry is configured; provide 'bundle' instead"), nil
+		}
+		data, err := h.packRegistry.Fetch(name, req.GetString("version", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to fetch pack: %v", err)), nil
+		}
+		bundle = string(data)
+	case bundle == "":
+		return mcp.NewToolResultError("missing required parameter 'bundle' or 'name'"), nil
+	}
+
+	result, err := h.packInstaller.Install([]byte(bundle), pack.InstallOptions{Force: req.GetBool("force", false)})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to install pack: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(formatInstallResult(result)), nil
+}
+
+// formatInstallResult summarises an installed pack
+func formatInstallResult(r *pack.Result) string {
+	summary := fmt.Sprintf("Successfully installed pack '%s' version %s: %d ruleset(s), %d prompt template(s), %d snippet(s)",
+		r.Name, r.Version, r.Rulesets, r.Templates, r.Snippets)
+	if len(r.Conflicts) > 0 {
+		summary += fmt.Sprintf("\n\nSkipped %d existing ruleset(s) not installed from this pack; install with force to replace them:\n", len(r.Conflicts))
+		for _, c := range r.Conflicts {
+			summary += fmt.Sprintf("- %s\n", c)
+		}
+	}
+	return summary
+}
+
+// HandleSearchPacks handles the search_packs tool invocation (exported for testing)
+func (h *Handler) HandleSearchPacks(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleSearchPacks(ctx, req)
+}
+
+// handleSearchPacks handles the search_packs tool invocation
+func (h *Handler) handleSearchPacks(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := req.GetString("query", "")
+
+	entries, err := h.packRegistry.Search(query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search packs: %v", err)), nil
+	}
+
+	if len(entries) == 0 {
+		return mcp.NewToolResultText("No packs found"), nil
+	}
+
+	result := fmt.Sprintf("Found %d pack(s):\n\n", len(entries))
+	for _, e := range entries {
+		result += fmt.Sprintf("- **%s** %s: %s\n", e.Name, e.Version, e.Description)
+	}
+	return mcp.NewToolResultText(result), nil
+}
@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/pack"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubInstaller records the bundle it receives and returns a fixed outcome
+type stubInstaller struct {
+	received string
+	opts     pack.InstallOptions
+	result   *pack.Result
+	err      error
+}
+
+func (s *stubInstaller) Install(data []byte, opts pack.InstallOptions) (*pack.Result, error) {
+	s.received = string(data)
+	s.opts = opts
+	return s.result, s.err
+}
+
+func TestHandleInstallPack(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		installer   *stubInstaller
+		expectError bool
+		contains    string
+	}{
+		{
+			name: "installs bundle",
+			args: map[string]interface{}{"bundle": `{"pack":{},"signature":""}`},
+			installer: &stubInstaller{result: &pack.Result{
+				Name: "go_team", Version: "1.0.0", Rulesets: 2, Templates: 1,
+			}},
+			contains: "Successfully installed pack 'go_team' version 1.0.0: 2 ruleset(s), 1 prompt template(s), 0 snippet(s)",
+		},
+		{
+			name: "reports conflicts",
+			args: map[string]interface{}{"bundle": `{"pack":{},"signature":""}`},
+			installer: &stubInstaller{result: &pack.Result{
+				Name: "go_team", Version: "1.0.0", Rulesets: 1,
+				Conflicts: []pack.Conflict{{Name: "go_style"}, {Name: "go_testing", SourceURL: "pack:qa_team@2.0.0"}},
+			}},
+			contains: "Skipped 2 existing ruleset(s) not installed from this pack; install with force to replace them:\n- go_style (locally authored)\n- go_testing (imported from pack:qa_team@2.0.0)\n",
+		},
+		{
+			name:        "missing bundle",
+			args:        map[string]interface{}{},
+			installer:   &stubInstaller{},
+			expectError: true,
+			contains:    "missing required parameter 'bundle'",
+		},
+		{
+			name:        "install failure",
+			args:        map[string]interface{}{"bundle": "{}"},
+			installer:   &stubInstaller{err: fmt.Errorf("pack signature does not match any trusted key")},
+			expectError: true,
+			contains:    "failed to install pack: pack signature does not match any trusted key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(new(MockRulesetService), WithPackInstaller(tt.installer))
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+
+			result, err := handler.HandleInstallPack(context.TODO(), req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectError, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.contains)
+		})
+	}
+}
+
+// stubRegistry serves fixed registry responses
+type stubRegistry struct {
+	entries []pack.Entry
+	bundles map[string]string
+}
+
+func (s *stubRegistry) Search(_ string) ([]pack.Entry, error) {
+	return s.entries, nil
+}
+
+func (s *stubRegistry) Fetch(name, version string) ([]byte, error) {
+	bundle, ok := s.bundles[name+"@"+version]
+	if !ok {
+		return nil, fmt.Errorf("unexpected status 404 Not Found")
+	}
+	return []byte(bundle), nil
+}
+
+func TestHandleInstallPack_FromRegistry(t *testing.T) {
+	installer := &stubInstaller{result: &pack.Result{Name: "go_team", Version: "1.0.0"}}
+	registry := &stubRegistry{bundles: map[string]string{"go_team@": "latest-bundle", "go_team@0.9.0": "old-bundle"}}
+	handler := NewHandler(new(MockRulesetService), WithPackInstaller(installer), WithPackRegistry(registry))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "go_team"}
+	result, err := handler.HandleInstallPack(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "latest-bundle", installer.received)
+
+	req.Params.Arguments = map[string]interface{}{"name": "go_team", "version": "0.9.0"}
+	_, err = handler.HandleInstallPack(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "old-bundle", installer.received)
+
+	req.Params.Arguments = map[string]interface{}{"name": "missing"}
+	result, err = handler.HandleInstallPack(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to fetch pack")
+
+	req.Params.Arguments = map[string]interface{}{"name": "go_team", "bundle": "{}"}
+	result, err = handler.HandleInstallPack(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestHandleInstallPack_NameWithoutRegistry(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService), WithPackInstaller(&stubInstaller{}))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "go_team"}
+	result, err := handler.HandleInstallPack(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "no pack registry is configured")
+}
+
+func TestHandleSearchPacks(t *testing.T) {
+	registry := &stubRegistry{entries: []pack.Entry{{Name: "go_team", Version: "1.0.0", Description: "Go rules"}}}
+	handler := NewHandler(new(MockRulesetService), WithPackInstaller(&stubInstaller{}), WithPackRegistry(registry))
+
+	result, err := handler.HandleSearchPacks(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "- **go_team** 1.0.0: Go rules")
+
+	registry.entries = nil
+	result, err = handler.HandleSearchPacks(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "No packs found", result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// WithPromptService enables prompt template tools and MCP prompt registration
+func WithPromptService(service prompt.ServiceInterface) Option {
+	return func(h *Handler) {
+		h.promptService = service
+	}
+}
+
+// RegisterPrompts registers every stored prompt template as an MCP prompt
+func (h *Handler) RegisterPrompts(s *server.MCPServer) {
+	if h.promptService == nil {
+		return
+	}
+
+	templates, err := h.promptService.List()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load prompt templates")
+		return
+	}
+
+	for _, t := range templates {
+		s.AddPrompt(toMCPPrompt(t), h.handleGetPrompt)
+	}
+}
+
+// registerPromptTools registers the prompt template CRUD tools
+func (h *Handler) registerPromptTools(s *server.MCPServer) {
+	upsertTool := mcp.NewTool("upsert_prompt_template",
+		mcp.WithDescription("Create or replace a reusable prompt template. Placeholders use {{variable}} syntax and must be declared in variables. Templates are also exposed as MCP prompts."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snake_case prompt template name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithString("description", mcp.Description("Brief description of the prompt"), mcp.MaxLength(maxTextLength)),
+		mcp.WithString("template", mcp.Required(), mcp.Description("Prompt body with {{variable}} placeholders")),
+		mcp.WithArray("variables",
+			mcp.Description("Declared variables"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":        map[string]any{"type": "string", "maxLength": maxNameLength},
+					"description": map[string]any{"type": "string", "maxLength": maxTextLength},
+					"required":    map[string]any{"type": "boolean"},
+				},
+				"required": []string{"name"},
+			}),
+		),
+	)
+	s.AddTool(upsertTool, h.handleUpsertPromptTemplate)
+
+	getTool := mcp.NewTool("get_prompt_template",
+		mcp.WithDescription("Retrieve a prompt template by exact name"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Exact prompt template name"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(getTool, h.handleGetPromptTemplate)
+
+	deleteTool := mcp.NewTool("delete_prompt_template",
+		mcp.WithDescription("Delete a prompt template by name"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Prompt template name to delete"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(deleteTool, h.handleDeletePromptTemplate)
+
+	listTool := mcp.NewTool("list_prompt_templates",
+		mcp.WithDescription("List all prompt templates"),
+	)
+	s.AddTool(listTool, h.handleListPromptTemplates)
+}
+
+// HandleUpsertPromptTemplate handles the upsert_prompt_template tool invocation (exported for testing)
+func (h *Handler) HandleUpsertPromptTemplate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleUpsertPromptTemplate(ctx, req)
+}
+
+// handleUpsertPromptTemplate handles the upsert_prompt_template tool invocation
+func (h *Handler) handleUpsertPromptTemplate(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	body, err := req.RequireString("template")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'template': %v", err)), nil
+	}
+
+	t := &prompt.Template{
+		Name:        name,
+		Description: req.GetString("description", ""),
+		Template:    body,
+		Variables:   parseVariables(req.GetArguments()["variables"]),
+	}
+
+	created, err := h.promptService.Save(t)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to save prompt template: %v", err)), nil
+	}
+
+	// Keep the MCP prompt list in sync when the server is running
+	if h.server != nil {
+		h.server.AddPrompt(toMCPPrompt(t), h.handleGetPrompt)
+	}
+
+	action := "updated"
+	if created {
+		action = "created"
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully %s prompt template '%s'", action, name)), nil
+}
+
+// HandleGetPromptTemplate handles the get_prompt_template tool invocation (exported for testing)
+func (h *Handler) HandleGetPromptTemplate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleGetPromptTemplate(ctx, req)
+}
+
+// handleGetPromptTemplate handles the get_prompt_template tool invocation
+func (h *Handler) handleGetPromptTemplate(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	t, err := h.promptService.Get(name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to retrieve prompt template: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(formatPromptTemplate(t)), nil
+}
+
+// HandleDeletePromptTemplate handles the delete_prompt_template tool invocation (exported for testing)
+func (h *Handler) HandleDeletePromptTemplate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleDeletePromptTemplate(ctx, req)
+}
+
+// handleDeletePromptTemplate handles the delete_prompt_template tool invocation
+func (h *Handler) handleDeletePromptTemplate(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	if err := h.promptService.Delete(name); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete prompt template: %v", err)), nil
+	}
+
+	if h.server != nil {
+		h.server.DeletePrompts(name)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted prompt template '%s'", name)), nil
+}
+
+// HandleListPromptTemplates handles the list_prompt_templates tool invocation (exported for testing)
+func (h *Handler) HandleListPromptTemplates(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleListPromptTemplates(ctx, req)
+}
+
+// handleListPromptTemplates handles the list_prompt_templates tool invocation
+func (h *Handler) handleListPromptTemplates(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	templates, err := h.promptService.List()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list prompt templates: %v", err)), nil
+	}
+
+	if len(templates) == 0 {
+		return mcp.NewToolResultText("No prompt templates found"), nil
+	}
+
+	result := fmt.Sprintf("Found %d prompt template(s):\n\n", len(templates))
+	for _, t := range templates {
+		result += fmt.Sprintf("- **%s**: %s\n", t.Name, t.Description)
+		if len(t.Variables) > 0 {
+			result += fmt.Sprintf("  Variables: %s\n", variableNames(t.Variables))
+		}
+	}
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// HandleGetPrompt handles MCP prompts/get requests for stored templates (exported for testing)
+func (h *Handler) HandleGetPrompt(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return h.handleGetPrompt(ctx, req)
+}
+
+// handleGetPrompt renders a stored template with the request arguments
+func (h *Handler) handleGetPrompt(_ context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	// Always read the latest version so edits apply without re-registration
+	t, err := h.promptService.Get(req.Params.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve prompt template: %w", err)
+	}
+
+	text, err := t.Render(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt template: %w", err)
+	}
+
+	return mcp.NewGetPromptResult(t.Description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
+
+// toMCPPrompt converts a stored template into an MCP prompt definition
+func toMCPPrompt(t *prompt.Template) mcp.Prompt {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(t.Description)}
+	for _, v := range t.Variables {
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(v.Description)}
+		if v.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(v.Name, argOpts...))
+	}
+	return mcp.NewPrompt(t.Name, opts...)
+}
+
+// parseVariables converts the raw variables tool argument into declared variables
+func parseVariables(raw any) []prompt.Variable {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	variables := make([]prompt.Variable, 0, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v := prompt.Variable{}
+		v.Name, _ = obj["name"].(string)
+		v.Description, _ = obj["description"].(string)
+		v.Required, _ = obj["required"].(bool)
+		variables = append(variables, v)
+	}
+	return variables
+}
+
+// formatPromptTemplate formats a prompt template with metadata as markdown
+func formatPromptTemplate(t *prompt.Template) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\nname: %s\ndescription: %s\nvariables:\n", t.Name, t.Description)
+	for _, v := range t.Variables {
+		required := ""
+		if v.Required {
+			required = " (required)"
+		}
+		fmt.Fprintf(&b, "  - %s%s: %s\n", v.Name, required, v.Description)
+	}
+	fmt.Fprintf(&b, "created_at: %s\nlast_modified: %s\n---\n\n",
+		t.CreatedAt.Format("2006-01-02 15:04:05"), t.LastModified.Format("2006-01-02 15:04:05"))
+	b.WriteString(t.Template)
+	return b.String()
+}
+
+// variableNames returns a comma-separated list of variable names
+func variableNames(variables []prompt.Variable) string {
+	names := make([]string, len(variables))
+	for i, v := range variables {
+		names[i] = v.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPromptTestHandler creates a handler backed by an in-memory prompt service and a live MCP server
+func newPromptTestHandler() (*Handler, *server.MCPServer) {
+	handler := NewHandler(new(MockRulesetService), WithPromptService(prompt.NewService(memstore.New())))
+	s := server.NewMCPServer("Test Server", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+	)
+	handler.server = s
+	handler.RegisterTools(s)
+	return handler, s
+}
+
+// listPromptNames sends prompts/list to the server and returns the prompt names
+func listPromptNames(t *testing.T, s *server.MCPServer) []string {
+	raw := s.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	resp, ok := raw.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	result, ok := resp.Result.(mcp.ListPromptsResult)
+	require.True(t, ok)
+
+	names := make([]string, 0, len(result.Prompts))
+	for _, p := range result.Prompts {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestHandlePromptTemplateTools(t *testing.T) {
+	handler, s := newPromptTestHandler()
+
+	upsertReq := mcp.CallToolRequest{}
+	upsertReq.Params.Arguments = map[string]interface{}{
+		"name":        "code_review",
+		"description": "Review code",
+		"template":    "Review this {{language}} code",
+		"variables": []interface{}{
+			map[string]interface{}{"name": "language", "description": "Programming language", "required": true},
+		},
+	}
+
+	result, err := handler.HandleUpsertPromptTemplate(context.TODO(), upsertReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully created prompt template 'code_review'")
+	assert.Equal(t, []string{"code_review"}, listPromptNames(t, s))
+
+	// Get tool shows metadata and body
+	getReq := mcp.CallToolRequest{}
+	getReq.Params.Arguments = map[string]interface{}{"name": "code_review"}
+	result, err = handler.HandleGetPromptTemplate(context.TODO(), getReq)
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "language (required): Programming language")
+	assert.Contains(t, text, "Review this {{language}} code")
+
+	// The MCP prompt renders with arguments
+	promptReq := mcp.GetPromptRequest{}
+	promptReq.Params.Name = "code_review"
+	promptReq.Params.Arguments = map[string]string{"language": "Go"}
+	rendered, err := handler.HandleGetPrompt(context.TODO(), promptReq)
+	require.NoError(t, err)
+	require.Len(t, rendered.Messages, 1)
+	assert.Equal(t, "Review this Go code", rendered.Messages[0].Content.(mcp.TextContent).Text)
+
+	promptReq.Params.Arguments = nil
+	_, err = handler.HandleGetPrompt(context.TODO(), promptReq)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required variables")
+
+	// List tool
+	result, err = handler.HandleListPromptTemplates(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Variables: language")
+
+	// Delete removes the MCP prompt too
+	result, err = handler.HandleDeletePromptTemplate(context.TODO(), getReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Empty(t, listPromptNames(t, s))
+}
+
+func TestHandleUpsertPromptTemplate_Errors(t *testing.T) {
+	handler, _ := newPromptTestHandler()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "no_body"}
+	result, err := handler.HandleUpsertPromptTemplate(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "missing required parameter 'template'")
+
+	req.Params.Arguments = map[string]interface{}{"name": "bad_vars", "template": "{{undeclared}}"}
+	result, err = handler.HandleUpsertPromptTemplate(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "undeclared variable")
+}
+
+func TestRegisterPrompts_LoadsStoredTemplates(t *testing.T) {
+	service := prompt.NewService(memstore.New())
+	_, err := service.Save(&prompt.Template{Name: "stored_prompt", Template: "Hello"})
+	require.NoError(t, err)
+
+	handler := NewHandler(new(MockRulesetService), WithPromptService(service))
+	s := server.NewMCPServer("Test Server", "1.0.0", server.WithPromptCapabilities(true))
+	handler.RegisterPrompts(s)
+
+	assert.Equal(t, []string{"stored_prompt"}, listPromptNames(t, s))
+}
@@ -0,0 +1,320 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jbrinkman/archivyr/internal/markdown"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// readOptions controls how a ruleset is rendered by get_ruleset and resource reads
+type readOptions struct {
+	// ExpandSnippets replaces {{snippet:name}} includes with snippet text
+	ExpandSnippets bool
+	// Raw returns the content without the metadata header
+	Raw bool
+	// Section limits the content to the part under this markdown heading
+	Section string
+	// Vars fills {{variable}} placeholders; placeholders without a value are left as is
+	Vars map[string]string
+	// Offset and Limit page through the content in units of Unit; a zero Limit reads to the end
+	Offset int
+	Limit  int
+	// Unit is chunkBytes or chunkHeadings
+	Unit string
+	// UsageHeader prepends a usage line identifying the exact ruleset version to the content
+	UsageHeader bool
+}
+
+// Units for paging through ruleset content
+const (
+	// chunkBytes pages by bytes, never splitting a UTF-8 character
+	chunkBytes = "bytes"
+	// chunkHeadings pages by sections, each starting at a heading
+	chunkHeadings = "headings"
+)
+
+// maxPageUnits bounds offset and limit, far above any ruleset size
+const maxPageUnits = 1 << 30
+
+// paged reports whether the options select a chunk rather than the whole content
+func (o readOptions) paged() bool {
+	return o.Offset > 0 || o.Limit > 0
+}
+
+// readPage describes the chunk of content returned by a paged read
+type readPage struct {
+	Unit string
+	// Start and End delimit the returned chunk in units, End exclusive
+	Start, End int
+	// Total is the size of the whole content in units
+	Total int
+}
+
+// contentRange renders the page in HTTP Content-Range style, e.g. "bytes 0-4095/20000"
+func (p *readPage) contentRange() string {
+	if p.Start >= p.End {
+		return fmt.Sprintf("%s */%d", p.Unit, p.Total)
+	}
+	return fmt.Sprintf("%s %d-%d/%d", p.Unit, p.Start, p.End-1, p.Total)
+}
+
+// more reports whether content follows the page
+func (p *readPage) more() bool {
+	return p.End < p.Total
+}
+
+// header renders the page as metadata header lines
+func (p *readPage) header() string {
+	header := fmt.Sprintf("range: %s\n", p.contentRange())
+	if p.more() {
+		header += fmt.Sprintf("next_offset: %d\n", p.End)
+	}
+	return header
+}
+
+// meta renders the page as _meta fields, for responses without a metadata header
+func (p *readPage) meta() map[string]any {
+	meta := map[string]any{"range": p.contentRange()}
+	if p.more() {
+		meta["next_offset"] = p.End
+	}
+	return meta
+}
+
+// varPlaceholderRegex matches {{ variable }} placeholders
+var varPlaceholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// readOptionParams declares the read options shared by get_ruleset and the ruleset resource
+func readOptionParams() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithBoolean("expand_snippets", mcp.Description("Replace {{snippet:name}} includes with snippet text. Defaults to true; set false to retrieve the raw content for editing.")),
+		mcp.WithBoolean("raw", mcp.Description("Return only the content, without the metadata header")),
+		mcp.WithString("section", mcp.Description("Only return the part of a markdown ruleset under this heading (matched by text or slug, e.g. 'Testing' or 'unit-tests')"), mcp.MaxLength(maxTextLength)),
+		mcp.WithObject("vars",
+			mcp.Description("Values for {{variable}} placeholders in the content, e.g. {\"project\": \"acme\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithNumber("offset", mcp.Description("Start of the chunk to return, in units; the header reports next_offset while more content follows"), integer(), mcp.Min(0), mcp.Max(maxPageUnits)),
+		mcp.WithNumber("limit", mcp.Description("Maximum size of the chunk to return, in units. Omit to read to the end."), integer(), mcp.Min(1), mcp.Max(maxPageUnits)),
+		mcp.WithString("unit", mcp.Description("Unit of offset and limit: 'bytes' (default) or 'headings' to page by sections"), mcp.Enum(chunkBytes, chunkHeadings)),
+		mcp.WithBoolean("usage_header", mcp.Description("Prepend a machine-readable comment with the canonical URI, version, checksum and retrieval time, so artifacts generated from the content can be traced to this exact ruleset version. Not supported for JSON rulesets.")),
+	}
+}
+
+// readOptionsFromRequest reads the read options of a get_ruleset call
+func readOptionsFromRequest(req mcp.CallToolRequest) (readOptions, error) {
+	opts := readOptions{
+		ExpandSnippets: req.GetBool("expand_snippets", true),
+		Raw:            req.GetBool("raw", false),
+		Section:        req.GetString("section", ""),
+		Offset:         req.GetInt("offset", 0),
+		Limit:          req.GetInt("limit", 0),
+		Unit:           req.GetString("unit", chunkBytes),
+		UsageHeader:    req.GetBool("usage_header", false),
+	}
+	if raw, ok := req.GetArguments()["vars"]; ok && raw != nil {
+		vars, ok := raw.(map[string]any)
+		if !ok {
+			return readOptions{}, fmt.Errorf("vars must be an object of strings")
+		}
+		opts.Vars = make(map[string]string, len(vars))
+		for name, value := range vars {
+			s, ok := value.(string)
+			if !ok {
+				return readOptions{}, fmt.Errorf("vars.%s must be a string", name)
+			}
+			opts.Vars[name] = s
+		}
+	}
+	return opts, opts.validate()
+}
+
+// validate checks the paging options
+func (o readOptions) validate() error {
+	if o.Offset < 0 {
+		return fmt.Errorf("offset must be a non-negative integer")
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("limit must be a positive integer")
+	}
+	if o.Offset > maxPageUnits || o.Limit > maxPageUnits {
+		return fmt.Errorf("offset and limit must be at most %d", maxPageUnits)
+	}
+	if o.Unit != chunkBytes && o.Unit != chunkHeadings {
+		return fmt.Errorf("unit must be one of: %s, %s", chunkBytes, chunkHeadings)
+	}
+	return nil
+}
+
+// readOptionsFromQuery reads the read options from a resource URI query such as
+// "section=testing&raw=true&vars=project%3Dacme". Several variables are passed by repeating vars.
+func readOptionsFromQuery(query url.Values) (readOptions, error) {
+	opts := readOptions{ExpandSnippets: true, Section: query.Get("section"), Unit: chunkBytes}
+	if unit := query.Get("unit"); unit != "" {
+		opts.Unit = unit
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{
+		{"offset", &opts.Offset},
+		{"limit", &opts.Limit},
+	} {
+		if value := query.Get(param.name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return readOptions{}, fmt.Errorf("invalid %s '%s': must be an integer", param.name, value)
+			}
+			*param.target = n
+		}
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *bool
+	}{
+		{"expand_snippets", &opts.ExpandSnippets},
+		{"raw", &opts.Raw},
+		{"usage_header", &opts.UsageHeader},
+	} {
+		if value := query.Get(param.name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return readOptions{}, fmt.Errorf("invalid %s '%s': must be true or false", param.name, value)
+			}
+			*param.target = b
+		}
+	}
+
+	for _, pair := range query["vars"] {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return readOptions{}, fmt.Errorf("invalid vars '%s': must be name=value", pair)
+		}
+		if opts.Vars == nil {
+			opts.Vars = make(map[string]string)
+		}
+		opts.Vars[name] = value
+	}
+	return opts, opts.validate()
+}
+
+// applyReadOptions returns the content of rs transformed by opts, and the page
+// returned when opts select a chunk. The metadata header is left to the caller,
+// which decides based on opts.Raw.
+func (h *Handler) applyReadOptions(rs *ruleset.Ruleset, opts readOptions) (string, *readPage, error) {
+	if opts.UsageHeader && ruleset.IsJSONContentType(rs.ContentType) {
+		return "", nil, fmt.Errorf("usage_header is not supported for JSON rulesets, which cannot hold a comment")
+	}
+	content, page, err := h.transformContent(rs, opts)
+	if err != nil || !opts.UsageHeader {
+		return content, page, err
+	}
+	return usageHeader(rs, time.Now()) + content, page, nil
+}
+
+// usageHeaderMarker prefixes the payload of a usage header
+const usageHeaderMarker = "archivyr-usage"
+
+// usage is the payload of a usage header
+type usage struct {
+	URI         string `json:"uri"`
+	Version     int64  `json:"version"`
+	Checksum    string `json:"checksum"`
+	RetrievedAt string `json:"retrieved_at"`
+}
+
+// usageHeader renders the usage header of rs retrieved at now as a one-line
+// comment holding JSON, e.g.
+// <!-- archivyr-usage: {"uri":"ruleset://go_rules","version":3,...} -->
+func usageHeader(rs *ruleset.Ruleset, now time.Time) string {
+	payload, _ := json.Marshal(usage{
+		URI:         "ruleset://" + rs.Name,
+		Version:     rs.Version,
+		Checksum:    rs.Checksum(),
+		RetrievedAt: now.UTC().Format(time.RFC3339),
+	})
+	return fmt.Sprintf("<!-- %s: %s -->\n", usageHeaderMarker, payload)
+}
+
+// transformContent applies the content options of opts to rs; see applyReadOptions
+func (h *Handler) transformContent(rs *ruleset.Ruleset, opts readOptions) (string, *readPage, error) {
+	content := rs.Markdown
+	if opts.ExpandSnippets {
+		content = h.expandSnippets(rs)
+	}
+
+	if opts.Section != "" {
+		if mimeType(rs) != ruleset.ContentTypeMarkdown {
+			return "", nil, fmt.Errorf("section is only supported for %s rulesets", ruleset.ContentTypeMarkdown)
+		}
+		section, ok := markdown.Section(content, opts.Section)
+		if !ok {
+			return "", nil, fmt.Errorf("section '%s' not found in ruleset '%s'", opts.Section, rs.Name)
+		}
+		content = section
+	}
+
+	if len(opts.Vars) > 0 && !ruleset.IsJSONContentType(rs.ContentType) {
+		content = varPlaceholderRegex.ReplaceAllStringFunc(content, func(placeholder string) string {
+			if value, ok := opts.Vars[varPlaceholderRegex.FindStringSubmatch(placeholder)[1]]; ok {
+				return value
+			}
+			return placeholder
+		})
+	}
+
+	if !opts.paged() {
+		return content, nil, nil
+	}
+	if opts.Unit == chunkHeadings && mimeType(rs) != ruleset.ContentTypeMarkdown {
+		return "", nil, fmt.Errorf("paging by headings is only supported for %s rulesets", ruleset.ContentTypeMarkdown)
+	}
+	content, page := chunk(content, opts)
+	return content, page, nil
+}
+
+// chunk cuts the page selected by opts out of content
+func chunk(content string, opts readOptions) (string, *readPage) {
+	if opts.Unit == chunkHeadings {
+		parts := markdown.Split(content)
+		start := min(opts.Offset, len(parts))
+		end := len(parts)
+		if opts.Limit > 0 && opts.Limit < end-start {
+			end = start + opts.Limit
+		}
+		return strings.Join(parts[start:end], ""), &readPage{Unit: chunkHeadings, Start: start, End: end, Total: len(parts)}
+	}
+
+	// Move both bounds back to the start of a UTF-8 character so none is split
+	start := runeStart(content, min(opts.Offset, len(content)))
+	end := len(content)
+	// Compare against the remaining length so a huge limit cannot overflow
+	if opts.Limit > 0 && opts.Limit < end-start {
+		end = runeStart(content, start+opts.Limit)
+		if end == start {
+			// A limit smaller than the character still returns it whole
+			_, size := utf8.DecodeRuneInString(content[start:])
+			end = start + size
+		}
+	}
+	return content[start:end], &readPage{Unit: chunkBytes, Start: start, End: end, Total: len(content)}
+}
+
+// runeStart moves i back to the start of the UTF-8 character containing it
+func runeStart(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunk(t *testing.T) {
+	doc := "Intro\n# One\nalpha\n## Two\nbeta\n# Three\ngamma\n"
+
+	tests := []struct {
+		name    string
+		content string
+		opts    readOptions
+		want    string
+		page    readPage
+		header  string
+	}{
+		{
+			name:    "first bytes",
+			content: "abcdefghij",
+			opts:    readOptions{Limit: 4, Unit: chunkBytes},
+			want:    "abcd",
+			page:    readPage{Unit: chunkBytes, Start: 0, End: 4, Total: 10},
+			header:  "range: bytes 0-3/10\nnext_offset: 4\n",
+		},
+		{
+			name:    "last bytes",
+			content: "abcdefghij",
+			opts:    readOptions{Offset: 8, Limit: 4, Unit: chunkBytes},
+			want:    "ij",
+			page:    readPage{Unit: chunkBytes, Start: 8, End: 10, Total: 10},
+			header:  "range: bytes 8-9/10\n",
+		},
+		{
+			name:    "past the end",
+			content: "abc",
+			opts:    readOptions{Offset: 10, Unit: chunkBytes},
+			want:    "",
+			page:    readPage{Unit: chunkBytes, Start: 3, End: 3, Total: 3},
+			header:  "range: bytes */3\n",
+		},
+		{
+			name:    "never splits a character",
+			content: "añb",
+			opts:    readOptions{Limit: 2, Unit: chunkBytes},
+			want:    "a",
+			page:    readPage{Unit: chunkBytes, Start: 0, End: 1, Total: 4},
+			header:  "range: bytes 0-0/4\nnext_offset: 1\n",
+		},
+		{
+			name:    "limit smaller than a character",
+			content: "ñb",
+			opts:    readOptions{Limit: 1, Unit: chunkBytes},
+			want:    "ñ",
+			page:    readPage{Unit: chunkBytes, Start: 0, End: 2, Total: 3},
+			header:  "range: bytes 0-1/3\nnext_offset: 2\n",
+		},
+		{
+			name:    "headings",
+			content: doc,
+			opts:    readOptions{Offset: 1, Limit: 2, Unit: chunkHeadings},
+			want:    "# One\nalpha\n## Two\nbeta\n",
+			page:    readPage{Unit: chunkHeadings, Start: 1, End: 3, Total: 4},
+			header:  "range: headings 1-2/4\nnext_offset: 3\n",
+		},
+		{
+			name:    "huge byte limit does not overflow",
+			content: "abcdefghij",
+			opts:    readOptions{Offset: 2, Limit: math.MaxInt - 1, Unit: chunkBytes},
+			want:    "cdefghij",
+			page:    readPage{Unit: chunkBytes, Start: 2, End: 10, Total: 10},
+			header:  "range: bytes 2-9/10\n",
+		},
+		{
+			name:    "huge heading limit does not overflow",
+			content: doc,
+			opts:    readOptions{Offset: 3, Limit: math.MaxInt, Unit: chunkHeadings},
+			want:    "# Three\ngamma\n",
+			page:    readPage{Unit: chunkHeadings, Start: 3, End: 4, Total: 4},
+			header:  "range: headings 3-3/4\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, page := chunk(tt.content, tt.opts)
+			assert.Equal(t, tt.want, content)
+			assert.Equal(t, tt.page, *page)
+			assert.Equal(t, tt.header, page.header())
+		})
+	}
+}
+
+func TestHandleGetRuleset_Paging(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	rs := &ruleset.Ruleset{
+		Name:     "big_rules",
+		Version:  2,
+		Markdown: "# A\none\n# B\ntwo\n# C\nthree\n",
+	}
+	mockService.On("Get", "big_rules").Return(rs, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{
+		"name":  "big_rules",
+		"unit":  "headings",
+		"limit": 1.0,
+	}
+	result, err := handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "checksum: "+rs.Checksum()+"\n")
+	assert.Contains(t, text, "range: headings 0-0/3\nnext_offset: 1\n---\n\n# A\none\n")
+
+	// Raw chunks carry the page in _meta
+	req.Params.Arguments = map[string]interface{}{
+		"name":   "big_rules",
+		"raw":    true,
+		"offset": 12.0,
+	}
+	result, err = handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "two\n# C\nthree\n", result.Content[0].(mcp.TextContent).Text)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, "bytes 12-25/26", result.Meta.AdditionalFields["range"])
+	assert.NotContains(t, result.Meta.AdditionalFields, "next_offset")
+
+	// Resource reads accept the same parameters
+	resourceReq := mcp.ReadResourceRequest{}
+	resourceReq.Params.URI = "ruleset://big_rules?offset=0&limit=4&raw=true"
+	contents, err := handler.HandleResourceRead(context.TODO(), resourceReq)
+	require.NoError(t, err)
+	resource := contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, "# A\n", resource.Text)
+	assert.Equal(t, map[string]any{"range": "bytes 0-3/26", "next_offset": 4}, resource.Meta)
+
+	resourceReq.Params.URI = "ruleset://big_rules?unit=lines&limit=4"
+	_, err = handler.HandleResourceRead(context.TODO(), resourceReq)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unit must be one of: bytes, headings")
+
+	resourceReq.Params.URI = "ruleset://big_rules?offset=2000&limit=9223372036854774784"
+	_, err = handler.HandleResourceRead(context.TODO(), resourceReq)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offset and limit must be at most 1073741824")
+}
+
+func TestGetRuleset_RejectsHugeLimit(t *testing.T) {
+	mockService := new(MockRulesetService)
+	ctx := context.Background()
+	c, err := NewHandler(mockService).NewInProcessClient(ctx)
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_ruleset"
+	req.Params.Arguments = map[string]any{"name": "big_rules", "offset": 2000.0, "limit": 9223372036854774784.0}
+	result, err := c.CallTool(ctx, req)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Equal(t, "invalid arguments: limit: must be at most 1.073741824e+09", result.Content[0].(mcp.TextContent).Text)
+
+	req.Params.Arguments = map[string]any{"name": "big_rules", "limit": 1.5}
+	result, err = c.CallTool(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "invalid arguments: limit: must be an integer", result.Content[0].(mcp.TextContent).Text)
+	mockService.AssertNotCalled(t, "Get", "big_rules")
+}
+
+func TestUsageHeader(t *testing.T) {
+	rs := &ruleset.Ruleset{Name: "go_rules", Version: 3, Markdown: "# Go"}
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	header := usageHeader(rs, now)
+	assert.Equal(t, `<!-- archivyr-usage: {"uri":"ruleset://go_rules","version":3,"checksum":"`+rs.Checksum()+`","retrieved_at":"2024-05-01T10:30:00Z"} -->`+"\n", header)
+}
+
+func TestHandleGetRuleset_UsageHeader(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService)
+
+	rs := &ruleset.Ruleset{Name: "go_rules", Version: 3, Markdown: "# Go\nUse gofmt.\n"}
+	config := &ruleset.Ruleset{Name: "lint_config", Version: 1, ContentType: "application/json", Markdown: `{"strict": true}`}
+	mockService.On("Get", "go_rules").Return(rs, nil)
+	mockService.On("Get", "lint_config").Return(config, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "go_rules", "raw": true, "usage_header": true}
+	result, err := handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Regexp(t, `^<!-- archivyr-usage: \{"uri":"ruleset://go_rules","version":3,"checksum":"`+rs.Checksum()+`","retrieved_at":"[^"]+Z"\} -->\n# Go\nUse gofmt\.\n$`, text)
+
+	// Without the option the content is unchanged
+	req.Params.Arguments = map[string]interface{}{"name": "go_rules", "raw": true}
+	result, err = handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, rs.Markdown, result.Content[0].(mcp.TextContent).Text)
+
+	// Resource reads put the usage header after the metadata header
+	resourceReq := mcp.ReadResourceRequest{}
+	resourceReq.Params.URI = "ruleset://go_rules?usage_header=true"
+	contents, err := handler.HandleResourceRead(context.TODO(), resourceReq)
+	require.NoError(t, err)
+	assert.Contains(t, contents[0].(mcp.TextResourceContents).Text, "---\n\n<!-- archivyr-usage: ")
+
+	// JSON cannot hold a comment
+	req.Params.Arguments = map[string]interface{}{"name": "lint_config", "usage_header": true}
+	result, err = handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "usage_header is not supported for JSON rulesets")
+}
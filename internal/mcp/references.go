@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/rs/zerolog/log"
+)
+
+// mandatoryReference names the MANDATORY_RULESETS configuration among the
+// references to a ruleset; it cannot be unlinked at runtime
+const mandatoryReference = "MANDATORY_RULESETS"
+
+// WithReferencePolicy checks for links to a ruleset before delete_ruleset
+// removes it and applies policy to them. Without it references are not checked.
+func WithReferencePolicy(policy ruleset.ReferencePolicy) Option {
+	return func(h *Handler) {
+		h.referencePolicy = policy
+	}
+}
+
+// references returns the names of the rulesets linking to the ruleset named
+// name, followed by mandatoryReference when it is a mandatory ruleset
+func (h *Handler) references(name string) ([]string, error) {
+	rulesets, err := h.rulesetService.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check references to ruleset '%s': %w", name, err)
+	}
+	names := ruleset.References(rulesets, name)
+	if slices.Contains(h.mandatory, name) {
+		names = append(names, mandatoryReference)
+	}
+	return names, nil
+}
+
+// unlink removes links to the deleted ruleset named name from the rulesets in
+// referencing and returns a note describing the outcome for the tool result
+func (h *Handler) unlink(name string, referencing []string) string {
+	var unlinked, failed []string
+	for _, ref := range referencing {
+		if ref == mandatoryReference {
+			continue
+		}
+		rs, err := h.rulesetService.Get(ref)
+		if err == nil {
+			content := ruleset.Unlink(rs.Markdown, name)
+			// The deletion was authorised, so removing its links may override stewardship and imports
+			err = h.rulesetService.Update(ref, &ruleset.Update{Markdown: &content, Force: true})
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("ruleset", ref).Str("deleted", name).Msg("Failed to unlink deleted ruleset")
+			failed = append(failed, ref)
+			continue
+		}
+		unlinked = append(unlinked, ref)
+	}
+
+	var notes []string
+	if len(unlinked) > 0 {
+		notes = append(notes, fmt.Sprintf("Removed links to it from: %s", strings.Join(unlinked, ", ")))
+	}
+	if len(failed) > 0 {
+		notes = append(notes, fmt.Sprintf("Warning: failed to remove links to it from: %s", strings.Join(failed, ", ")))
+	}
+	if slices.Contains(referencing, mandatoryReference) {
+		notes = append(notes, fmt.Sprintf("Warning: it is still listed in %s", mandatoryReference))
+	}
+	return strings.Join(notes, "\n")
+}
+
+// changesetReferences applies the reference policy to the deletions among
+// changes. Links are judged by the rulesets as they will be once every change
+// is applied, so a changeset may remove the links along with the ruleset. It
+// returns the changes to apply, with the unlinking edits appended under the
+// cascade policy, and notes describing the outcome for the tool result.
+func (h *Handler) changesetReferences(changes []ruleset.Change) ([]ruleset.Change, []string, error) {
+	rulesets, err := h.rulesetService.List()
+	if err != nil {
+		err = fmt.Errorf("failed to check references to the rulesets deleted by the changeset: %w", err)
+		if h.referencePolicy == ruleset.ReferencePolicyBlock {
+			return nil, nil, err
+		}
+		log.Warn().Err(err).Msg("Committing changeset without checking references")
+		return changes, nil, nil
+	}
+
+	// Replay the changes on copies of the stored rulesets
+	after := make(map[string]*ruleset.Ruleset, len(rulesets))
+	for _, rs := range rulesets {
+		stored := *rs
+		after[rs.Name] = &stored
+	}
+	var deleted []string
+	for _, c := range changes {
+		switch c.Kind {
+		case ruleset.ChangeDelete:
+			delete(after, c.Name)
+			if !slices.Contains(deleted, c.Name) {
+				deleted = append(deleted, c.Name)
+			}
+		case ruleset.ChangeUpsert:
+			deleted = slices.DeleteFunc(deleted, func(name string) bool { return name == c.Name })
+			next := *c.Ruleset
+			if current, ok := after[c.Name]; ok {
+				next = *current
+				if c.Updates.Markdown != nil {
+					next.Markdown = *c.Updates.Markdown
+				}
+			}
+			after[c.Name] = &next
+		}
+	}
+	remaining := make([]*ruleset.Ruleset, 0, len(after))
+	for _, rs := range after {
+		remaining = append(remaining, rs)
+	}
+
+	var notes []string
+	var unlinked []string
+	for _, name := range deleted {
+		referencing := ruleset.References(remaining, name)
+		if slices.Contains(h.mandatory, name) {
+			referencing = append(referencing, mandatoryReference)
+		}
+		if len(referencing) == 0 {
+			continue
+		}
+
+		switch h.referencePolicy {
+		case ruleset.ReferencePolicyBlock:
+			return nil, nil, fmt.Errorf("ruleset '%s' is still referenced by: %s; remove the references before deleting it",
+				name, strings.Join(referencing, ", "))
+		case ruleset.ReferencePolicyCascade:
+			var refs []string
+			for _, ref := range referencing {
+				if ref == mandatoryReference {
+					notes = append(notes, fmt.Sprintf("Warning: ruleset '%s' is still listed in %s", name, mandatoryReference))
+					continue
+				}
+				after[ref].Markdown = ruleset.Unlink(after[ref].Markdown, name)
+				if !slices.Contains(unlinked, ref) {
+					unlinked = append(unlinked, ref)
+				}
+				refs = append(refs, ref)
+			}
+			if len(refs) > 0 {
+				notes = append(notes, fmt.Sprintf("Removed links to ruleset '%s' from: %s", name, strings.Join(refs, ", ")))
+			}
+		default:
+			notes = append(notes, fmt.Sprintf("Warning: ruleset '%s' is still referenced by: %s", name, strings.Join(referencing, ", ")))
+		}
+	}
+
+	// The deletions were authorised, so removing their links may override stewardship and imports
+	for _, ref := range unlinked {
+		content := after[ref].Markdown
+		changes = append(changes, ruleset.Change{
+			Kind:    ruleset.ChangeUpsert,
+			Name:    ref,
+			Ruleset: after[ref],
+			Updates: &ruleset.Update{Markdown: &content, Force: true},
+		})
+	}
+	return changes, notes, nil
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleDeleteRuleset_ReferencePolicy(t *testing.T) {
+	corpus := func() []*ruleset.Ruleset {
+		return []*ruleset.Ruleset{
+			{Name: "go_rules", Markdown: "# Go"},
+			{Name: "api_rules", Markdown: "Follow [the Go rules](ruleset://go_rules)."},
+			{Name: "rust_rules", Markdown: "# Rust"},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		policy    ruleset.ReferencePolicy
+		mandatory []string
+		deletes   bool
+		isError   bool
+		expected  string
+	}{
+		{
+			name:     "block",
+			policy:   ruleset.ReferencePolicyBlock,
+			isError:  true,
+			expected: "ruleset 'go_rules' is still referenced by: api_rules; remove the references before deleting it",
+		},
+		{
+			name:      "block mandatory",
+			policy:    ruleset.ReferencePolicyBlock,
+			mandatory: []string{"go_rules"},
+			isError:   true,
+			expected:  "ruleset 'go_rules' is still referenced by: api_rules, MANDATORY_RULESETS",
+		},
+		{
+			name:     "warn",
+			policy:   ruleset.ReferencePolicyWarn,
+			deletes:  true,
+			expected: "Successfully deleted ruleset 'go_rules'\nWarning: it is still referenced by: api_rules",
+		},
+		{
+			name:      "cascade",
+			policy:    ruleset.ReferencePolicyCascade,
+			mandatory: []string{"go_rules"},
+			deletes:   true,
+			expected:  "Successfully deleted ruleset 'go_rules'\nRemoved links to it from: api_rules\nWarning: it is still listed in MANDATORY_RULESETS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRulesetService)
+			handler := NewHandler(mockService, WithReferencePolicy(tt.policy), WithMandatoryRulesets(tt.mandatory))
+
+			rulesets := corpus()
+			mockService.On("List").Return(rulesets, nil)
+			if tt.deletes {
+				mockService.On("Delete", "go_rules").Return(nil)
+			}
+			if tt.policy == ruleset.ReferencePolicyCascade {
+				mockService.On("Get", "api_rules").Return(rulesets[1], nil)
+				mockService.On("Update", "api_rules", mock.MatchedBy(func(u *ruleset.Update) bool {
+					return u.Markdown != nil && *u.Markdown == "Follow the Go rules." && u.Force
+				})).Return(nil)
+			}
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]interface{}{"name": "go_rules"}
+			result, err := handler.HandleDeleteRuleset(context.TODO(), req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.isError, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.expected)
+			mockService.AssertExpectations(t)
+			if !tt.deletes {
+				mockService.AssertNotCalled(t, "Delete", "go_rules")
+			}
+		})
+	}
+}
+
+func TestHandleDeleteRuleset_ReferenceCheckFails(t *testing.T) {
+	mockService := new(MockRulesetService)
+	mockService.On("List").Return(nil, errors.New("connection refused"))
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "go_rules"}
+
+	// Blocking refuses deletions it cannot check
+	handler := NewHandler(mockService, WithReferencePolicy(ruleset.ReferencePolicyBlock))
+	result, err := handler.HandleDeleteRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to check references to ruleset 'go_rules'")
+
+	// Other policies delete without checking
+	mockService.On("Delete", "go_rules").Return(nil)
+	handler = NewHandler(mockService, WithReferencePolicy(ruleset.ReferencePolicyWarn))
+	result, err = handler.HandleDeleteRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Successfully deleted ruleset 'go_rules'", result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jbrinkman/archivyr/internal/diff"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/source"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithSourceFetcher enables the refresh_ruleset tool for rulesets with a recorded source URL
+func WithSourceFetcher(fetcher source.Fetcher) Option {
+	return func(h *Handler) {
+		h.sourceFetcher = fetcher
+	}
+}
+
+// registerRefreshTools registers the refresh_ruleset tool
+func (h *Handler) registerRefreshTools(s *server.MCPServer) {
+	refreshTool := mcp.NewTool("refresh_ruleset",
+		mcp.WithDescription("Refetch an imported ruleset from its source URL and show the diff against the stored content with its checksum. Call again with confirm=true and that expected_checksum to apply the upstream content."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of a ruleset with a recorded source_url"), mcp.MaxLength(maxNameLength)),
+		mcp.WithBoolean("confirm", mcp.Description("Apply the upstream content instead of only previewing the diff")),
+		mcp.WithString("expected_checksum", mcp.Description("Checksum returned with the previewed diff; required with confirm, which is refused when the stored or upstream content changed since the preview"), mcp.MaxLength(maxTextLength)),
+	)
+	s.AddTool(refreshTool, h.handleRefreshRuleset)
+}
+
+// HandleRefreshRuleset handles the refresh_ruleset tool invocation (exported for testing)
+func (h *Handler) HandleRefreshRuleset(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleRefreshRuleset(ctx, req)
+}
+
+// handleRefreshRuleset handles the refresh_ruleset tool invocation
+func (h *Handler) handleRefreshRuleset(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	rs, err := h.rulesetService.Get(name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to retrieve ruleset: %v", err)), nil
+	}
+
+	if rs.SourceURL == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("ruleset '%s' has no recorded source_url", name)), nil
+	}
+
+	upstream, err := h.sourceFetcher.Fetch(rs.SourceURL)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to refresh ruleset: %v", err)), nil
+	}
+
+	changes := diff.Unified("ruleset://"+name, rs.SourceURL, rs.Markdown, upstream)
+	if changes == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("Ruleset '%s' is up to date with %s", name, rs.SourceURL)), nil
+	}
+
+	checksum := refreshChecksum(rs, upstream)
+	if !req.GetBool("confirm", false) {
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"Upstream changes for ruleset '%s' (checksum %s):\n\n```diff\n%s```\n\nCall refresh_ruleset with confirm=true and expected_checksum=%s to apply them.",
+			name, checksum, changes, checksum)), nil
+	}
+
+	// Only apply the diff that was reviewed, not whatever the source serves now
+	expected := req.GetString("expected_checksum", "")
+	if expected == "" {
+		return mcp.NewToolResultError("confirm requires the expected_checksum returned with the previewed diff"), nil
+	}
+	if expected != checksum {
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"ruleset '%s' or its upstream content changed since the preview (checksum %s, expected %s); call refresh_ruleset without confirm to review the new diff",
+			name, checksum, expected)), nil
+	}
+
+	// Re-importing from the same source keeps provenance and refreshes imported_at
+	sourceURL := rs.SourceURL
+	err = h.rulesetService.Update(name, &ruleset.Update{Markdown: &upstream, SourceURL: &sourceURL, Force: true})
+	if result := queuedResult(err, fmt.Sprintf("refresh of ruleset '%s'", name)); result != nil {
+		return result, nil
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to refresh ruleset: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(
+		"Successfully refreshed ruleset '%s' from %s:\n\n```diff\n%s```", name, sourceURL, changes)), nil
+}
+
+// refreshChecksum identifies a previewed refresh: it covers both the stored
+// content and the upstream content replacing it, so a confirmation applies
+// exactly the diff that was reviewed
+func refreshChecksum(rs *ruleset.Ruleset, upstream string) string {
+	refreshed := *rs
+	refreshed.Markdown = upstream
+
+	h := sha256.New()
+	h.Write([]byte(rs.Checksum()))
+	h.Write([]byte{0})
+	h.Write([]byte(refreshed.Checksum()))
+	return hex.EncodeToString(h.Sum(nil))
+}
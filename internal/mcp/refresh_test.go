@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFetcher serves fixed upstream content keyed by URL
+type stubFetcher map[string]string
+
+func (f stubFetcher) Fetch(sourceURL string) (string, error) {
+	content, ok := f[sourceURL]
+	if !ok {
+		return "", fmt.Errorf("failed to fetch source: unexpected status 404 Not Found")
+	}
+	return content, nil
+}
+
+func TestHandleRefreshRuleset(t *testing.T) {
+	const sourceURL = "https://example.com/rules.md"
+	stored := &ruleset.Ruleset{Name: "community_rules", Markdown: "# Rules\nold line\n", SourceURL: sourceURL}
+
+	t.Run("previews diff", func(t *testing.T) {
+		mockService := new(MockRulesetService)
+		handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{sourceURL: "# Rules\nnew line\n"}))
+		mockService.On("Get", "community_rules").Return(stored, nil)
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"name": "community_rules"}
+		result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "-old line\n+new line\n")
+		assert.Contains(t, text, "confirm=true and expected_checksum="+refreshChecksum(stored, "# Rules\nnew line\n"))
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("applies on confirm", func(t *testing.T) {
+		mockService := new(MockRulesetService)
+		handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{sourceURL: "# Rules\nnew line\n"}))
+		mockService.On("Get", "community_rules").Return(stored, nil)
+		mockService.On("Update", "community_rules", mock.MatchedBy(func(u *ruleset.Update) bool {
+			return *u.Markdown == "# Rules\nnew line\n" && *u.SourceURL == sourceURL && u.Force
+		})).Return(nil)
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{
+			"name":              "community_rules",
+			"confirm":           true,
+			"expected_checksum": refreshChecksum(stored, "# Rules\nnew line\n"),
+		}
+		result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully refreshed ruleset 'community_rules'")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("confirm requires checksum", func(t *testing.T) {
+		mockService := new(MockRulesetService)
+		handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{sourceURL: "# Rules\nnew line\n"}))
+		mockService.On("Get", "community_rules").Return(stored, nil)
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"name": "community_rules", "confirm": true}
+		result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "requires the expected_checksum")
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses changed content", func(t *testing.T) {
+		edited := *stored
+		edited.Markdown = "# Rules\nlocal edit\n"
+
+		testCases := []struct {
+			name     string
+			current  *ruleset.Ruleset
+			upstream string
+		}{
+			{"upstream changed", stored, "# Rules\nnewer line\n"},
+			{"stored changed", &edited, "# Rules\nnew line\n"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				mockService := new(MockRulesetService)
+				handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{sourceURL: tc.upstream}))
+				mockService.On("Get", "community_rules").Return(tc.current, nil)
+
+				req := mcp.CallToolRequest{}
+				req.Params.Arguments = map[string]interface{}{
+					"name":              "community_rules",
+					"confirm":           true,
+					"expected_checksum": refreshChecksum(stored, "# Rules\nnew line\n"),
+				}
+				result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "changed since the preview")
+				mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		mockService := new(MockRulesetService)
+		handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{sourceURL: stored.Markdown}))
+		mockService.On("Get", "community_rules").Return(stored, nil)
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"name": "community_rules", "confirm": true}
+		result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+		require.NoError(t, err)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "is up to date")
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		mockService := new(MockRulesetService)
+		handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{}))
+		mockService.On("Get", "local_rules").Return(&ruleset.Ruleset{Name: "local_rules"}, nil)
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"name": "local_rules"}
+		result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "has no recorded source_url")
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		mockService := new(MockRulesetService)
+		handler := NewHandler(mockService, WithSourceFetcher(stubFetcher{}))
+		mockService.On("Get", "community_rules").Return(stored, nil)
+
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"name": "community_rules"}
+		result, err := handler.HandleRefreshRuleset(context.TODO(), req)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "404")
+	})
+}
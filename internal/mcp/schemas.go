@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// schemaURIPrefix prefixes the URIs of the published JSON Schemas, e.g. archivyr://schema/ruleset
+const schemaURIPrefix = "archivyr://schema/"
+
+// registerSchemaResources exposes the Ruleset and Update JSON Schemas as resources
+func (h *Handler) registerSchemaResources(s *server.MCPServer) {
+	for _, name := range ruleset.Schemas {
+		resource := mcp.NewResource(
+			schemaURIPrefix+name,
+			fmt.Sprintf("%s JSON Schema", name),
+			mcp.WithResourceDescription(fmt.Sprintf("JSON Schema (version %d) for validating %s payloads before submission", ruleset.SchemaVersion, name)),
+			mcp.WithMIMEType(ruleset.ContentTypeJSONSchema),
+		)
+		s.AddResource(resource, h.handleSchemaRead)
+	}
+}
+
+// HandleSchemaRead handles resource reads of the JSON Schemas (exported for testing)
+func (h *Handler) HandleSchemaRead(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return h.handleSchemaRead(ctx, req)
+}
+
+// handleSchemaRead handles resource reads of the JSON Schemas
+func (h *Handler) handleSchemaRead(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	name, ok := strings.CutPrefix(req.Params.URI, schemaURIPrefix)
+	if !ok {
+		return nil, fmt.Errorf("invalid URI format: %s", req.Params.URI)
+	}
+
+	schema, err := ruleset.Schema(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: ruleset.ContentTypeJSONSchema,
+			Text:     string(schema),
+		},
+	}, nil
+}
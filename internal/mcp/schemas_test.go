@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSchemaRead(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService))
+
+	for _, name := range ruleset.Schemas {
+		t.Run(name, func(t *testing.T) {
+			req := mcp.ReadResourceRequest{}
+			req.Params.URI = "archivyr://schema/" + name
+
+			contents, err := handler.HandleSchemaRead(context.TODO(), req)
+			require.NoError(t, err)
+			require.Len(t, contents, 1)
+
+			text := contents[0].(mcp.TextResourceContents)
+			assert.Equal(t, ruleset.ContentTypeJSONSchema, text.MIMEType)
+			assert.True(t, json.Valid([]byte(text.Text)))
+		})
+	}
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "archivyr://schema/pack"
+	_, err := handler.HandleSchemaRead(context.TODO(), req)
+	assert.Error(t, err)
+}
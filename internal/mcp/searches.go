@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/search"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithSearchService enables saved search tools
+func WithSearchService(service search.ServiceInterface) Option {
+	return func(h *Handler) {
+		h.searchService = service
+	}
+}
+
+// registerSearchTools registers the saved search tools
+func (h *Handler) registerSearchTools(s *server.MCPServer) {
+	saveTool := mcp.NewTool("save_search", append([]mcp.ToolOption{
+		mcp.WithDescription("Save search_rulesets filters under a name so the view can be rerun with run_saved_search. Relative dates like '-30d' are resolved on every run."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snake_case saved search name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithString("description", mcp.Description("What the saved search shows"), mcp.MaxLength(maxTextLength)),
+	}, searchFilterParams()...)...)
+	s.AddTool(saveTool, h.handleSaveSearch)
+
+	runTool := mcp.NewTool("run_saved_search",
+		mcp.WithDescription("Run a saved search and return the matching rulesets"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Exact saved search name"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(runTool, h.handleRunSavedSearch)
+
+	listTool := mcp.NewTool("list_saved_searches",
+		mcp.WithDescription("List saved searches with their filters"),
+	)
+	s.AddTool(listTool, h.handleListSavedSearches)
+
+	deleteTool := mcp.NewTool("delete_saved_search",
+		mcp.WithDescription("Delete a saved search by name"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Saved search name to delete"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(deleteTool, h.handleDeleteSavedSearch)
+}
+
+// HandleSaveSearch handles the save_search tool invocation (exported for testing)
+func (h *Handler) HandleSaveSearch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleSaveSearch(ctx, req)
+}
+
+// handleSaveSearch handles the save_search tool invocation
+func (h *Handler) handleSaveSearch(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	criteria, err := searchCriteria(req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid search criteria: %v", err)), nil
+	}
+
+	saved := &search.Saved{
+		Name:        name,
+		Description: req.GetString("description", ""),
+		Criteria:    criteria,
+	}
+
+	created, err := h.searchService.Save(saved)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to save search: %v", err)), nil
+	}
+
+	action := "updated"
+	if created {
+		action = "created"
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully %s saved search '%s'", action, name)), nil
+}
+
+// HandleRunSavedSearch handles the run_saved_search tool invocation (exported for testing)
+func (h *Handler) HandleRunSavedSearch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleRunSavedSearch(ctx, req)
+}
+
+// handleRunSavedSearch handles the run_saved_search tool invocation
+func (h *Handler) handleRunSavedSearch(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	saved, err := h.searchService.Get(name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to retrieve saved search: %v", err)), nil
+	}
+
+	filter, err := saved.Criteria.Filter(time.Now())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid search criteria: %v", err)), nil
+	}
+
+	return h.runSearch(filter), nil
+}
+
+// HandleListSavedSearches handles the list_saved_searches tool invocation (exported for testing)
+func (h *Handler) HandleListSavedSearches(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleListSavedSearches(ctx, req)
+}
+
+// handleListSavedSearches handles the list_saved_searches tool invocation
+func (h *Handler) handleListSavedSearches(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	searches, err := h.searchService.List()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list saved searches: %v", err)), nil
+	}
+
+	if len(searches) == 0 {
+		return mcp.NewToolResultText("No saved searches found"), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d saved search(es):\n\n", len(searches))
+	for _, saved := range searches {
+		fmt.Fprintf(&b, "- **%s**", saved.Name)
+		if saved.Description != "" {
+			fmt.Fprintf(&b, ": %s", saved.Description)
+		}
+		fmt.Fprintf(&b, "\n  Filters: %s\n", describeCriteria(saved.Criteria))
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// HandleDeleteSavedSearch handles the delete_saved_search tool invocation (exported for testing)
+func (h *Handler) HandleDeleteSavedSearch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleDeleteSavedSearch(ctx, req)
+}
+
+// handleDeleteSavedSearch handles the delete_saved_search tool invocation
+func (h *Handler) handleDeleteSavedSearch(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	if err := h.searchService.Delete(name); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete saved search: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted saved search '%s'", name)), nil
+}
+
+// describeCriteria renders the set filters of a saved search as name=value pairs
+func describeCriteria(c search.Criteria) string {
+	parts := make([]string, 0, 7)
+	add := func(name, value string) {
+		if value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	add("pattern", c.Pattern)
+	if len(c.Tags) > 0 {
+		add("tags", strings.Join(c.Tags, ","))
+	}
+	if len(c.Text) > 0 {
+		add("text", strings.Join(c.Text, ","))
+	}
+	add("license", c.License)
+	add("owner", c.Owner)
+	add("team", c.Team)
+	add("created_after", c.CreatedAfter)
+	add("modified_after", c.ModifiedAfter)
+	add("modified_before", c.ModifiedBefore)
+
+	if len(parts) == 0 {
+		return "all rulesets"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/search"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSavedSearchTools(t *testing.T) {
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService, WithSearchService(search.NewService(memstore.New())))
+
+	saveReq := mcp.CallToolRequest{}
+	saveReq.Params.Arguments = map[string]interface{}{
+		"name":           "recent_go",
+		"description":    "Go rules touched this month",
+		"pattern":        "go_*",
+		"tags":           []interface{}{"go"},
+		"modified_after": "-30d",
+	}
+	result, err := handler.HandleSaveSearch(context.TODO(), saveReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully created saved search 'recent_go'")
+
+	result, err = handler.HandleListSavedSearches(context.TODO(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+		"- **recent_go**: Go rules touched this month\n  Filters: pattern=go_* tags=go modified_after=-30d")
+
+	// Running resolves the relative date at call time
+	mockService.On("Find", mock.MatchedBy(func(f ruleset.Filter) bool {
+		return f.Pattern == "go_*" && len(f.Tags) == 1 && f.Tags[0] == "go" && !f.ModifiedAfter.IsZero()
+	})).Return([]*ruleset.Ruleset{{Name: "go_style", Description: "Go style"}}, nil)
+
+	runReq := mcp.CallToolRequest{}
+	runReq.Params.Arguments = map[string]interface{}{"name": "recent_go"}
+	result, err = handler.HandleRunSavedSearch(context.TODO(), runReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "- **go_style**: Go style")
+	mockService.AssertExpectations(t)
+
+	result, err = handler.HandleDeleteSavedSearch(context.TODO(), runReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	result, err = handler.HandleRunSavedSearch(context.TODO(), runReq)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "saved search 'recent_go' not found")
+}
+
+func TestHandleSaveSearch_InvalidCriteria(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService), WithSearchService(search.NewService(memstore.New())))
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "bad_dates", "created_after": "someday"}
+	result, err := handler.HandleSaveSearch(context.TODO(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid created_after")
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithServerConfig includes the effective, redacted server configuration in server_config results
+func WithServerConfig(snapshot map[string]any) Option {
+	return func(h *Handler) {
+		h.serverConfig = snapshot
+	}
+}
+
+// WithIndexStatus includes the progress of background index rebuilds in server_config results
+func WithIndexStatus(reporter ruleset.IndexStatusReporter) Option {
+	return func(h *Handler) {
+		h.indexStatus = reporter
+	}
+}
+
+// registerServerConfigTool registers the server_config tool
+func (h *Handler) registerServerConfigTool(s *server.MCPServer) {
+	configTool := mcp.NewTool("server_config",
+		mcp.WithDescription("Describe this server: enabled features, size and argument limits, naming rules, the effective (redacted) configuration and the progress of background index rebuilds. Call it to adapt to the server instead of discovering limits through errors."),
+	)
+	s.AddTool(configTool, h.handleServerConfig)
+}
+
+// HandleServerConfig handles the server_config tool invocation (exported for testing)
+func (h *Handler) HandleServerConfig(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleServerConfig(ctx, req)
+}
+
+// handleServerConfig reports the server's capabilities and configuration
+func (h *Handler) handleServerConfig(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	snapshot := map[string]any{
+		"server": map[string]any{
+			"name":    serverName,
+			"version": serverVersion,
+		},
+		"features": map[string]any{
+			"prompts":        h.promptService != nil,
+			"snippets":       h.snippetService != nil,
+			"saved_searches": h.searchService != nil,
+			"packs":          h.packInstaller != nil,
+			"pack_registry":  h.packRegistry != nil,
+			"refresh":        h.sourceFetcher != nil,
+			"admin":          h.maintainer != nil,
+			"changesets":     h.changesets != nil,
+			"markers":        h.markers != nil,
+			"welcome":        h.welcome != "",
+			"metrics":        h.metrics != nil,
+		},
+		"naming": map[string]any{
+			"name_pattern":    validation.NamePattern,
+			"max_name_length": maxNameLength,
+			"tag_pattern":     tagPattern,
+		},
+		"arguments": map[string]any{
+			"max_text_length": maxTextLength,
+			"max_url_length":  maxURLLength,
+		},
+		"content_types": ruleset.SupportedContentTypes,
+	}
+	if h.serverConfig != nil {
+		snapshot["config"] = h.serverConfig
+	}
+	if h.indexStatus != nil {
+		snapshot["indexing"] = h.indexStatus.IndexStatus()
+	}
+
+	text, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to encode server configuration: %v", err)), nil
+	}
+	return mcp.NewToolResultStructured(snapshot, string(text)), nil
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleServerConfig(t *testing.T) {
+	t.Run("reports features and configuration", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService),
+			WithSourceFetcher(stubFetcher{}),
+			WithServerConfig(map[string]any{"limits": map[string]any{"max_markdown_bytes": 65536}}),
+		)
+
+		result, err := handler.HandleServerConfig(context.TODO(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		var snapshot map[string]any
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &snapshot))
+
+		features := snapshot["features"].(map[string]any)
+		assert.Equal(t, true, features["refresh"])
+		assert.Equal(t, false, features["admin"])
+		assert.Equal(t, `^[a-z][a-z0-9]*(_[a-z0-9]+)*$`, snapshot["naming"].(map[string]any)["name_pattern"])
+		assert.Equal(t, float64(maxNameLength), snapshot["naming"].(map[string]any)["max_name_length"])
+		assert.Equal(t, float64(65536), snapshot["config"].(map[string]any)["limits"].(map[string]any)["max_markdown_bytes"])
+		assert.NotNil(t, result.StructuredContent)
+	})
+
+	t.Run("omits configuration when not provided", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService))
+
+		result, err := handler.HandleServerConfig(context.TODO(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+		assert.NotContains(t, result.Content[0].(mcp.TextContent).Text, `"config"`)
+		assert.NotContains(t, result.Content[0].(mcp.TextContent).Text, `"indexing"`)
+	})
+
+	t.Run("reports background index rebuilds", func(t *testing.T) {
+		handler := NewHandler(new(MockRulesetService),
+			WithIndexStatus(stubIndexStatus{Running: true, Done: 40, Total: 250}),
+		)
+
+		result, err := handler.HandleServerConfig(context.TODO(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+
+		var snapshot map[string]any
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &snapshot))
+		indexing := snapshot["indexing"].(map[string]any)
+		assert.Equal(t, true, indexing["running"])
+		assert.Equal(t, float64(40), indexing["done"])
+		assert.Equal(t, float64(250), indexing["total"])
+	})
+}
+
+// stubIndexStatus reports a fixed index rebuild status
+type stubIndexStatus ruleset.IndexStatus
+
+func (s stubIndexStatus) IndexStatus() ruleset.IndexStatus {
+	return ruleset.IndexStatus(s)
+}
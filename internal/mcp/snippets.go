@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithSnippetService enables snippet tools and {{snippet:name}} expansion in rulesets
+func WithSnippetService(service snippet.ServiceInterface) Option {
+	return func(h *Handler) {
+		h.snippetService = service
+	}
+}
+
+// registerSnippetTools registers the snippet CRUD tools
+func (h *Handler) registerSnippetTools(s *server.MCPServer) {
+	upsertTool := mcp.NewTool("upsert_snippet",
+		mcp.WithDescription(fmt.Sprintf("Create or replace a short reusable rule fragment (at most %d bytes). Rulesets include snippets with {{snippet:name}}.", snippet.MaxTextBytes)),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snake_case snippet name"), mcp.MaxLength(maxNameLength)),
+		mcp.WithString("text", mcp.Required(), mcp.Description("Snippet text")),
+		tagsParam("Tags for filtering"),
+	)
+	s.AddTool(upsertTool, h.handleUpsertSnippet)
+
+	getTool := mcp.NewTool("get_snippet",
+		mcp.WithDescription("Retrieve a snippet by exact name"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Exact snippet name"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(getTool, h.handleGetSnippet)
+
+	listTool := mcp.NewTool("list_snippets",
+		mcp.WithDescription("List snippets with their text, optionally filtered by tag"),
+		mcp.WithString("tag", mcp.Description("Only list snippets carrying this tag"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(listTool, h.handleListSnippets)
+
+	deleteTool := mcp.NewTool("delete_snippet",
+		mcp.WithDescription("Delete a snippet by name"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snippet name to delete"), mcp.MaxLength(maxNameLength)),
+	)
+	s.AddTool(deleteTool, h.handleDeleteSnippet)
+}
+
+// HandleUpsertSnippet handles the upsert_snippet tool invocation (exported for testing)
+func (h *Handler) HandleUpsertSnippet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleUpsertSnippet(ctx, req)
+}
+
+// handleUpsertSnippet handles the upsert_snippet tool invocation
+func (h *Handler) handleUpsertSnippet(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	text, err := req.RequireString("text")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'text': %v", err)), nil
+	}
+
+	tags, err := parseTags(req.GetArguments()["tags"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	sn := &snippet.Snippet{Name: name, Text: text, Tags: tags}
+
+	created, err := h.snippetService.Save(sn)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to save snippet: %v", err)), nil
+	}
+
+	action := "updated"
+	if created {
+		action = "created"
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully %s snippet '%s'", action, name)), nil
+}
+
+// HandleGetSnippet handles the get_snippet tool invocation (exported for testing)
+func (h *Handler) HandleGetSnippet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleGetSnippet(ctx, req)
+}
+
+// handleGetSnippet handles the get_snippet tool invocation
+func (h *Handler) handleGetSnippet(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	sn, err := h.snippetService.Get(name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to retrieve snippet: %v", err)), nil
+	}
+
+	// Snippets are returned bare so they can be pasted directly
+	return mcp.NewToolResultText(sn.Text), nil
+}
+
+// HandleListSnippets handles the list_snippets tool invocation (exported for testing)
+func (h *Handler) HandleListSnippets(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleListSnippets(ctx, req)
+}
+
+// handleListSnippets handles the list_snippets tool invocation
+func (h *Handler) handleListSnippets(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tag := req.GetString("tag", "")
+
+	snippets, err := h.snippetService.List(tag)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list snippets: %v", err)), nil
+	}
+
+	if len(snippets) == 0 {
+		if tag == "" {
+			return mcp.NewToolResultText("No snippets found"), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("No snippets found with tag '%s'", tag)), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d snippet(s):\n\n", len(snippets))
+	for _, sn := range snippets {
+		fmt.Fprintf(&b, "- **%s**", sn.Name)
+		if len(sn.Tags) > 0 {
+			fmt.Fprintf(&b, " %v", sn.Tags)
+		}
+		fmt.Fprintf(&b, ": %s\n", sn.Text)
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// HandleDeleteSnippet handles the delete_snippet tool invocation (exported for testing)
+func (h *Handler) HandleDeleteSnippet(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.handleDeleteSnippet(ctx, req)
+}
+
+// handleDeleteSnippet handles the delete_snippet tool invocation
+func (h *Handler) handleDeleteSnippet(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("missing required parameter 'name': %v", err)), nil
+	}
+
+	if err := h.snippetService.Delete(name); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete snippet: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted snippet '%s'", name)), nil
+}
+
+// expandSnippets returns the content of rs with {{snippet:name}} includes resolved.
+// JSON content is left untouched so it stays parseable.
+func (h *Handler) expandSnippets(rs *ruleset.Ruleset) string {
+	if h.snippetService == nil || ruleset.IsJSONContentType(rs.ContentType) {
+		return rs.Markdown
+	}
+	return snippet.Expand(rs.Markdown, h.snippetService.Get)
+}
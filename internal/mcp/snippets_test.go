@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSnippetTools(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService), WithSnippetService(snippet.NewService(memstore.New())))
+
+	upsertReq := mcp.CallToolRequest{}
+	upsertReq.Params.Arguments = map[string]interface{}{
+		"name": "no_tabs",
+		"text": "Use spaces, not tabs.",
+		"tags": "style, whitespace",
+	}
+	result, err := handler.HandleUpsertSnippet(context.TODO(), upsertReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Successfully created snippet 'no_tabs'")
+
+	getReq := mcp.CallToolRequest{}
+	getReq.Params.Arguments = map[string]interface{}{"name": "no_tabs"}
+	result, err = handler.HandleGetSnippet(context.TODO(), getReq)
+	require.NoError(t, err)
+	assert.Equal(t, "Use spaces, not tabs.", result.Content[0].(mcp.TextContent).Text)
+
+	listReq := mcp.CallToolRequest{}
+	listReq.Params.Arguments = map[string]interface{}{"tag": "style"}
+	result, err = handler.HandleListSnippets(context.TODO(), listReq)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "- **no_tabs** [style whitespace]: Use spaces, not tabs.")
+
+	listReq.Params.Arguments = map[string]interface{}{"tag": "git"}
+	result, err = handler.HandleListSnippets(context.TODO(), listReq)
+	require.NoError(t, err)
+	assert.Equal(t, "No snippets found with tag 'git'", result.Content[0].(mcp.TextContent).Text)
+
+	result, err = handler.HandleDeleteSnippet(context.TODO(), getReq)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	result, err = handler.HandleGetSnippet(context.TODO(), getReq)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestHandleGetRuleset_ExpandsSnippets(t *testing.T) {
+	snippets := snippet.NewService(memstore.New())
+	_, err := snippets.Save(&snippet.Snippet{Name: "no_tabs", Text: "Use spaces, not tabs."})
+	require.NoError(t, err)
+
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService, WithSnippetService(snippets))
+
+	mockService.On("Get", "style_guide").Return(&ruleset.Ruleset{
+		Name:        "style_guide",
+		ContentType: ruleset.ContentTypeMarkdown,
+		Markdown:    "# Style\n{{snippet:no_tabs}}",
+	}, nil)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"name": "style_guide"}
+	result, err := handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "# Style\nUse spaces, not tabs.")
+
+	// Raw content is available for editing
+	req.Params.Arguments = map[string]interface{}{"name": "style_guide", "expand_snippets": false}
+	result, err = handler.HandleGetRuleset(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "# Style\n{{snippet:no_tabs}}")
+
+	resourceReq := mcp.ReadResourceRequest{}
+	resourceReq.Params.URI = "ruleset://style_guide"
+	contents, err := handler.HandleResourceRead(context.TODO(), resourceReq)
+	require.NoError(t, err)
+	assert.Contains(t, contents[0].(mcp.TextResourceContents).Text, "Use spaces, not tabs.")
+}
+
+func TestHandleGetRuleset_ConditionalCoversSnippets(t *testing.T) {
+	snippets := snippet.NewService(memstore.New())
+	_, err := snippets.Save(&snippet.Snippet{Name: "no_tabs", Text: "Use spaces, not tabs."})
+	require.NoError(t, err)
+
+	mockService := new(MockRulesetService)
+	handler := NewHandler(mockService, WithSnippetService(snippets))
+
+	rs := &ruleset.Ruleset{
+		Name:        "style_guide",
+		ContentType: ruleset.ContentTypeMarkdown,
+		Markdown:    "# Style\n{{snippet:no_tabs}}",
+		Version:     2,
+	}
+	mockService.On("Get", "style_guide").Return(rs, nil)
+
+	get := func(args map[string]interface{}) string {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]interface{}{"name": "style_guide"}
+		for k, v := range args {
+			req.Params.Arguments.(map[string]interface{})[k] = v
+		}
+		result, err := handler.HandleGetRuleset(context.TODO(), req)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	// The header reports the checksum of the expanded content next to the stored one
+	text := get(nil)
+	assert.Contains(t, text, "checksum: "+rs.Checksum()+"\ncontent_checksum: ")
+	contentChecksum := strings.TrimPrefix(regexp.MustCompile(`content_checksum: \w+`).FindString(text), "content_checksum: ")
+	require.NotEmpty(t, contentChecksum)
+
+	assert.Equal(t, "Ruleset 'style_guide' is unchanged (version 2, checksum "+contentChecksum+")",
+		get(map[string]interface{}{"if_checksum_not": contentChecksum}))
+	// The stored checksum and version do not vouch for the expanded content
+	assert.Contains(t, get(map[string]interface{}{"if_checksum_not": rs.Checksum()}), "Use spaces, not tabs.")
+	assert.Contains(t, get(map[string]interface{}{"if_version_not": float64(2)}), "Use spaces, not tabs.")
+	// Without expansion the stored checksum and version apply
+	assert.Equal(t, "Ruleset 'style_guide' is unchanged (version 2, checksum "+rs.Checksum()+")",
+		get(map[string]interface{}{"if_version_not": float64(2), "if_checksum_not": rs.Checksum(), "expand_snippets": false}))
+
+	// Editing the included snippet changes the content, so the old checksum is stale
+	_, err = snippets.Save(&snippet.Snippet{Name: "no_tabs", Text: "Indent with two spaces."})
+	require.NoError(t, err)
+	assert.Contains(t, get(map[string]interface{}{"if_checksum_not": contentChecksum}), "Indent with two spaces.")
+}
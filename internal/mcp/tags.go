@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// tagPattern restricts tags to a letter or digit followed by letters, digits and . _ + # -
+const tagPattern = `^[A-Za-z0-9][A-Za-z0-9._+#-]*$`
+
+// tagListPattern matches one or more comma-separated tags, as accepted in array items
+const tagListPattern = `^\s*[A-Za-z0-9][A-Za-z0-9._+#-]*\s*(,\s*[A-Za-z0-9][A-Za-z0-9._+#-]*\s*)*,?\s*$`
+
+var tagRegex = regexp.MustCompile(tagPattern)
+
+// tagsParam declares a tags parameter that accepts an array of strings or a
+// comma-separated string, since clients serialize lists differently
+func tagsParam(description string) mcp.ToolOption {
+	return namedTagsParam("tags", description)
+}
+
+// namedTagsParam declares a tags parameter under another name, see tagsParam
+func namedTagsParam(name, description string) mcp.ToolOption {
+	return mcp.WithAny(name,
+		mcp.Description(description+`. Pass an array (["go", "style"]) or a comma-separated string ("go,style").`),
+		func(schema map[string]any) {
+			schema["type"] = []string{"array", "string"}
+		},
+		mcp.WithStringItems(mcp.Pattern(tagListPattern), mcp.MaxLength(maxTextLength)),
+	)
+}
+
+// parseTags normalizes a tags argument into the canonical slice. It accepts a
+// comma-separated string, an array of strings, or an array whose items are
+// themselves comma-separated; whitespace is trimmed, empty entries dropped and
+// repeated tags (ignoring case) kept once in first-seen order.
+func parseTags(raw any) ([]string, error) {
+	var values []string
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		values = strings.Split(v, ",")
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("tags must be strings, got %T", item)
+			}
+			values = append(values, strings.Split(s, ",")...)
+		}
+	case []string:
+		for _, s := range v {
+			values = append(values, strings.Split(s, ",")...)
+		}
+	default:
+		return nil, fmt.Errorf("tags must be an array of strings or a comma-separated string")
+	}
+
+	tags := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		tag := strings.TrimSpace(value)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		if !tagRegex.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag '%s': tags must start with a letter or digit and contain only letters, digits and . _ + # -", tag)
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     any
+		want    []string
+		wantErr string
+	}{
+		{name: "array", raw: []any{"go", "style"}, want: []string{"go", "style"}},
+		{name: "comma-separated string", raw: "go, style ,c++", want: []string{"go", "style", "c++"}},
+		{name: "empty string clears tags", raw: "", want: []string{}},
+		{name: "empty entries dropped", raw: []any{"go", " ", ""}, want: []string{"go"}},
+		{name: "comma-separated items", raw: []any{"go,style", "lint"}, want: []string{"go", "style", "lint"}},
+		{name: "repeated values kept once", raw: []any{"go", "Go", "style,go"}, want: []string{"go", "style"}},
+		{name: "string slice", raw: []string{"go", "style"}, want: []string{"go", "style"}},
+		{name: "absent", raw: nil, want: nil},
+		{name: "non-string item", raw: []any{"go", 1.0}, wantErr: "tags must be strings"},
+		{name: "invalid tag", raw: "go,code style", wantErr: "invalid tag 'code style'"},
+		{name: "wrong type", raw: 3.0, wantErr: "tags must be an array of strings or a comma-separated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := parseTags(tt.raw)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tags)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// welcomeURI is the URI of the onboarding resource
+const welcomeURI = "archivyr://welcome"
+
+// welcomeInstructions are returned to clients on initialize when a welcome
+// resource is configured, pointing agents at it
+const welcomeInstructions = "Read the " + welcomeURI + " resource before using this server; it describes this organisation's conventions for using rulesets."
+
+// WithWelcome publishes markdown describing the organisation's conventions for
+// using the server as the archivyr://welcome resource
+func WithWelcome(markdown string) Option {
+	return func(h *Handler) {
+		h.welcome = markdown
+	}
+}
+
+// registerWelcomeResource exposes the welcome resource
+func (h *Handler) registerWelcomeResource(s *server.MCPServer) {
+	resource := mcp.NewResource(
+		welcomeURI,
+		"Welcome",
+		mcp.WithResourceDescription("Onboarding instructions: this organisation's conventions for using the ruleset server. Read it once when connecting."),
+		mcp.WithMIMEType(ruleset.ContentTypeMarkdown),
+	)
+	s.AddResource(resource, h.handleWelcomeRead)
+}
+
+// HandleWelcomeRead handles resource reads of the welcome resource (exported for testing)
+func (h *Handler) HandleWelcomeRead(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return h.handleWelcomeRead(ctx, req)
+}
+
+// handleWelcomeRead returns the configured welcome markdown
+func (h *Handler) handleWelcomeRead(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: ruleset.ContentTypeMarkdown,
+			Text:     h.welcome,
+		},
+	}, nil
+}
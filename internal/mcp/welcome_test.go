@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listResourceURIs sends resources/list to the server and returns the resource URIs
+func listResourceURIs(t *testing.T, s *server.MCPServer) []string {
+	raw := s.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+	resp, ok := raw.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	result, ok := resp.Result.(mcp.ListResourcesResult)
+	require.True(t, ok)
+
+	uris := make([]string, 0, len(result.Resources))
+	for _, r := range result.Resources {
+		uris = append(uris, r.URI)
+	}
+	return uris
+}
+
+func TestWelcomeResource(t *testing.T) {
+	welcome := "# Welcome\n\nCall get_mandatory_rulesets first."
+	handler := NewHandler(new(MockRulesetService), WithWelcome(welcome))
+	s := server.NewMCPServer("Test Server", "1.0.0", server.WithResourceCapabilities(true, true))
+	handler.RegisterResources(s)
+	assert.Contains(t, listResourceURIs(t, s), welcomeURI)
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = welcomeURI
+	contents, err := handler.HandleWelcomeRead(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	text := contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, "text/markdown", text.MIMEType)
+	assert.Equal(t, welcome, text.Text)
+}
+
+func TestWelcomeResource_NotConfigured(t *testing.T) {
+	handler := NewHandler(new(MockRulesetService))
+	s := server.NewMCPServer("Test Server", "1.0.0", server.WithResourceCapabilities(true, true))
+	handler.RegisterResources(s)
+
+	assert.NotContains(t, listResourceURIs(t, s), welcomeURI)
+}
@@ -0,0 +1,224 @@
+// Package memstore provides an in-memory implementation of the key-value commands
+// used by the ruleset service, for unit tests and embedders without a Valkey server.
+package memstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Store is a concurrency-safe, in-memory key-value store
+type Store struct {
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+	zsets  map[string]map[string]float64
+}
+
+// New creates an empty in-memory store
+func New() *Store {
+	return &Store{
+		hashes: make(map[string]map[string]string),
+		zsets:  make(map[string]map[string]float64),
+	}
+}
+
+// Exists reports whether the key exists
+func (s *Store) Exists(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, isHash := s.hashes[key]
+	_, isZSet := s.zsets[key]
+	return isHash || isZSet, nil
+}
+
+// HGetAll returns a copy of all fields of the hash stored at key, or an empty map if it does not exist
+func (s *Store) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := make(map[string]string, len(s.hashes[key]))
+	for field, value := range s.hashes[key] {
+		fields[field] = value
+	}
+	return fields, nil
+}
+
+// HSet sets the given fields on the hash stored at key
+func (s *Store) HSet(_ context.Context, key string, fields map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, ok := s.hashes[key]
+	if !ok {
+		hash = make(map[string]string, len(fields))
+		s.hashes[key] = hash
+	}
+	for field, value := range fields {
+		hash[field] = value
+	}
+	return nil
+}
+
+// Del removes the given keys
+func (s *Store) Del(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.hashes, key)
+		delete(s.zsets, key)
+	}
+	return nil
+}
+
+// ScanKeys returns every key matching the glob pattern in lexical order
+func (s *Store) ScanKeys(_ context.Context, match string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0)
+	for key := range s.hashes {
+		if Match(match, key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range s.zsets {
+		if Match(match, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// UpsertHash atomically sets create on a missing key or update on an existing one
+// and reports whether the key existed. A nil map leaves the key untouched in that case.
+func (s *Store) UpsertHash(_ context.Context, key string, create, update map[string]string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, exists := s.hashes[key]
+	if !exists {
+		if len(create) > 0 {
+			hash = make(map[string]string, len(create))
+			s.hashes[key] = hash
+		}
+		for field, value := range create {
+			hash[field] = value
+		}
+		return false, nil
+	}
+
+	for field, value := range update {
+		hash[field] = value
+	}
+	return true, nil
+}
+
+// DelIfField atomically deletes key only while its hash field holds value
+// and reports whether it was deleted
+func (s *Store) DelIfField(_ context.Context, key, field, value string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.hashes[key][field]
+	if !ok || current != value {
+		return false, nil
+	}
+	delete(s.hashes, key)
+	return true, nil
+}
+
+// ZAdd adds member to the sorted set at key, or updates its score
+func (s *Store) ZAdd(_ context.Context, key, member string, score float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zset, ok := s.zsets[key]
+	if !ok {
+		zset = make(map[string]float64)
+		s.zsets[key] = zset
+	}
+	zset[member] = score
+	return nil
+}
+
+// ZRem removes members from the sorted set at key
+func (s *Store) ZRem(_ context.Context, key string, members ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zset := s.zsets[key]
+	for _, member := range members {
+		delete(zset, member)
+	}
+	if zset != nil && len(zset) == 0 {
+		delete(s.zsets, key)
+	}
+	return nil
+}
+
+// ZRangeByScore returns the members of the sorted set at key with min <= score <= max,
+// ordered by score and then member like Valkey
+func (s *Store) ZRangeByScore(_ context.Context, key string, minScore, maxScore float64) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zset := s.zsets[key]
+	members := make([]string, 0)
+	for member, score := range zset {
+		if score >= minScore && score <= maxScore {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if zset[members[i]] != zset[members[j]] {
+			return zset[members[i]] < zset[members[j]]
+		}
+		return members[i] < members[j]
+	})
+	return members, nil
+}
+
+// Match reports whether key matches a Valkey-style glob pattern.
+// Supports * (any characters), ? (single character) and backslash escapes.
+func Match(pattern, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			// Collapse consecutive stars, then try every possible split
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(key); i++ {
+				if Match(pattern, key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if key == "" {
+				return false
+			}
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			if key == "" || key[0] != pattern[0] {
+				return false
+			}
+		default:
+			if key == "" || key[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		key = key[1:]
+	}
+	return key == ""
+}
@@ -0,0 +1,155 @@
+package memstore
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_HashCommands(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	exists, err := store.Exists(ctx, "ruleset:one")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, store.HSet(ctx, "ruleset:one", map[string]string{"a": "1", "b": "2"}))
+	require.NoError(t, store.HSet(ctx, "ruleset:one", map[string]string{"b": "3"}))
+
+	fields, err := store.HGetAll(ctx, "ruleset:one")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "3"}, fields)
+
+	// Returned maps are copies
+	fields["a"] = "changed"
+	fields, err = store.HGetAll(ctx, "ruleset:one")
+	require.NoError(t, err)
+	assert.Equal(t, "1", fields["a"])
+
+	require.NoError(t, store.Del(ctx, "ruleset:one", "missing"))
+	fields, err = store.HGetAll(ctx, "ruleset:one")
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestStore_ScanKeys(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	for _, key := range []string{"ruleset:b", "ruleset:a", "other:c"} {
+		require.NoError(t, store.HSet(ctx, key, map[string]string{"x": "y"}))
+	}
+
+	keys, err := store.ScanKeys(ctx, "ruleset:*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ruleset:a", "ruleset:b"}, keys)
+
+	keys, err = store.ScanKeys(ctx, "*")
+	require.NoError(t, err)
+	assert.Len(t, keys, 3)
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"*", "anything", true},
+		{"ruleset:*", "ruleset:python", true},
+		{"ruleset:*", "snippet:python", false},
+		{"*python*", "ruleset:python_style", true},
+		{"ruleset:?o", "ruleset:go", true},
+		{"ruleset:?o", "ruleset:goo", false},
+		{`a\*b`, "a*b", true},
+		{`a\*b`, "axb", false},
+		{"", "", true},
+		{"", "x", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+"_"+tc.key, func(t *testing.T) {
+			assert.Equal(t, tc.want, Match(tc.pattern, tc.key))
+		})
+	}
+}
+
+func TestStore_UpsertHash(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	existed, err := store.UpsertHash(ctx, "ruleset:one", map[string]string{"a": "1"}, map[string]string{"a": "2"})
+	require.NoError(t, err)
+	assert.False(t, existed)
+
+	existed, err = store.UpsertHash(ctx, "ruleset:one", map[string]string{"a": "1"}, map[string]string{"a": "2"})
+	require.NoError(t, err)
+	assert.True(t, existed)
+
+	fields, err := store.HGetAll(ctx, "ruleset:one")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "2"}, fields)
+
+	// A nil map leaves the key untouched
+	existed, err = store.UpsertHash(ctx, "ruleset:two", nil, map[string]string{"a": "2"})
+	require.NoError(t, err)
+	assert.False(t, existed)
+	exists, err := store.Exists(ctx, "ruleset:two")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestStore_DelIfField(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	require.NoError(t, store.HSet(ctx, "blob:one", map[string]string{"stored_at": "1"}))
+
+	deleted, err := store.DelIfField(ctx, "blob:one", "stored_at", "0")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	exists, err := store.Exists(ctx, "blob:one")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	deleted, err = store.DelIfField(ctx, "blob:one", "stored_at", "1")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	exists, err = store.Exists(ctx, "blob:one")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	deleted, err = store.DelIfField(ctx, "blob:missing", "stored_at", "")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestStore_SortedSets(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	require.NoError(t, store.ZAdd(ctx, "index", "b", 2))
+	require.NoError(t, store.ZAdd(ctx, "index", "a", 2))
+	require.NoError(t, store.ZAdd(ctx, "index", "c", 1))
+	require.NoError(t, store.ZAdd(ctx, "index", "d", 5))
+
+	members, err := store.ZRangeByScore(ctx, "index", math.Inf(-1), math.Inf(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a", "b", "d"}, members)
+
+	members, err = store.ZRangeByScore(ctx, "index", 2, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "d"}, members)
+
+	keys, err := store.ScanKeys(ctx, "ind*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"index"}, keys)
+
+	require.NoError(t, store.ZRem(ctx, "index", "a", "b", "c", "d"))
+	exists, err := store.Exists(ctx, "index")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
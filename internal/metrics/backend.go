@@ -0,0 +1,51 @@
+package metrics
+
+// namespace prefixes the name of every instrument
+const namespace = "archivyr"
+
+// Counter is a monotonically increasing value
+type Counter interface {
+	Add(delta float64)
+}
+
+// Histogram records the distribution of observed values
+type Histogram interface {
+	Observe(value float64)
+}
+
+// Gauge is a value that can go up and down
+type Gauge interface {
+	Set(value float64)
+}
+
+// Backend creates instruments in a telemetry system, so embedders can bridge
+// the server's metrics to the system they already run. Names are given in
+// Prometheus style without the archivyr namespace, which backends add, and
+// counter names without the _total suffix, e.g. "legacy_uri_reads".
+type Backend interface {
+	Counter(name, help string) (Counter, error)
+	// Histogram creates a histogram with the given upper bucket bounds
+	Histogram(name, help string, buckets []float64) (Histogram, error)
+	Gauge(name, help string) (Gauge, error)
+}
+
+// noopBackend discards every measurement
+type noopBackend struct{}
+
+// noop is an instrument ignoring every measurement
+type noop struct{}
+
+func (noop) Add(float64)     {}
+func (noop) Observe(float64) {}
+func (noop) Set(float64)     {}
+
+// NewNoopBackend returns a backend whose instruments discard every measurement
+func NewNoopBackend() Backend {
+	return noopBackend{}
+}
+
+func (noopBackend) Counter(string, string) (Counter, error) { return noop{}, nil }
+
+func (noopBackend) Histogram(string, string, []float64) (Histogram, error) { return noop{}, nil }
+
+func (noopBackend) Gauge(string, string) (Gauge, error) { return noop{}, nil }
@@ -0,0 +1,149 @@
+// Package metrics provides instrumentation for the MCP Ruleset Server,
+// recorded to Prometheus by default or to another Backend.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the server's instruments
+type Metrics struct {
+	markdownSize  Histogram
+	searchResults Histogram
+	legacyURIs    Counter
+	// storedRulesets and storedQuantiles describe the stored corpus as of the
+	// last RecordCorpus
+	storedRulesets  Gauge
+	storedQuantiles []storedQuantile
+}
+
+// storedQuantile is a gauge holding a quantile of the stored content sizes
+type storedQuantile struct {
+	q     float64
+	gauge Gauge
+}
+
+// corpusQuantiles are the quantiles of stored content sizes exposed as gauges;
+// 1 is the largest ruleset
+var corpusQuantiles = []struct {
+	name string
+	q    float64
+}{
+	{"p50", 0.5},
+	{"p90", 0.9},
+	{"p99", 0.99},
+	{"max", 1},
+}
+
+// New creates the server's instruments as Prometheus collectors registered with reg
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	return NewWithBackend(NewPrometheusBackend(reg))
+}
+
+// NewWithBackend creates the server's instruments in backend
+func NewWithBackend(backend Backend) (*Metrics, error) {
+	m := &Metrics{}
+
+	var err error
+	// 256 B up to 512 KiB
+	if m.markdownSize, err = backend.Histogram("markdown_write_size_bytes", "Size of ruleset markdown content per write.", prometheus.ExponentialBuckets(256, 2, 12)); err != nil {
+		return nil, fmt.Errorf("failed to create markdown size histogram: %w", err)
+	}
+	if m.searchResults, err = backend.Histogram("search_results", "Number of rulesets returned per search.", []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}); err != nil {
+		return nil, fmt.Errorf("failed to create search results histogram: %w", err)
+	}
+	if m.legacyURIs, err = backend.Counter("legacy_uri_reads", "Resource reads using the deprecated ruleset:{name} URI form."); err != nil {
+		return nil, fmt.Errorf("failed to create legacy URI counter: %w", err)
+	}
+	if m.storedRulesets, err = backend.Gauge("stored_rulesets", "Number of stored rulesets."); err != nil {
+		return nil, fmt.Errorf("failed to create stored rulesets gauge: %w", err)
+	}
+	for _, cq := range corpusQuantiles {
+		gauge, err := backend.Gauge("stored_markdown_"+cq.name+"_bytes", "Size of stored ruleset markdown content, "+cq.name+" across rulesets.")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stored markdown %s gauge: %w", cq.name, err)
+		}
+		m.storedQuantiles = append(m.storedQuantiles, storedQuantile{q: cq.q, gauge: gauge})
+	}
+
+	return m, nil
+}
+
+// ObserveMarkdownSize records the size in bytes of markdown content written to storage
+func (m *Metrics) ObserveMarkdownSize(size int) {
+	if m == nil {
+		return
+	}
+	m.markdownSize.Observe(float64(size))
+}
+
+// ObserveSearchResults records the number of rulesets returned by a search
+func (m *Metrics) ObserveSearchResults(count int) {
+	if m == nil {
+		return
+	}
+	m.searchResults.Observe(float64(count))
+}
+
+// ObserveLegacyURI records a resource read using the deprecated ruleset:{name} URI form
+func (m *Metrics) ObserveLegacyURI() {
+	if m == nil {
+		return
+	}
+	m.legacyURIs.Add(1)
+}
+
+// Hooks returns ruleset service hooks recording the size of content written by
+// any path, such as tools, pack installs, refreshes, loads and changesets
+func (m *Metrics) Hooks() ruleset.Hooks {
+	if m == nil {
+		return ruleset.Hooks{}
+	}
+	return ruleset.Hooks{
+		AfterCreate: func(rs *ruleset.Ruleset) { m.ObserveMarkdownSize(len(rs.Markdown)) },
+		AfterUpdate: func(previous, updated *ruleset.Ruleset) {
+			if updated.Markdown != previous.Markdown {
+				m.ObserveMarkdownSize(len(updated.Markdown))
+			}
+		},
+	}
+}
+
+// RecordCorpus sets the stored corpus gauges from rulesets, every stored
+// ruleset, replacing the previous values. Quantiles of an empty corpus are 0.
+func (m *Metrics) RecordCorpus(rulesets []*ruleset.Ruleset) {
+	if m == nil {
+		return
+	}
+	sizes := make([]int, len(rulesets))
+	for i, rs := range rulesets {
+		sizes[i] = len(rs.Markdown)
+	}
+	sort.Ints(sizes)
+
+	m.storedRulesets.Set(float64(len(sizes)))
+	for _, sq := range m.storedQuantiles {
+		sq.gauge.Set(float64(nearestRank(sizes, sq.q)))
+	}
+}
+
+// nearestRank returns the q quantile of sorted by the nearest-rank method
+func nearestRank(sorted []int, q float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// Handler returns an HTTP handler exposing the metrics gathered by g
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
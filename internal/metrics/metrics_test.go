@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_RegistersCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	m, err := New(reg)
+	require.NoError(t, err)
+
+	m.ObserveMarkdownSize(1024)
+	m.ObserveMarkdownSize(300)
+	m.ObserveSearchResults(3)
+	m.ObserveLegacyURI()
+	m.ObserveLegacyURI()
+
+	count, err := testutil.GatherAndCount(reg, "archivyr_markdown_write_size_bytes", "archivyr_search_results", "archivyr_legacy_uri_reads_total")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.legacyURIs.(prometheus.Counter)))
+
+	// Registering twice on the same registry fails
+	_, err = New(reg)
+	assert.Error(t, err)
+}
+
+func TestMetrics_HooksAndCorpus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	require.NoError(t, err)
+	histogram := func() *dto.Histogram {
+		families, err := reg.Gather()
+		require.NoError(t, err)
+		for _, f := range families {
+			if f.GetName() == "archivyr_markdown_write_size_bytes" {
+				return f.GetMetric()[0].GetHistogram()
+			}
+		}
+		t.Fatal("markdown size histogram not gathered")
+		return nil
+	}
+
+	hooks := m.Hooks()
+	hooks.AfterCreate(&ruleset.Ruleset{Markdown: "# New"})
+	hooks.AfterUpdate(&ruleset.Ruleset{Markdown: "# New"}, &ruleset.Ruleset{Markdown: "# New", Description: "metadata only"})
+	hooks.AfterUpdate(&ruleset.Ruleset{Markdown: "# New"}, &ruleset.Ruleset{Markdown: "# Newer"})
+
+	// Updates leaving the content as it was write no content
+	assert.Equal(t, uint64(2), histogram().GetSampleCount())
+	assert.Equal(t, float64(5+7), histogram().GetSampleSum())
+}
+
+func TestMetrics_RecordCorpus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	require.NoError(t, err)
+
+	corpus := make([]*ruleset.Ruleset, 0, 100)
+	for size := 100; size >= 1; size-- {
+		corpus = append(corpus, &ruleset.Ruleset{Markdown: strings.Repeat("x", size)})
+	}
+	m.RecordCorpus(corpus)
+
+	expected := `
+# HELP archivyr_stored_markdown_max_bytes Size of stored ruleset markdown content, max across rulesets.
+# TYPE archivyr_stored_markdown_max_bytes gauge
+archivyr_stored_markdown_max_bytes 100
+# HELP archivyr_stored_markdown_p50_bytes Size of stored ruleset markdown content, p50 across rulesets.
+# TYPE archivyr_stored_markdown_p50_bytes gauge
+archivyr_stored_markdown_p50_bytes 50
+# HELP archivyr_stored_markdown_p90_bytes Size of stored ruleset markdown content, p90 across rulesets.
+# TYPE archivyr_stored_markdown_p90_bytes gauge
+archivyr_stored_markdown_p90_bytes 90
+# HELP archivyr_stored_markdown_p99_bytes Size of stored ruleset markdown content, p99 across rulesets.
+# TYPE archivyr_stored_markdown_p99_bytes gauge
+archivyr_stored_markdown_p99_bytes 99
+# HELP archivyr_stored_rulesets Number of stored rulesets.
+# TYPE archivyr_stored_rulesets gauge
+archivyr_stored_rulesets 100
+`
+	names := []string{"archivyr_stored_rulesets", "archivyr_stored_markdown_p50_bytes", "archivyr_stored_markdown_p90_bytes", "archivyr_stored_markdown_p99_bytes", "archivyr_stored_markdown_max_bytes"}
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), names...))
+
+	// Each recomputation replaces the previous values, so deletions and
+	// shrinking rulesets lower them
+	m.RecordCorpus([]*ruleset.Ruleset{{Markdown: "abc"}})
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.storedRulesets.(prometheus.Gauge)))
+	for _, sq := range m.storedQuantiles {
+		assert.Equal(t, 3.0, testutil.ToFloat64(sq.gauge.(prometheus.Gauge)))
+	}
+
+	m.RecordCorpus(nil)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.storedRulesets.(prometheus.Gauge)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.storedQuantiles[3].gauge.(prometheus.Gauge)))
+}
+
+func TestMetrics_NilReceiver(t *testing.T) {
+	var m *Metrics
+
+	assert.NotPanics(t, func() {
+		m.ObserveMarkdownSize(10)
+		m.ObserveSearchResults(1)
+		m.ObserveLegacyURI()
+		m.RecordCorpus([]*ruleset.Ruleset{{Markdown: "x"}})
+	})
+	assert.Equal(t, ruleset.Hooks{}, m.Hooks())
+}
+
+func TestHandler(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	require.NoError(t, err)
+	m.ObserveSearchResults(7)
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "archivyr_search_results_count 1")
+}
+
+func TestNewWithBackend_Noop(t *testing.T) {
+	m, err := NewWithBackend(NewNoopBackend())
+	require.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		m.ObserveMarkdownSize(10)
+		m.ObserveSearchResults(1)
+		m.ObserveLegacyURI()
+	})
+}
+
+func TestPrometheusBackend_Gauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	backend := NewPrometheusBackend(reg)
+
+	g, err := backend.Gauge("queued_writes", "Writes waiting to be replayed.")
+	require.NoError(t, err)
+	g.Set(3)
+	g.Set(2)
+
+	expected := `
+# HELP archivyr_queued_writes Writes waiting to be replayed.
+# TYPE archivyr_queued_writes gauge
+archivyr_queued_writes 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "archivyr_queued_writes"))
+
+	// Names must be unique per registry
+	_, err = backend.Gauge("queued_writes", "Again.")
+	assert.Error(t, err)
+}
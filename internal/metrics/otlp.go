@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// OTLPBackend records instruments with the OpenTelemetry SDK and pushes them
+// to a collector over OTLP/HTTP. Instruments are named with dots, e.g.
+// archivyr.search_results.
+type OTLPBackend struct {
+	provider *sdkmetric.MeterProvider
+	meter    metric.Meter
+}
+
+// NewOTLPBackend creates a backend exporting periodically over OTLP/HTTP.
+// Without options the exporter follows the standard OTEL_EXPORTER_OTLP_*
+// environment variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT, and pushes every
+// 60 seconds, or every OTEL_METRIC_EXPORT_INTERVAL milliseconds.
+func NewOTLPBackend(ctx context.Context, opts ...otlpmetrichttp.Option) (*OTLPBackend, error) {
+	exporter, err := otlpmetrichttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return newOTelBackend(sdkmetric.NewPeriodicReader(exporter)), nil
+}
+
+// newOTelBackend creates a backend whose measurements are collected by reader
+func newOTelBackend(reader sdkmetric.Reader) *OTLPBackend {
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(resource.NewSchemaless(attribute.String("service.name", namespace))),
+	)
+	return &OTLPBackend{provider: provider, meter: provider.Meter("github.com/jbrinkman/archivyr")}
+}
+
+// Shutdown pushes the measurements not exported yet and stops exporting
+func (b *OTLPBackend) Shutdown(ctx context.Context) error {
+	return b.provider.Shutdown(ctx)
+}
+
+// otelName returns the OpenTelemetry name of an instrument
+func otelName(name string) string {
+	return namespace + "." + name
+}
+
+func (b *OTLPBackend) Counter(name, help string) (Counter, error) {
+	c, err := b.meter.Float64Counter(otelName(name), metric.WithDescription(help))
+	if err != nil {
+		return nil, err
+	}
+	return otelCounter{c}, nil
+}
+
+func (b *OTLPBackend) Histogram(name, help string, buckets []float64) (Histogram, error) {
+	h, err := b.meter.Float64Histogram(otelName(name), metric.WithDescription(help), metric.WithExplicitBucketBoundaries(buckets...))
+	if err != nil {
+		return nil, err
+	}
+	return otelHistogram{h}, nil
+}
+
+func (b *OTLPBackend) Gauge(name, help string) (Gauge, error) {
+	g, err := b.meter.Float64Gauge(otelName(name), metric.WithDescription(help))
+	if err != nil {
+		return nil, err
+	}
+	return otelGauge{g}, nil
+}
+
+// otelCounter adapts an OpenTelemetry counter to Counter
+type otelCounter struct{ c metric.Float64Counter }
+
+func (c otelCounter) Add(delta float64) { c.c.Add(context.Background(), delta) }
+
+// otelHistogram adapts an OpenTelemetry histogram to Histogram
+type otelHistogram struct{ h metric.Float64Histogram }
+
+func (h otelHistogram) Observe(value float64) { h.h.Record(context.Background(), value) }
+
+// otelGauge adapts an OpenTelemetry gauge to Gauge
+type otelGauge struct{ g metric.Float64Gauge }
+
+func (g otelGauge) Set(value float64) { g.g.Record(context.Background(), value) }
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOTLPBackend_Instruments(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	backend := newOTelBackend(reader)
+
+	m, err := NewWithBackend(backend)
+	require.NoError(t, err)
+	m.ObserveSearchResults(3)
+	m.ObserveSearchResults(40)
+	m.ObserveLegacyURI()
+	g, err := backend.Gauge("queued_writes", "Writes waiting to be replayed.")
+	require.NoError(t, err)
+	g.Set(4)
+
+	var collected metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &collected))
+	require.Len(t, collected.ScopeMetrics, 1)
+
+	byName := make(map[string]metricdata.Metrics)
+	for _, metric := range collected.ScopeMetrics[0].Metrics {
+		byName[metric.Name] = metric
+	}
+
+	results := byName["archivyr.search_results"].Data.(metricdata.Histogram[float64])
+	require.Len(t, results.DataPoints, 1)
+	assert.Equal(t, uint64(2), results.DataPoints[0].Count)
+	assert.Equal(t, 43.0, results.DataPoints[0].Sum)
+	assert.Equal(t, []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}, results.DataPoints[0].Bounds)
+
+	legacy := byName["archivyr.legacy_uri_reads"].Data.(metricdata.Sum[float64])
+	assert.True(t, legacy.IsMonotonic)
+	assert.Equal(t, 1.0, legacy.DataPoints[0].Value)
+
+	queued := byName["archivyr.queued_writes"].Data.(metricdata.Gauge[float64])
+	assert.Equal(t, 4.0, queued.DataPoints[0].Value)
+	assert.Equal(t, "Writes waiting to be replayed.", byName["archivyr.queued_writes"].Description)
+}
+
+func TestOTLPBackend_ExportsOnShutdown(t *testing.T) {
+	var requests atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/metrics" && r.Header.Get("Content-Type") == "application/x-protobuf" {
+			requests.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	backend, err := NewOTLPBackend(context.Background(), otlpmetrichttp.WithEndpointURL(collector.URL+"/v1/metrics"))
+	require.NoError(t, err)
+	m, err := NewWithBackend(backend)
+	require.NoError(t, err)
+	m.ObserveMarkdownSize(1024)
+
+	require.NoError(t, backend.Shutdown(context.Background()))
+	assert.Equal(t, int32(1), requests.Load())
+}
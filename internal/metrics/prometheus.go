@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// prometheusBackend registers instruments as Prometheus collectors
+type prometheusBackend struct {
+	reg prometheus.Registerer
+}
+
+// NewPrometheusBackend returns a backend registering its instruments with reg
+func NewPrometheusBackend(reg prometheus.Registerer) Backend {
+	return prometheusBackend{reg: reg}
+}
+
+func (b prometheusBackend) Counter(name, help string) (Counter, error) {
+	c := prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Name: name + "_total", Help: help})
+	if err := b.reg.Register(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (b prometheusBackend) Histogram(name, help string, buckets []float64) (Histogram, error) {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Namespace: namespace, Name: name, Help: help, Buckets: buckets})
+	if err := b.reg.Register(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (b prometheusBackend) Gauge(name, help string) (Gauge, error) {
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help})
+	if err := b.reg.Register(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
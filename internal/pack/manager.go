@@ -0,0 +1,247 @@
+package pack
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+)
+
+// Installer installs signed pack bundles
+type Installer interface {
+	Install(data []byte, opts InstallOptions) (*Result, error)
+}
+
+// InstallOptions controls pack installation
+type InstallOptions struct {
+	// Force replaces rulesets that were not installed from the pack, such as
+	// locally authored rulesets or rulesets imported from another source
+	Force bool
+}
+
+// Selection names the entities to export. A nil list exports every entity of that kind.
+type Selection struct {
+	Rulesets  []string
+	Templates []string
+	Snippets  []string
+}
+
+// Result summarises an installed pack
+type Result struct {
+	Name      string
+	Version   string
+	Rulesets  int
+	Templates int
+	Snippets  int
+	// Conflicts lists the rulesets left untouched because they were not
+	// installed from the pack; installing with force replaces them
+	Conflicts []Conflict
+}
+
+// Conflict is an existing ruleset a pack would replace although it was not installed from it
+type Conflict struct {
+	Name string
+	// SourceURL is the ruleset's current source, empty for locally authored rulesets
+	SourceURL string
+}
+
+// String describes the conflict, e.g. "go_style (locally authored)"
+func (c Conflict) String() string {
+	if c.SourceURL == "" {
+		return fmt.Sprintf("%s (locally authored)", c.Name)
+	}
+	return fmt.Sprintf("%s (imported from %s)", c.Name, c.SourceURL)
+}
+
+// Manager exports and installs packs using the entity services.
+// The prompt and snippet services are optional.
+type Manager struct {
+	rulesets ruleset.ServiceInterface
+	prompts  prompt.ServiceInterface
+	snippets snippet.ServiceInterface
+	trusted  []ed25519.PublicKey
+}
+
+// NewManager creates a pack manager that only installs bundles signed by one of the trusted keys
+func NewManager(rulesets ruleset.ServiceInterface, prompts prompt.ServiceInterface, snippets snippet.ServiceInterface, trusted []ed25519.PublicKey) *Manager {
+	return &Manager{
+		rulesets: rulesets,
+		prompts:  prompts,
+		snippets: snippets,
+		trusted:  trusted,
+	}
+}
+
+// Export assembles a pack from the selected entities
+func (m *Manager) Export(name, version, description string, sel Selection) (*Pack, error) {
+	p := &Pack{
+		Name:        name,
+		Version:     version,
+		Description: description,
+		Rulesets:    []*ruleset.Ruleset{},
+		Templates:   []*prompt.Template{},
+		Snippets:    []*snippet.Snippet{},
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	if sel.Rulesets == nil {
+		rulesets, err := m.rulesets.List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rulesets: %w", err)
+		}
+		p.Rulesets = rulesets
+	} else {
+		for _, n := range sel.Rulesets {
+			rs, err := m.rulesets.Get(n)
+			if err != nil {
+				return nil, fmt.Errorf("failed to export ruleset: %w", err)
+			}
+			p.Rulesets = append(p.Rulesets, rs)
+		}
+	}
+
+	if m.prompts != nil {
+		if sel.Templates == nil {
+			templates, err := m.prompts.List()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+			}
+			p.Templates = templates
+		} else {
+			for _, n := range sel.Templates {
+				t, err := m.prompts.Get(n)
+				if err != nil {
+					return nil, fmt.Errorf("failed to export prompt template: %w", err)
+				}
+				p.Templates = append(p.Templates, t)
+			}
+		}
+	}
+
+	if m.snippets != nil {
+		if sel.Snippets == nil {
+			snippets, err := m.snippets.List("")
+			if err != nil {
+				return nil, fmt.Errorf("failed to list snippets: %w", err)
+			}
+			p.Snippets = snippets
+		} else {
+			for _, n := range sel.Snippets {
+				sn, err := m.snippets.Get(n)
+				if err != nil {
+					return nil, fmt.Errorf("failed to export snippet: %w", err)
+				}
+				p.Snippets = append(p.Snippets, sn)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// Install verifies a bundle and creates or replaces every entity it contains.
+// Rulesets are only replaced when they were installed from any version of the
+// same pack, unless opts.Force is set; the others are reported as conflicts.
+// Installation stops at the first failure; entities installed before it are kept.
+func (m *Manager) Install(data []byte, opts InstallOptions) (*Result, error) {
+	p, err := Open(data, m.trusted)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Templates) > 0 && m.prompts == nil {
+		return nil, fmt.Errorf("pack contains prompt templates but prompt templates are not enabled")
+	}
+	if len(p.Snippets) > 0 && m.snippets == nil {
+		return nil, fmt.Errorf("pack contains snippets but snippets are not enabled")
+	}
+
+	result := &Result{Name: p.Name, Version: p.Version}
+
+	// Snippets first so rulesets that include them resolve immediately
+	for _, sn := range p.Snippets {
+		if _, err := m.snippets.Save(sn); err != nil {
+			return result, fmt.Errorf("failed to install snippet '%s': %w", sn.Name, err)
+		}
+		result.Snippets++
+	}
+
+	// Large packs leave index maintenance to a background rebuild when the
+	// ruleset service supports it
+	if bulk, ok := m.rulesets.(ruleset.BulkImporter); ok {
+		end := bulk.BeginBulkImport()
+		defer end()
+	}
+
+	for _, rs := range p.Rulesets {
+		if !opts.Force {
+			conflict, err := m.conflict(p, rs.Name)
+			if err != nil {
+				return result, fmt.Errorf("failed to install ruleset '%s': %w", rs.Name, err)
+			}
+			if conflict != nil {
+				result.Conflicts = append(result.Conflicts, *conflict)
+				continue
+			}
+		}
+
+		// Record the pack as provenance so local edits require force
+		rs.SourceURL = p.SourceURL()
+		if _, err := m.rulesets.Upsert(rs, fullUpdate(rs)); err != nil {
+			return result, fmt.Errorf("failed to install ruleset '%s': %w", rs.Name, err)
+		}
+		result.Rulesets++
+	}
+
+	for _, t := range p.Templates {
+		if _, err := m.prompts.Save(t); err != nil {
+			return result, fmt.Errorf("failed to install prompt template '%s': %w", t.Name, err)
+		}
+		result.Templates++
+	}
+
+	return result, nil
+}
+
+// conflict reports the ruleset named name when it exists but was not installed from p
+func (m *Manager) conflict(p *Pack, name string) (*Conflict, error) {
+	exists, err := m.rulesets.Exists(name)
+	if err != nil || !exists {
+		return nil, err
+	}
+	current, err := m.rulesets.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(current.SourceURL, fmt.Sprintf("pack:%s@", p.Name)) {
+		return nil, nil
+	}
+	return &Conflict{Name: name, SourceURL: current.SourceURL}, nil
+}
+
+// fullUpdate returns an update replacing every mutable field of an existing ruleset
+// with rs. It forces the update, since Install has checked the ruleset may be replaced.
+func fullUpdate(rs *ruleset.Ruleset) *ruleset.Update {
+	contentType := rs.ContentType
+	if contentType == "" {
+		contentType = ruleset.ContentTypeMarkdown
+	}
+	tags := rs.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &ruleset.Update{
+		Description: &rs.Description,
+		Tags:        &tags,
+		ContentType: &contentType,
+		License:     &rs.License,
+		Markdown:    &rs.Markdown,
+		SourceURL:   &rs.SourceURL,
+		Force:       true,
+	}
+}
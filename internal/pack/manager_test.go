@@ -0,0 +1,175 @@
+package pack
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryManager creates a manager whose services share one in-memory store
+func newMemoryManager(trusted ...ed25519.PublicKey) *Manager {
+	store := memstore.New()
+	return NewManager(ruleset.NewService(store), prompt.NewService(store), snippet.NewService(store), trusted)
+}
+
+func TestManager_ExportAndInstall(t *testing.T) {
+	public, private := newKey(1)
+
+	source := newMemoryManager()
+	require.NoError(t, source.rulesets.Create(&ruleset.Ruleset{
+		Name:        "go_style",
+		Description: "Go style",
+		Tags:        []string{"go"},
+		License:     "MIT",
+		Markdown:    "# Go\n{{snippet:no_tabs}}",
+	}))
+	require.NoError(t, source.rulesets.Create(&ruleset.Ruleset{
+		Name:        "internal_notes",
+		Description: "Not exported",
+		Markdown:    "# Internal",
+	}))
+	_, err := source.snippets.Save(&snippet.Snippet{Name: "no_tabs", Text: "Use gofmt."})
+	require.NoError(t, err)
+	_, err = source.prompts.Save(&prompt.Template{Name: "review", Template: "Review {{code}}", Variables: []prompt.Variable{{Name: "code"}}})
+	require.NoError(t, err)
+
+	p, err := source.Export("go_team", "1.2.0", "Go team rules", Selection{Rulesets: []string{"go_style"}})
+	require.NoError(t, err)
+	assert.Len(t, p.Rulesets, 1)
+	assert.Len(t, p.Templates, 1)
+	assert.Len(t, p.Snippets, 1)
+
+	bundle, err := Sign(p, private)
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	target := newMemoryManager(public)
+	result, err := target.Install(data, InstallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Name: "go_team", Version: "1.2.0", Rulesets: 1, Templates: 1, Snippets: 1}, result)
+
+	rs, err := target.rulesets.Get("go_style")
+	require.NoError(t, err)
+	assert.Equal(t, "Go style", rs.Description)
+	assert.Equal(t, []string{"go"}, rs.Tags)
+	assert.Equal(t, "MIT", rs.License)
+	assert.Equal(t, "pack:go_team@1.2.0", rs.SourceURL)
+	assert.False(t, rs.ImportedAt.IsZero())
+
+	exists, err := target.rulesets.Exists("internal_notes")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Installing again replaces existing entities
+	result, err = target.Install(data, InstallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Rulesets)
+}
+
+func TestManager_InstallConflicts(t *testing.T) {
+	public, private := newKey(1)
+	install := func(m *Manager, version string, opts InstallOptions) *Result {
+		bundle, err := Sign(&Pack{
+			Name:    "go_team",
+			Version: version,
+			Rulesets: []*ruleset.Ruleset{
+				{Name: "go_style", Description: "Go style", Markdown: "# Go " + version},
+				{Name: "go_local", Description: "Go", Markdown: "# Pack"},
+				{Name: "go_vendored", Description: "Go", Markdown: "# Pack"},
+			},
+		}, private)
+		require.NoError(t, err)
+		data, err := json.Marshal(bundle)
+		require.NoError(t, err)
+		result, err := m.Install(data, opts)
+		require.NoError(t, err)
+		return result
+	}
+
+	target := newMemoryManager(public)
+	require.NoError(t, target.rulesets.Create(&ruleset.Ruleset{Name: "go_local", Description: "Local", Markdown: "# Local"}))
+	require.NoError(t, target.rulesets.Create(&ruleset.Ruleset{Name: "go_vendored", Description: "Vendored", Markdown: "# Other", SourceURL: "pack:other_team@1.0.0"}))
+
+	// Rulesets not installed from the pack are reported and left untouched
+	result := install(target, "1.0.0", InstallOptions{})
+	assert.Equal(t, 1, result.Rulesets)
+	assert.Equal(t, []Conflict{{Name: "go_local"}, {Name: "go_vendored", SourceURL: "pack:other_team@1.0.0"}}, result.Conflicts)
+	local, err := target.rulesets.Get("go_local")
+	require.NoError(t, err)
+	assert.Equal(t, "# Local", local.Markdown)
+	assert.Empty(t, local.SourceURL)
+
+	// A later version of the pack replaces the rulesets it installed
+	result = install(target, "1.1.0", InstallOptions{})
+	assert.Equal(t, 1, result.Rulesets)
+	assert.Len(t, result.Conflicts, 2)
+	rs, err := target.rulesets.Get("go_style")
+	require.NoError(t, err)
+	assert.Equal(t, "# Go 1.1.0", rs.Markdown)
+	assert.Equal(t, "pack:go_team@1.1.0", rs.SourceURL)
+
+	// Force replaces them all
+	result = install(target, "1.1.0", InstallOptions{Force: true})
+	assert.Equal(t, 3, result.Rulesets)
+	assert.Empty(t, result.Conflicts)
+	vendored, err := target.rulesets.Get("go_vendored")
+	require.NoError(t, err)
+	assert.Equal(t, "# Pack", vendored.Markdown)
+	assert.Equal(t, "pack:go_team@1.1.0", vendored.SourceURL)
+}
+
+func TestManager_ExportMissingEntity(t *testing.T) {
+	_, err := newMemoryManager().Export("go_team", "1.0.0", "", Selection{Rulesets: []string{"missing"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestManager_InstallRequiresEnabledServices(t *testing.T) {
+	public, private := newKey(1)
+	bundle, err := Sign(&Pack{
+		Name:     "snippets_only",
+		Version:  "1.0.0",
+		Snippets: []*snippet.Snippet{{Name: "no_tabs", Text: "Use gofmt."}},
+	}, private)
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	manager := NewManager(ruleset.NewService(memstore.New()), nil, nil, []ed25519.PublicKey{public})
+	_, err = manager.Install(data, InstallOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "snippets are not enabled")
+}
+
+func TestManager_InstallDefersIndexing(t *testing.T) {
+	public, private := newKey(1)
+	bundle, err := Sign(&Pack{
+		Name:     "go_team",
+		Version:  "1.0.0",
+		Rulesets: []*ruleset.Ruleset{{Name: "go_style", Description: "Go style", Markdown: "# Go"}, {Name: "go_testing", Description: "Go tests", Markdown: "# Testing"}},
+	}, private)
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	rulesets := ruleset.NewService(memstore.New(), ruleset.WithBackgroundReindex(0))
+	manager := NewManager(rulesets, nil, nil, []ed25519.PublicKey{public})
+	_, err = manager.Install(data, InstallOptions{})
+	require.NoError(t, err)
+
+	// The install ends by scheduling the background rebuild
+	assert.Equal(t, 0, rulesets.IndexStatus().DeferredImports)
+	assert.Eventually(t, func() bool {
+		status := rulesets.IndexStatus()
+		return !status.Running && status.Indexed == 2
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,144 @@
+// Package pack bundles rulesets, prompt templates and snippets into signed,
+// versioned knowledge packs that can be distributed and installed elsewhere.
+//
+// Collections are not modelled by the server yet and are therefore not part of a pack.
+package pack
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/prompt"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/snippet"
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// Pack is a versioned set of entities distributed together
+type Pack struct {
+	Name        string             `json:"name"`
+	Version     string             `json:"version"`
+	Description string             `json:"description,omitempty"`
+	Rulesets    []*ruleset.Ruleset `json:"rulesets"`
+	Templates   []*prompt.Template `json:"templates"`
+	Snippets    []*snippet.Snippet `json:"snippets"`
+}
+
+// Bundle is the distributable form of a pack. The signature covers the exact
+// bytes of Pack so verification does not depend on JSON canonicalisation.
+type Bundle struct {
+	Pack      json.RawMessage `json:"pack"`
+	Signature string          `json:"signature"`
+}
+
+// Validate checks that the pack has a valid name and a version
+func (p *Pack) Validate() error {
+	if err := validation.ValidateName("pack", p.Name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(p.Version) == "" {
+		return fmt.Errorf("pack version cannot be empty")
+	}
+	return nil
+}
+
+// SourceURL identifies the pack version as the provenance of installed rulesets
+func (p *Pack) SourceURL() string {
+	return fmt.Sprintf("pack:%s@%s", p.Name, p.Version)
+}
+
+// Sign encodes the pack and signs it with key
+func Sign(p *Pack, key ed25519.PrivateKey) (*Bundle, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pack: %w", err)
+	}
+
+	return &Bundle{
+		Pack:      data,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)),
+	}, nil
+}
+
+// Open decodes a bundle, verifies its signature against the trusted keys and returns the pack
+func Open(data []byte, trusted []ed25519.PublicKey) (*Pack, error) {
+	if len(trusted) == 0 {
+		return nil, fmt.Errorf("no trusted pack keys configured")
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(bundle.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	verified := false
+	for _, key := range trusted {
+		if ed25519.Verify(key, bundle.Pack, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("pack signature does not match any trusted key")
+	}
+
+	var p Pack
+	if err := json.Unmarshal(bundle.Pack, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode pack: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// ParsePublicKeys parses a comma-separated list of base64-encoded ed25519 public keys
+func ParsePublicKeys(s string) ([]ed25519.PublicKey, error) {
+	keys := make([]ed25519.PublicKey, 0)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode public key: %w", err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("public key must be %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	return keys, nil
+}
+
+// ParsePrivateKey parses a base64-encoded ed25519 private key or seed
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("private key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
@@ -0,0 +1,112 @@
+package pack
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKey returns a deterministic key pair for tests
+func newKey(seed byte) (ed25519.PublicKey, ed25519.PrivateKey) {
+	seedBytes := make([]byte, ed25519.SeedSize)
+	seedBytes[0] = seed
+	private := ed25519.NewKeyFromSeed(seedBytes)
+	return private.Public().(ed25519.PublicKey), private
+}
+
+func TestSignAndOpen(t *testing.T) {
+	public, private := newKey(1)
+	otherPublic, _ := newKey(2)
+
+	p := &Pack{
+		Name:     "go_team",
+		Version:  "1.0.0",
+		Rulesets: []*ruleset.Ruleset{{Name: "go_style", Description: "Go style", Markdown: "# Go"}},
+	}
+
+	bundle, err := Sign(p, private)
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	t.Run("trusted key", func(t *testing.T) {
+		opened, err := Open(data, []ed25519.PublicKey{otherPublic, public})
+		require.NoError(t, err)
+		assert.Equal(t, "go_team", opened.Name)
+		assert.Equal(t, "1.0.0", opened.Version)
+		require.Len(t, opened.Rulesets, 1)
+		assert.Equal(t, "# Go", opened.Rulesets[0].Markdown)
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		_, err := Open(data, []ed25519.PublicKey{otherPublic})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match any trusted key")
+	})
+
+	t.Run("no trusted keys", func(t *testing.T) {
+		_, err := Open(data, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no trusted pack keys configured")
+	})
+
+	t.Run("tampered pack", func(t *testing.T) {
+		tampered := *bundle
+		tampered.Pack = json.RawMessage(`{"name":"go_team","version":"1.0.1"}`)
+		tamperedData, err := json.Marshal(tampered)
+		require.NoError(t, err)
+
+		_, err = Open(tamperedData, []ed25519.PublicKey{public})
+		require.Error(t, err)
+	})
+}
+
+func TestPack_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pack    Pack
+		wantErr bool
+	}{
+		{"valid", Pack{Name: "go_team", Version: "1.0.0"}, false},
+		{"invalid name", Pack{Name: "Go Team", Version: "1.0.0"}, true},
+		{"missing version", Pack{Name: "go_team"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pack.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	public, private := newKey(1)
+
+	keys, err := ParsePublicKeys(" " + base64.StdEncoding.EncodeToString(public) + ", ")
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{public}, keys)
+
+	_, err = ParsePublicKeys("c2hvcnQ=")
+	assert.Error(t, err)
+
+	parsed, err := ParsePrivateKey(base64.StdEncoding.EncodeToString(private.Seed()))
+	require.NoError(t, err)
+	assert.Equal(t, private, parsed)
+
+	parsed, err = ParsePrivateKey(base64.StdEncoding.EncodeToString(private))
+	require.NoError(t, err)
+	assert.Equal(t, private, parsed)
+
+	_, err = ParsePrivateKey("not base64!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package pack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxBundleBytes caps the size of a bundle downloaded from a registry
+const maxBundleBytes = 32 << 20
+
+// Entry describes a pack version published in a registry
+type Entry struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	// URL locates the bundle; relative URLs are resolved against the registry base URL
+	URL string `json:"url"`
+	// SHA256 is the hex-encoded checksum of the bundle
+	SHA256 string `json:"sha256"`
+}
+
+// RegistryClient searches and downloads published packs
+type RegistryClient interface {
+	Search(query string) ([]Entry, error)
+	Fetch(name, version string) ([]byte, error)
+}
+
+// Registry is an HTTPS client for a pack registry exposing
+// GET /packs?q={query} and GET /packs/{name}/{version}
+type Registry struct {
+	baseURL *url.URL
+	client  *http.Client
+	ctx     context.Context
+}
+
+// RegistryOption configures a Registry
+type RegistryOption func(*Registry)
+
+// WithHTTPClient sets the HTTP client used for registry requests
+func WithHTTPClient(client *http.Client) RegistryOption {
+	return func(r *Registry) {
+		r.client = client
+	}
+}
+
+// NewRegistry creates a registry client for the given HTTPS base URL
+func NewRegistry(baseURL string, opts ...RegistryOption) (*Registry, error) {
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL: %w", err)
+	}
+	if base.Scheme != "https" {
+		return nil, fmt.Errorf("registry URL must use https: %s", baseURL)
+	}
+
+	r := &Registry{
+		baseURL: base,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		ctx:     context.Background(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Search returns the published packs matching query
+func (r *Registry) Search(query string) ([]Entry, error) {
+	endpoint := r.baseURL.ResolveReference(&url.URL{Path: "packs", RawQuery: url.Values{"q": {query}}.Encode()})
+
+	body, err := r.get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search registry: %w", err)
+	}
+
+	var response struct {
+		Packs []Entry `json:"packs"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode registry response: %w", err)
+	}
+	return response.Packs, nil
+}
+
+// Fetch downloads the bundle of a pack version and verifies its checksum.
+// An empty version selects the latest published version.
+func (r *Registry) Fetch(name, version string) ([]byte, error) {
+	if version == "" {
+		version = "latest"
+	}
+
+	endpoint := r.baseURL.ResolveReference(&url.URL{Path: "packs/" + url.PathEscape(name) + "/" + url.PathEscape(version)})
+	body, err := r.get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up pack '%s': %w", name, err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode registry response: %w", err)
+	}
+
+	bundleURL, err := r.baseURL.Parse(entry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle URL: %w", err)
+	}
+	if bundleURL.Scheme != "https" {
+		return nil, fmt.Errorf("bundle URL must use https: %s", bundleURL)
+	}
+
+	bundle, err := r.get(bundleURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download pack '%s': %w", name, err)
+	}
+
+	sum := sha256.Sum256(bundle)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), entry.SHA256) {
+		return nil, fmt.Errorf("checksum mismatch for pack '%s' version %s", name, entry.Version)
+	}
+
+	return bundle, nil
+}
+
+// get performs a GET request and returns the response body
+func (r *Registry) get(u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBundleBytes {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxBundleBytes)
+	}
+	return body, nil
+}
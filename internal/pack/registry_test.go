@@ -0,0 +1,97 @@
+package pack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry serves a registry publishing one pack whose checksum is given by sum
+func newTestRegistry(t *testing.T, bundle []byte, sum string) *Registry {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /packs", func(w http.ResponseWriter, r *http.Request) {
+		packs := []Entry{}
+		if r.URL.Query().Get("q") == "go" {
+			packs = append(packs, Entry{Name: "go_team", Version: "1.0.0", Description: "Go rules"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"packs": packs})
+	})
+	mux.HandleFunc("GET /packs/go_team/{version}", func(w http.ResponseWriter, r *http.Request) {
+		if v := r.PathValue("version"); v != "latest" && v != "1.0.0" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Entry{Name: "go_team", Version: "1.0.0", URL: "/bundles/go_team-1.0.0.json", SHA256: sum})
+	})
+	mux.HandleFunc("GET /bundles/go_team-1.0.0.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(bundle)
+	})
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	registry, err := NewRegistry(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	return registry
+}
+
+func TestRegistry_Search(t *testing.T) {
+	registry := newTestRegistry(t, nil, "")
+
+	entries, err := registry.Search("go")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "go_team", entries[0].Name)
+
+	entries, err = registry.Search("rust")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRegistry_Fetch(t *testing.T) {
+	bundle := []byte(`{"pack":{},"signature":""}`)
+	sum := sha256.Sum256(bundle)
+
+	t.Run("verifies checksum", func(t *testing.T) {
+		registry := newTestRegistry(t, bundle, hex.EncodeToString(sum[:]))
+
+		data, err := registry.Fetch("go_team", "")
+		require.NoError(t, err)
+		assert.Equal(t, bundle, data)
+
+		data, err = registry.Fetch("go_team", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, bundle, data)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		registry := newTestRegistry(t, bundle, "00")
+
+		_, err := registry.Fetch("go_team", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch")
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		registry := newTestRegistry(t, bundle, hex.EncodeToString(sum[:]))
+
+		_, err := registry.Fetch("go_team", "9.9.9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+}
+
+func TestNewRegistry_RequiresHTTPS(t *testing.T) {
+	_, err := NewRegistry("http://registry.example.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must use https")
+
+	_, err = NewRegistry("https://registry.example.com/api")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,9 @@
+package prompt
+
+// ServiceInterface defines the interface for prompt template operations
+type ServiceInterface interface {
+	Save(t *Template) (created bool, err error)
+	Get(name string) (*Template, error)
+	Delete(name string) error
+	List() ([]*Template, error)
+}
@@ -0,0 +1,155 @@
+package prompt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// KeyPrefix is the Valkey key prefix used for prompt template hashes
+const KeyPrefix = "prompt:"
+
+// Service provides business logic for prompt template management
+type Service struct {
+	storage ruleset.Storage
+	ctx     context.Context
+	clock   func() time.Time
+}
+
+// NewService creates a new prompt template service backed by storage
+func NewService(storage ruleset.Storage) *Service {
+	return &Service{
+		storage: storage,
+		ctx:     context.Background(),
+		clock:   time.Now,
+	}
+}
+
+// Save creates or fully replaces a prompt template, preserving its creation time.
+// It reports whether the template was newly created.
+func (s *Service) Save(t *Template) (bool, error) {
+	if err := validation.ValidateName("prompt template", t.Name); err != nil {
+		return false, err
+	}
+	if err := t.Validate(); err != nil {
+		return false, err
+	}
+
+	key := KeyPrefix + t.Name
+	existing, err := s.storage.HGetAll(s.ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve prompt template: %w", err)
+	}
+
+	now := s.clock()
+	created := len(existing) == 0
+	t.CreatedAt = now
+	if !created {
+		if createdAt, err := validation.ParseTimestamp(existing["created_at"]); err == nil {
+			t.CreatedAt = createdAt
+		}
+	}
+	t.LastModified = now
+
+	if t.Variables == nil {
+		t.Variables = []Variable{}
+	}
+	variablesJSON, err := json.Marshal(t.Variables)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode variables: %w", err)
+	}
+
+	fields := map[string]string{
+		"description":   t.Description,
+		"variables":     string(variablesJSON),
+		"template":      t.Template,
+		"created_at":    validation.FormatTimestamp(t.CreatedAt),
+		"last_modified": validation.FormatTimestamp(t.LastModified),
+	}
+	if err := s.storage.HSet(s.ctx, key, fields); err != nil {
+		return false, fmt.Errorf("failed to save prompt template: %w", err)
+	}
+
+	return created, nil
+}
+
+// Get retrieves a prompt template by exact name
+func (s *Service) Get(name string) (*Template, error) {
+	if err := validation.ValidateName("prompt template", name); err != nil {
+		return nil, err
+	}
+
+	result, err := s.storage.HGetAll(s.ctx, KeyPrefix+name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve prompt template: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("prompt template '%s' not found", name)
+	}
+
+	t := &Template{
+		Name:        name,
+		Description: result["description"],
+		Template:    result["template"],
+	}
+
+	if err := json.Unmarshal([]byte(result["variables"]), &t.Variables); err != nil {
+		return nil, fmt.Errorf("failed to parse variables: %w", err)
+	}
+	if t.CreatedAt, err = validation.ParseTimestamp(result["created_at"]); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if t.LastModified, err = validation.ParseTimestamp(result["last_modified"]); err != nil {
+		return nil, fmt.Errorf("failed to parse last_modified: %w", err)
+	}
+
+	return t, nil
+}
+
+// Delete removes a prompt template by name
+func (s *Service) Delete(name string) error {
+	if err := validation.ValidateName("prompt template", name); err != nil {
+		return err
+	}
+
+	key := KeyPrefix + name
+	exists, err := s.storage.Exists(s.ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check if prompt template exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("prompt template '%s' not found", name)
+	}
+
+	if err := s.storage.Del(s.ctx, key); err != nil {
+		return fmt.Errorf("failed to delete prompt template: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all prompt templates sorted by name
+func (s *Service) List() ([]*Template, error) {
+	keys, err := s.storage.ScanKeys(s.ctx, KeyPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan prompt template keys: %w", err)
+	}
+
+	templates := make([]*Template, 0, len(keys))
+	for _, key := range keys {
+		t, err := s.Get(strings.TrimPrefix(key, KeyPrefix))
+		if err != nil {
+			// Skip templates that vanished or cannot be decoded
+			continue
+		}
+		templates = append(templates, t)
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
@@ -0,0 +1,71 @@
+package prompt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CRUD(t *testing.T) {
+	service := NewService(memstore.New())
+	first := time.Date(2025, 10, 29, 10, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return first }
+
+	tmpl := &Template{
+		Name:        "code_review",
+		Description: "Code review prompt",
+		Variables:   []Variable{{Name: "language", Required: true}},
+		Template:    "Review this {{language}} code",
+	}
+
+	created, err := service.Save(tmpl)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	retrieved, err := service.Get("code_review")
+	require.NoError(t, err)
+	assert.Equal(t, "Code review prompt", retrieved.Description)
+	assert.Equal(t, []Variable{{Name: "language", Required: true}}, retrieved.Variables)
+	assert.Equal(t, first, retrieved.CreatedAt)
+
+	// Saving again replaces the template but keeps the creation time
+	later := first.Add(time.Hour)
+	service.clock = func() time.Time { return later }
+	tmpl.Description = "Updated"
+	created, err = service.Save(tmpl)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	retrieved, err = service.Get("code_review")
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", retrieved.Description)
+	assert.Equal(t, first, retrieved.CreatedAt)
+	assert.Equal(t, later, retrieved.LastModified)
+
+	templates, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+
+	require.NoError(t, service.Delete("code_review"))
+	_, err = service.Get("code_review")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	err = service.Delete("code_review")
+	require.Error(t, err)
+}
+
+func TestService_SaveValidation(t *testing.T) {
+	service := NewService(memstore.New())
+
+	_, err := service.Save(&Template{Name: "Bad-Name", Template: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prompt template name must be in snake_case")
+
+	_, err = service.Save(&Template{Name: "missing_var", Template: "{{x}}"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undeclared variable")
+}
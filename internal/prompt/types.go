@@ -0,0 +1,77 @@
+// Package prompt provides storage and rendering of reusable prompt templates.
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Variable declares a placeholder that can be filled when rendering a template
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// Template is a reusable prompt with declared variables
+type Template struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Variables    []Variable `json:"variables"`
+	Template     string     `json:"template"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastModified time.Time  `json:"last_modified"`
+}
+
+// variableNameRegex matches valid variable identifiers
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// placeholderRegex matches {{ variable }} placeholders in a template body
+var placeholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Validate checks that variables are well-formed and every placeholder is declared
+func (t *Template) Validate() error {
+	if t.Template == "" {
+		return fmt.Errorf("template body cannot be empty")
+	}
+
+	declared := make(map[string]bool, len(t.Variables))
+	for _, v := range t.Variables {
+		if !variableNameRegex.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name '%s': must start with a letter or underscore and contain only letters, digits and underscores", v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("variable '%s' is declared more than once", v.Name)
+		}
+		declared[v.Name] = true
+	}
+
+	for _, match := range placeholderRegex.FindAllStringSubmatch(t.Template, -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("template uses undeclared variable '%s'", match[1])
+		}
+	}
+
+	return nil
+}
+
+// Render substitutes args into the template. Missing required variables are an
+// error; missing optional variables render as empty strings.
+func (t *Template) Render(args map[string]string) (string, error) {
+	missing := make([]string, 0)
+	for _, v := range t.Variables {
+		if _, ok := args[v.Name]; !ok && v.Required {
+			missing = append(missing, v.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing required variables: %s", strings.Join(missing, ", "))
+	}
+
+	return placeholderRegex.ReplaceAllStringFunc(t.Template, func(placeholder string) string {
+		name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+		return args[name]
+	}), nil
+}
@@ -0,0 +1,72 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template Template
+		wantErr  string
+	}{
+		{
+			name:     "valid template",
+			template: Template{Template: "Review {{ language }} code", Variables: []Variable{{Name: "language"}}},
+		},
+		{
+			name:     "empty body",
+			template: Template{},
+			wantErr:  "template body cannot be empty",
+		},
+		{
+			name:     "undeclared variable",
+			template: Template{Template: "Hello {{name}}"},
+			wantErr:  "undeclared variable 'name'",
+		},
+		{
+			name:     "invalid variable name",
+			template: Template{Template: "x", Variables: []Variable{{Name: "bad-name"}}},
+			wantErr:  "invalid variable name 'bad-name'",
+		},
+		{
+			name:     "duplicate variable",
+			template: Template{Template: "x", Variables: []Variable{{Name: "a"}, {Name: "a"}}},
+			wantErr:  "declared more than once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.template.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl := &Template{
+		Template: "Review this {{language}} change for {{ focus }}.{{suffix}}",
+		Variables: []Variable{
+			{Name: "language", Required: true},
+			{Name: "focus", Required: true},
+			{Name: "suffix"},
+		},
+	}
+
+	out, err := tmpl.Render(map[string]string{"language": "Go", "focus": "error handling"})
+	require.NoError(t, err)
+	assert.Equal(t, "Review this Go change for error handling.", out)
+
+	_, err = tmpl.Render(map[string]string{"language": "Go"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required variables: focus")
+}
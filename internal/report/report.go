@@ -0,0 +1,78 @@
+// Package report renders spreadsheet-friendly summaries of the ruleset corpus.
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// Columns are the header row of a report
+var Columns = []string{"name", "description", "tags", "owner", "team", "content_type", "size_bytes", "version", "created_at", "last_modified", "source_url"}
+
+// Options controls how a report is written
+type Options struct {
+	// Comma separates fields; zero means ','
+	Comma rune
+	// BOM starts the output with a UTF-8 byte order mark, so Excel detects the encoding
+	BOM bool
+}
+
+// Write writes one row per ruleset in name order after the header row
+func Write(w io.Writer, rulesets []*ruleset.Ruleset, opts Options) error {
+	if opts.BOM {
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	out := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		out.Comma = opts.Comma
+	}
+
+	sorted := slices.Clone(rulesets)
+	slices.SortFunc(sorted, func(a, b *ruleset.Ruleset) int { return strings.Compare(a.Name, b.Name) })
+
+	if err := out.Write(Columns); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	for _, rs := range sorted {
+		row := []string{
+			rs.Name,
+			cell(rs.Description),
+			cell(strings.Join(rs.Tags, ", ")),
+			rs.Owner,
+			rs.Team,
+			rs.ContentType,
+			strconv.Itoa(len(rs.Markdown)),
+			strconv.FormatInt(rs.Version, 10),
+			validation.FormatTimestamp(rs.CreatedAt),
+			validation.FormatTimestamp(rs.LastModified),
+			cell(rs.SourceURL),
+		}
+		if err := out.Write(row); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// cell neutralises free text a spreadsheet would evaluate as a formula
+func cell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rulesets := []*ruleset.Ruleset{
+		{Name: "py_rules", Description: "=HYPERLINK(\"evil\")", Tags: []string{"python"}, ContentType: ruleset.ContentTypeMarkdown, Markdown: "# Py", Version: 1, CreatedAt: modified, LastModified: modified},
+		{Name: "go_rules", Description: "Go, idiomatic", Tags: []string{"go", "style"}, Owner: "alice@example.com", Team: "platform", ContentType: ruleset.ContentTypeMarkdown, Markdown: "# Go ✓", Version: 3, CreatedAt: modified, LastModified: modified},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, rulesets, Options{}))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, Columns, rows[0])
+	assert.Equal(t, []string{"go_rules", "Go, idiomatic", "go, style", "alice@example.com", "platform", "text/markdown", "8", "3", "2024-05-01T12:00:00Z", "2024-05-01T12:00:00Z", ""}, rows[1])
+
+	// Free text starting like a formula is escaped
+	assert.Equal(t, `'=HYPERLINK("evil")`, rows[2][1])
+}
+
+func TestWrite_ExcelOptions(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, nil, Options{Comma: ';', BOM: true}))
+
+	out, ok := strings.CutPrefix(buf.String(), "\ufeff")
+	require.True(t, ok)
+	assert.Equal(t, strings.Join(Columns, ";")+"\n", out)
+}
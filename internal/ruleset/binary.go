@@ -0,0 +1,65 @@
+package ruleset
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// binarySignatures maps the leading bytes of common binary formats to their names
+var binarySignatures = []struct {
+	prefix string
+	format string
+}{
+	{"\x89PNG\r\n\x1a\n", "PNG image"},
+	{"\xff\xd8\xff", "JPEG image"},
+	{"GIF87a", "GIF image"},
+	{"GIF89a", "GIF image"},
+	{"%PDF-", "PDF document"},
+	{"PK\x03\x04", "ZIP archive"},
+	{"\x1f\x8b", "gzip archive"},
+}
+
+// binarySampleSize bounds how much of the body is scanned for control characters
+const binarySampleSize = 8192
+
+// maxControlRatio is the share of control characters above which text is treated as binary
+const maxControlRatio = 0.05
+
+// binaryReason reports why body looks like binary data rather than text, or ""
+// when it is text. Tabs, newlines, carriage returns, form feeds and escape
+// sequences are allowed in moderation.
+func binaryReason(body string) string {
+	for _, sig := range binarySignatures {
+		if strings.HasPrefix(body, sig.prefix) {
+			return sig.format
+		}
+	}
+	if !utf8.ValidString(body) {
+		return "invalid UTF-8"
+	}
+	if strings.IndexByte(body, 0) >= 0 {
+		return "NUL bytes"
+	}
+
+	sample := body[:min(len(body), binarySampleSize)]
+	control, total := 0, 0
+	for _, r := range sample {
+		total++
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f' || r == 0x7f {
+			control++
+		}
+	}
+	if total > 0 && float64(control)/float64(total) > maxControlRatio {
+		return "control characters"
+	}
+	return ""
+}
+
+// validateText rejects bodies that look like binary data, which break every text render
+func validateText(body string) error {
+	if reason := binaryReason(body); reason != "" {
+		return fmt.Errorf("content looks like binary data (%s); only text can be stored, so reference binary files by URL instead", reason)
+	}
+	return nil
+}
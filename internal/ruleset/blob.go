@@ -0,0 +1,60 @@
+package ruleset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// blobKey returns the key of the content-addressed hash holding a body, e.g. blob:ruleset:<sha256>
+func (s *Service) blobKey(digest string) string {
+	return "blob:" + s.opts.KeyPrefix + digest
+}
+
+// blobDigest returns the hex SHA-256 addressing body
+func blobDigest(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// setBody stores body and sets the ruleset hash fields referencing it.
+// With blob storage the body is written once under its digest and the hash
+// only keeps the reference, so rulesets with equal bodies share one copy and
+// copying a ruleset never copies its body. Otherwise the body is stored inline;
+// on update any earlier reference is cleared so the inline body is read.
+func (s *Service) setBody(fields map[string]string, body string, update bool) error {
+	if !s.opts.BlobStorage {
+		fields["markdown"] = body
+		if update {
+			fields["blob"] = ""
+		}
+		return nil
+	}
+
+	digest := blobDigest(body)
+	// Writing the same content again is idempotent, so no existence check is needed.
+	// stored_at is refreshed on every write so garbage collection spares blobs
+	// whose referencing ruleset hash is still being written. It has nanosecond
+	// precision, so garbage collection can tell a refresh within the same second.
+	blob := map[string]string{"markdown": body, "stored_at": s.opts.Clock().UTC().Format(time.RFC3339Nano)}
+	if err := s.storage.HSet(s.ctx, s.blobKey(digest), blob); err != nil {
+		return fmt.Errorf("failed to store content blob: %w", err)
+	}
+	fields["markdown"] = ""
+	fields["blob"] = digest
+	return nil
+}
+
+// loadBlob reads the body stored under digest
+func (s *Service) loadBlob(digest string) (string, error) {
+	result, err := s.storage.HGetAll(s.ctx, s.blobKey(digest))
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve content blob: %w", err)
+	}
+	body, ok := result["markdown"]
+	if !ok {
+		return "", fmt.Errorf("content blob %s not found", digest)
+	}
+	return body, nil
+}
@@ -0,0 +1,155 @@
+package ruleset
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache stores recently read rulesets keyed by name
+type Cache interface {
+	Get(name string) (*Ruleset, bool)
+	Set(rs *Ruleset)
+	Delete(name string)
+	Clear()
+}
+
+// LRUCache is a fixed-capacity, concurrency-safe least-recently-used Cache
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// NewLRUCache creates an LRU cache holding at most capacity rulesets
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns a copy of the cached ruleset and marks it as recently used
+func (c *LRUCache) Get(name string) (*Ruleset, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[name]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return cloneRuleset(elem.Value.(*Ruleset)), true
+}
+
+// Set stores a copy of the ruleset, evicting the least recently used entry when full
+func (c *LRUCache) Set(rs *Ruleset) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[rs.Name]; ok {
+		elem.Value = cloneRuleset(rs)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[rs.Name] = c.order.PushFront(cloneRuleset(rs))
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*Ruleset).Name)
+	}
+}
+
+// Delete removes a ruleset from the cache
+func (c *LRUCache) Delete(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, name)
+	}
+}
+
+// Clear removes every entry from the cache
+func (c *LRUCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Len returns the number of cached rulesets
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// cacheGeneration identifies the state of a cache entry: a read may only
+// cache what it read while the generation it took beforehand is current
+type cacheGeneration struct {
+	epoch uint64
+	name  uint64
+}
+
+// cacheGenerations counts invalidations per ruleset and flushes of the whole
+// cache, so a read racing a mutation does not cache the value it replaced
+type cacheGenerations struct {
+	mu    sync.Mutex
+	epoch uint64
+	names map[string]uint64
+}
+
+// current returns the generation of the entry for name
+func (g *cacheGenerations) current(name string) cacheGeneration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return cacheGeneration{epoch: g.epoch, name: g.names[name]}
+}
+
+// setIfCurrent stores rs in cache unless its entry was invalidated since gen was taken
+func (g *cacheGenerations) setIfCurrent(cache Cache, rs *Ruleset, gen cacheGeneration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.epoch == gen.epoch && g.names[rs.Name] == gen.name {
+		cache.Set(rs)
+	}
+}
+
+// delete drops the entry for name from cache and advances its generation
+func (g *cacheGenerations) delete(cache Cache, name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.names == nil {
+		g.names = make(map[string]uint64)
+	}
+	g.names[name]++
+	cache.Delete(name)
+}
+
+// clear empties cache and advances the generation of every entry
+func (g *cacheGenerations) clear(cache Cache) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.epoch++
+	g.names = nil
+	cache.Clear()
+}
+
+// cloneRuleset returns a deep copy so cached values cannot be mutated by callers
+func cloneRuleset(rs *Ruleset) *Ruleset {
+	clone := *rs
+	if rs.Tags != nil {
+		clone.Tags = append([]string(nil), rs.Tags...)
+	}
+	return &clone
+}
@@ -0,0 +1,131 @@
+package ruleset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache_GetSet(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	cache.Set(&Ruleset{Name: "first", Tags: []string{"a"}})
+
+	cached, ok := cache.Get("first")
+	require.True(t, ok)
+	assert.Equal(t, "first", cached.Name)
+
+	// Mutating the returned copy must not affect the cache
+	cached.Tags[0] = "mutated"
+	again, ok := cache.Get("first")
+	require.True(t, ok)
+	assert.Equal(t, []string{"a"}, again.Tags)
+
+	_, ok = cache.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestLRUCache_Eviction(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	cache.Set(&Ruleset{Name: "first"})
+	cache.Set(&Ruleset{Name: "second"})
+
+	// Touch first so second becomes least recently used
+	_, _ = cache.Get("first")
+	cache.Set(&Ruleset{Name: "third"})
+
+	assert.Equal(t, 2, cache.Len())
+	_, ok := cache.Get("second")
+	assert.False(t, ok)
+	_, ok = cache.Get("first")
+	assert.True(t, ok)
+	_, ok = cache.Get("third")
+	assert.True(t, ok)
+}
+
+func TestLRUCache_DeleteAndClear(t *testing.T) {
+	cache := NewLRUCache(4)
+
+	cache.Set(&Ruleset{Name: "first"})
+	cache.Set(&Ruleset{Name: "second"})
+
+	cache.Delete("first")
+	_, ok := cache.Get("first")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLRUCache_ZeroCapacity(t *testing.T) {
+	cache := NewLRUCache(0)
+
+	cache.Set(&Ruleset{Name: "first"})
+	_, ok := cache.Get("first")
+	assert.False(t, ok)
+}
+
+// racingStorage runs interleave once, right after the next read of a ruleset
+type racingStorage struct {
+	*memstore.Store
+	interleave func()
+}
+
+func (r *racingStorage) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	result, err := r.Store.HGetAll(ctx, key)
+	if interleave := r.interleave; interleave != nil {
+		r.interleave = nil
+		interleave()
+	}
+	return result, err
+}
+
+func TestService_GetDoesNotCacheRacedReads(t *testing.T) {
+	storage := &racingStorage{Store: memstore.New()}
+	cache := NewLRUCache(10)
+	service := NewService(storage, WithCache(cache))
+	require.NoError(t, service.Create(&Ruleset{Name: "raced", Description: "Raced", Markdown: "# v1"}))
+
+	testCases := []struct {
+		name       string
+		interleave func()
+		want       string
+	}{
+		{"update", func() {
+			markdown := "# v2"
+			require.NoError(t, service.Update("raced", &Update{Markdown: &markdown}))
+		}, "# v2"},
+		{"invalidation by another instance", func() {
+			markdown := "# v3"
+			require.NoError(t, service.Update("raced", &Update{Markdown: &markdown}))
+			service.InvalidateCache("raced")
+		}, "# v3"},
+		{"flush", func() {
+			markdown := "# v4"
+			require.NoError(t, service.Update("raced", &Update{Markdown: &markdown}))
+			service.FlushCache()
+		}, "# v4"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache.Clear()
+			storage.interleave = tc.interleave
+
+			// The read returns what it read before the mutation, without caching it
+			_, err := service.Get("raced")
+			require.NoError(t, err)
+			assert.Equal(t, 0, cache.Len())
+
+			rs, err := service.Get("raced")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, rs.Markdown)
+			assert.Equal(t, 1, cache.Len())
+		})
+	}
+}
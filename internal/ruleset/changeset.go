@@ -0,0 +1,124 @@
+package ruleset
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Kinds of staged changes
+const (
+	ChangeUpsert = "upsert"
+	ChangeDelete = "delete"
+)
+
+// Change is a mutation staged in a changeset
+type Change struct {
+	// Kind is ChangeUpsert or ChangeDelete
+	Kind string
+	// Name is the ruleset the change applies to
+	Name string
+	// Ruleset and Updates are the arguments of an upsert
+	Ruleset *Ruleset
+	Updates *Update
+	// DeleteOptions are the options of a delete
+	DeleteOptions DeleteOptions
+}
+
+// Describe returns a short description of the change, e.g. "upsert go_rules"
+func (c Change) Describe() string {
+	return c.Kind + " " + c.Name
+}
+
+// ApplyChangeset applies changes in order. When one fails, the rulesets
+// touched so far are restored to their state before the changeset, so either
+// every change is applied or none is. Writes by other clients are not held
+// off meanwhile: they may observe intermediate states, and a concurrent write
+// to a touched ruleset is overwritten by a rollback.
+func (s *Service) ApplyChangeset(changes []Change) error {
+	// Record the state of every touched ruleset before the first change
+	before := make(map[string]*Ruleset)
+	for _, c := range changes {
+		if _, ok := before[c.Name]; ok {
+			continue
+		}
+		prior, err := s.current(c.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read ruleset '%s' before applying the changeset: %w", c.Name, err)
+		}
+		before[c.Name] = prior
+	}
+
+	// Rulesets written by applied changes, in first-write order
+	var written []string
+	for i, c := range changes {
+		if err := s.applyChange(c); err != nil {
+			cause := fmt.Errorf("change %d (%s) failed: %w", i+1, c.Describe(), err)
+			if rollbackErr := s.rollback(written, before); rollbackErr != nil {
+				return fmt.Errorf("%w; rolling back the changeset failed, so earlier changes may remain: %w", cause, rollbackErr)
+			}
+			return fmt.Errorf("%w; earlier changes were rolled back", cause)
+		}
+		if !slices.Contains(written, c.Name) {
+			written = append(written, c.Name)
+		}
+	}
+	return nil
+}
+
+// current returns the stored ruleset named name, or nil when there is none
+func (s *Service) current(name string) (*Ruleset, error) {
+	exists, err := s.Exists(name)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return s.Get(name)
+}
+
+// applyChange applies a single staged change
+func (s *Service) applyChange(c Change) error {
+	switch c.Kind {
+	case ChangeUpsert:
+		_, err := s.Upsert(c.Ruleset, c.Updates)
+		return err
+	case ChangeDelete:
+		return s.DeleteWithOptions(c.Name, c.DeleteOptions)
+	default:
+		return fmt.Errorf("unknown change kind '%s'", c.Kind)
+	}
+}
+
+// rollback returns the rulesets named in names to their recorded state,
+// deleting those that did not exist. Hooks run as for regular changes so
+// subscribers see the rollback.
+func (s *Service) rollback(names []string, before map[string]*Ruleset) error {
+	var errs []error
+	for _, name := range names {
+		changed, err := s.current(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		prior := before[name]
+		if prior == nil {
+			if changed != nil {
+				errs = append(errs, s.DeleteWithOptions(name, DeleteOptions{Force: true}))
+			}
+			continue
+		}
+
+		restored := *prior
+		if _, err := s.Restore(&restored); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch {
+		case changed == nil && s.opts.Hooks.AfterCreate != nil:
+			s.opts.Hooks.AfterCreate(&restored)
+		case changed != nil && s.opts.Hooks.AfterUpdate != nil:
+			s.opts.Hooks.AfterUpdate(changed, &restored)
+		}
+	}
+	return errors.Join(errs...)
+}
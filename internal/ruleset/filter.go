@@ -0,0 +1,111 @@
+package ruleset
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// Filter selects rulesets by name pattern and metadata. Zero-valued fields
+// match everything and all set fields must match (AND). The pattern is applied
+// to the key scan first, date ranges are then narrowed through the timestamp
+// indexes and the remaining criteria are checked on each loaded ruleset.
+type Filter struct {
+	// Pattern is a glob matched against ruleset names
+	Pattern string
+	// Tags selects rulesets carrying every one of these tags, ignoring case
+	Tags []string
+	// Text selects rulesets whose name, description or tags contain every one of these terms, ignoring case
+	Text []string
+	// License selects rulesets whose license expression references this SPDX identifier
+	License string
+	// Owner and Team select rulesets assigned to this owner or team, ignoring case
+	Owner string
+	Team  string
+	// CreatedAfter selects rulesets created strictly after this time
+	CreatedAfter time.Time
+	// ModifiedAfter selects rulesets last modified strictly after this time
+	ModifiedAfter time.Time
+	// ModifiedBefore selects rulesets last modified strictly before this time
+	ModifiedBefore time.Time
+}
+
+// HasCriteria reports whether the filter selects on anything besides the name pattern
+func (f Filter) HasCriteria() bool {
+	return f.License != "" || f.Owner != "" || f.Team != "" || len(f.Tags) > 0 || len(f.Text) > 0 || !f.CreatedAfter.IsZero() ||
+		!f.ModifiedAfter.IsZero() || !f.ModifiedBefore.IsZero()
+}
+
+// validate checks the filter values before any data is read
+func (f Filter) validate() error {
+	if f.License != "" {
+		if err := validation.ValidateLicense(f.License); err != nil {
+			return err
+		}
+	}
+	if !f.ModifiedAfter.IsZero() && !f.ModifiedBefore.IsZero() && !f.ModifiedAfter.Before(f.ModifiedBefore) {
+		return fmt.Errorf("modified_after must be earlier than modified_before")
+	}
+	return nil
+}
+
+// matches reports whether rs satisfies the metadata criteria of the filter
+func (f Filter) matches(rs *Ruleset) bool {
+	if f.License != "" && !referencesLicense(rs.License, f.License) {
+		return false
+	}
+	if f.Owner != "" && !strings.EqualFold(rs.Owner, f.Owner) {
+		return false
+	}
+	if f.Team != "" && !strings.EqualFold(rs.Team, f.Team) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.ContainsFunc(rs.Tags, func(candidate string) bool { return strings.EqualFold(candidate, tag) }) {
+			return false
+		}
+	}
+	for _, term := range f.Text {
+		if !containsTerm(rs, term) {
+			return false
+		}
+	}
+	if !f.CreatedAfter.IsZero() && !rs.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.ModifiedAfter.IsZero() && !rs.LastModified.After(f.ModifiedAfter) {
+		return false
+	}
+	if !f.ModifiedBefore.IsZero() && !rs.LastModified.Before(f.ModifiedBefore) {
+		return false
+	}
+	return true
+}
+
+// containsTerm reports whether the ruleset's name, description or tags contain term, ignoring case
+func containsTerm(rs *Ruleset, term string) bool {
+	term = strings.ToLower(term)
+	if strings.Contains(strings.ToLower(rs.Name), term) || strings.Contains(strings.ToLower(rs.Description), term) {
+		return true
+	}
+	return slices.ContainsFunc(rs.Tags, func(tag string) bool {
+		return strings.Contains(strings.ToLower(tag), term)
+	})
+}
+
+// referencesLicense reports whether the license expression references id, ignoring case
+func referencesLicense(expression, id string) bool {
+	if expression == "" {
+		return false
+	}
+	ids, err := validation.LicenseIdentifiers(expression)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(ids, func(candidate string) bool {
+		return strings.EqualFold(candidate, id)
+	})
+}
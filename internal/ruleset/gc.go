@@ -0,0 +1,98 @@
+package ruleset
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// GCReport summarizes a garbage collection run
+type GCReport struct {
+	// BlobsScanned is the number of content blobs examined
+	BlobsScanned int
+	// BlobsRemoved is the number of unreferenced blobs deleted
+	BlobsRemoved int
+	// BytesReclaimed is the content size of the deleted blobs
+	BytesReclaimed int64
+}
+
+// CollectGarbage deletes content blobs that no ruleset references any more.
+// Blobs stored less than minAge ago are kept, since a write stores its blob
+// before the ruleset hash that references it.
+func (s *Service) CollectGarbage(minAge time.Duration) (GCReport, error) {
+	var report GCReport
+
+	prefix := "blob:" + s.opts.KeyPrefix
+	blobKeys, err := s.storage.ScanKeys(s.ctx, globEscaper.Replace(prefix)+"*")
+	if err != nil {
+		return report, fmt.Errorf("failed to scan content blobs: %w", err)
+	}
+	if len(blobKeys) == 0 {
+		return report, nil
+	}
+
+	// Collect references after listing blobs, so a blob referenced by a write
+	// racing with the scan is either too young to collect or already referenced
+	referenced, err := s.referencedBlobs()
+	if err != nil {
+		return report, err
+	}
+
+	cutoff := s.opts.Clock().Add(-minAge)
+	for _, key := range blobKeys {
+		digest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		report.BlobsScanned++
+		if referenced[digest] {
+			continue
+		}
+
+		blob, err := s.storage.HGetAll(s.ctx, key)
+		if err != nil {
+			return report, fmt.Errorf("failed to read content blob: %w", err)
+		}
+		if storedAt, err := validation.ParseTimestamp(blob["stored_at"]); err == nil && storedAt.After(cutoff) {
+			continue
+		}
+
+		// A write of the same content refreshes stored_at before committing the
+		// ruleset hash referencing it, so only delete the blob as it was read
+		deleted, err := s.storage.DelIfField(s.ctx, key, "stored_at", blob["stored_at"])
+		if err != nil {
+			return report, fmt.Errorf("failed to delete content blob: %w", err)
+		}
+		if !deleted {
+			continue
+		}
+		report.BlobsRemoved++
+		report.BytesReclaimed += int64(len(blob["markdown"]))
+		log.Debug().Str("blob", digest).Msg("Removed unreferenced content blob")
+	}
+
+	return report, nil
+}
+
+// referencedBlobs returns the digests of the blobs referenced by stored rulesets
+func (s *Service) referencedBlobs() (map[string]bool, error) {
+	keys, err := s.scanKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ruleset keys: %w", err)
+	}
+
+	referenced := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		fields, err := s.storage.HGetAll(s.ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ruleset: %w", err)
+		}
+		if digest := fields["blob"]; digest != "" {
+			referenced[digest] = true
+		}
+	}
+	return referenced, nil
+}
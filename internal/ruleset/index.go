@@ -0,0 +1,153 @@
+package ruleset
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Sorted-set indexes of ruleset names scored by Unix time
+const (
+	indexCreated  = "created"
+	indexModified = "modified"
+)
+
+// indexKey returns the key of a timestamp index, e.g. index:ruleset:modified
+func (s *Service) indexKey(kind string) string {
+	return "index:" + s.opts.KeyPrefix + kind
+}
+
+// indexScore converts a timestamp to an index score with second precision
+func indexScore(t time.Time) float64 {
+	return float64(t.Unix())
+}
+
+// index records the ruleset's timestamps. Indexes are derived data, so
+// failures are logged rather than failing the mutation that triggered them.
+func (s *Service) index(rs *Ruleset) {
+	if err := s.storage.ZAdd(s.ctx, s.indexKey(indexCreated), rs.Name, indexScore(rs.CreatedAt)); err != nil {
+		log.Warn().Err(err).Str("ruleset", rs.Name).Msg("Failed to index ruleset creation time")
+	}
+	if err := s.storage.ZAdd(s.ctx, s.indexKey(indexModified), rs.Name, indexScore(rs.LastModified)); err != nil {
+		log.Warn().Err(err).Str("ruleset", rs.Name).Msg("Failed to index ruleset modification time")
+	}
+}
+
+// unindex removes a deleted ruleset from the timestamp indexes
+func (s *Service) unindex(name string) {
+	for _, kind := range []string{indexCreated, indexModified} {
+		if err := s.storage.ZRem(s.ctx, s.indexKey(kind), name); err != nil {
+			log.Warn().Err(err).Str("ruleset", name).Str("index", kind).Msg("Failed to remove ruleset from index")
+		}
+	}
+}
+
+// RebuildIndexes re-records the timestamps of every stored ruleset and drops
+// entries of rulesets that no longer exist. It adds rulesets written before
+// indexing existed and returns how many rulesets were indexed.
+func (s *Service) RebuildIndexes() (int, error) {
+	return s.RebuildIndexesWithProgress(nil)
+}
+
+// RebuildIndexesWithProgress is RebuildIndexes reporting progress after each
+// ruleset as the number processed out of the total. progress may be nil.
+func (s *Service) RebuildIndexesWithProgress(progress func(done, total int)) (int, error) {
+	names, err := s.ListNames()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	stored := make(map[string]bool, len(names))
+	for i, name := range names {
+		rs, err := s.lookup(name)
+		switch {
+		case err != nil:
+			log.Warn().Err(err).Str("ruleset", name).Msg("Skipping unreadable ruleset while rebuilding indexes")
+			stored[name] = true
+		case rs != nil:
+			stored[name] = true
+			s.index(rs)
+			count++
+		}
+		if progress != nil {
+			progress(i+1, len(names))
+		}
+	}
+
+	// Remove stale members in place so concurrent searches never see an empty index.
+	// Rulesets created since names was listed are kept, as they are indexed already.
+	for _, kind := range []string{indexCreated, indexModified} {
+		members, err := s.storage.ZRangeByScore(s.ctx, s.indexKey(kind), math.Inf(-1), math.Inf(1))
+		if err != nil {
+			return count, fmt.Errorf("failed to read %s index: %w", kind, err)
+		}
+		for _, member := range members {
+			if stored[member] {
+				continue
+			}
+			exists, err := s.storage.Exists(s.ctx, s.key(member))
+			if err != nil {
+				return count, fmt.Errorf("failed to prune %s index: %w", kind, err)
+			}
+			if exists {
+				stored[member] = true
+				continue
+			}
+			if err := s.storage.ZRem(s.ctx, s.indexKey(kind), member); err != nil {
+				return count, fmt.Errorf("failed to prune %s index: %w", kind, err)
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// indexedNames returns the names whose indexed timestamps fall within the
+// filter's date ranges, or nil when the filter has no date criteria.
+// Bounds are widened to whole seconds; matches applies the exact comparison.
+func (s *Service) indexedNames(filter Filter) (map[string]bool, error) {
+	type rangeQuery struct {
+		kind       string
+		start, end time.Time
+	}
+
+	var queries []rangeQuery
+	if !filter.CreatedAfter.IsZero() {
+		queries = append(queries, rangeQuery{kind: indexCreated, start: filter.CreatedAfter})
+	}
+	if !filter.ModifiedAfter.IsZero() || !filter.ModifiedBefore.IsZero() {
+		queries = append(queries, rangeQuery{kind: indexModified, start: filter.ModifiedAfter, end: filter.ModifiedBefore})
+	}
+	if len(queries) == 0 {
+		return nil, nil
+	}
+
+	var names map[string]bool
+	for _, q := range queries {
+		minScore, maxScore := math.Inf(-1), math.Inf(1)
+		if !q.start.IsZero() {
+			minScore = indexScore(q.start)
+		}
+		if !q.end.IsZero() {
+			maxScore = indexScore(q.end) + 1
+		}
+
+		members, err := s.storage.ZRangeByScore(s.ctx, s.indexKey(q.kind), minScore, maxScore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s index: %w", q.kind, err)
+		}
+
+		matched := make(map[string]bool, len(members))
+		for _, member := range members {
+			if names == nil || names[member] {
+				matched[member] = true
+			}
+		}
+		names = matched
+	}
+
+	return names, nil
+}
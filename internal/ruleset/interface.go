@@ -6,10 +6,23 @@ type ServiceInterface interface {
 	Create(rs *Ruleset) error
 	Get(name string) (*Ruleset, error)
 	Update(name string, updates *Update) error
-	Upsert(rs *Ruleset, updates *Update) error
+	Upsert(rs *Ruleset, updates *Update) (created bool, err error)
 	Delete(name string) error
+	DeleteWithOptions(name string, opts DeleteOptions) error
 	List() ([]*Ruleset, error)
 	Search(pattern string) ([]*Ruleset, error)
+	Find(filter Filter) ([]*Ruleset, error)
 	Exists(name string) (bool, error)
 	ListNames() ([]string, error)
 }
+
+// Maintainer repairs derived data; it backs the operator-only admin tools
+type Maintainer interface {
+	RebuildIndexesWithProgress(progress func(done, total int)) (int, error)
+	FlushCache() bool
+}
+
+// ChangesetApplier applies changesets; it backs the changeset tools
+type ChangesetApplier interface {
+	ApplyChangeset(changes []Change) error
+}
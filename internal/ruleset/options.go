@@ -0,0 +1,174 @@
+package ruleset
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultKeyPrefix is the Valkey key prefix used for ruleset hashes
+const DefaultKeyPrefix = "ruleset:"
+
+// Validator inspects a ruleset before it is written and returns an error to reject it
+type Validator func(rs *Ruleset) error
+
+// Limits bounds the size of ruleset fields. Zero values mean unlimited.
+type Limits struct {
+	MaxMarkdownBytes    int
+	MaxDescriptionBytes int
+	MaxTags             int
+}
+
+// Hooks are callbacks invoked after successful mutations
+type Hooks struct {
+	AfterCreate func(rs *Ruleset)
+	// AfterUpdate receives the ruleset as it was before and after the update
+	AfterUpdate func(previous, updated *Ruleset)
+	AfterDelete func(name string)
+}
+
+// CombineHooks returns hooks invoking the callbacks of each of hooks in order
+func CombineHooks(hooks ...Hooks) Hooks {
+	var combined Hooks
+	for _, h := range hooks {
+		if h.AfterCreate != nil {
+			next, prev := h.AfterCreate, combined.AfterCreate
+			combined.AfterCreate = func(rs *Ruleset) {
+				if prev != nil {
+					prev(rs)
+				}
+				next(rs)
+			}
+		}
+		if h.AfterUpdate != nil {
+			next, prev := h.AfterUpdate, combined.AfterUpdate
+			combined.AfterUpdate = func(previous, updated *Ruleset) {
+				if prev != nil {
+					prev(previous, updated)
+				}
+				next(previous, updated)
+			}
+		}
+		if h.AfterDelete != nil {
+			next, prev := h.AfterDelete, combined.AfterDelete
+			combined.AfterDelete = func(name string) {
+				if prev != nil {
+					prev(name)
+				}
+				next(name)
+			}
+		}
+	}
+	return combined
+}
+
+// Options holds the configuration of a Service
+type Options struct {
+	KeyPrefix  string
+	Clock      func() time.Time
+	Validators []Validator
+	Cache      Cache
+	Limits     Limits
+	Hooks      Hooks
+	ParseMode  ParseMode
+	// BlobStorage stores bodies as shared content-addressed blobs
+	BlobStorage bool
+	// BackgroundReindex defers indexing of bulk imports to a background
+	// rebuild pausing ReindexDelay after each ruleset
+	BackgroundReindex bool
+	ReindexDelay      time.Duration
+}
+
+// Option configures a Service
+type Option func(*Options)
+
+// defaultOptions returns the options used when none are supplied
+func defaultOptions() Options {
+	return Options{
+		KeyPrefix: DefaultKeyPrefix,
+		Clock:     time.Now,
+		ParseMode: ParseModeStrict,
+	}
+}
+
+// WithKeyPrefix sets the Valkey key prefix for ruleset hashes
+func WithKeyPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.KeyPrefix = prefix
+	}
+}
+
+// WithClock sets the time source used for timestamps
+func WithClock(clock func() time.Time) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithValidators adds validators run before every create and update
+func WithValidators(validators ...Validator) Option {
+	return func(o *Options) {
+		o.Validators = append(o.Validators, validators...)
+	}
+}
+
+// WithCache enables read caching of rulesets
+func WithCache(cache Cache) Option {
+	return func(o *Options) {
+		o.Cache = cache
+	}
+}
+
+// WithLimits sets size limits for ruleset fields
+func WithLimits(limits Limits) Option {
+	return func(o *Options) {
+		o.Limits = limits
+	}
+}
+
+// WithHooks sets callbacks invoked after successful mutations
+func WithHooks(hooks Hooks) Option {
+	return func(o *Options) {
+		o.Hooks = hooks
+	}
+}
+
+// WithParseMode configures how malformed stored data is handled on read
+func WithParseMode(mode ParseMode) Option {
+	return func(o *Options) {
+		o.ParseMode = mode
+	}
+}
+
+// WithBlobStorage stores ruleset bodies as content-addressed blobs referenced
+// from the ruleset hashes, so identical bodies are stored once. Rulesets with
+// inline bodies stay readable and move to a blob on their next content update.
+func WithBlobStorage() Option {
+	return func(o *Options) {
+		o.BlobStorage = true
+	}
+}
+
+// WithBackgroundReindex makes bulk imports leave index maintenance to a
+// rebuild run in the background once they finish, pausing delay after each
+// ruleset so the rebuild does not crowd out regular traffic. Until it
+// completes, searches by date may miss the imported rulesets.
+func WithBackgroundReindex(delay time.Duration) Option {
+	return func(o *Options) {
+		o.BackgroundReindex = true
+		o.ReindexDelay = delay
+	}
+}
+
+// check verifies that a ruleset respects the configured limits
+func (l Limits) check(rs *Ruleset) error {
+	if l.MaxMarkdownBytes > 0 && len(rs.Markdown) > l.MaxMarkdownBytes {
+		return fmt.Errorf("markdown content is %d bytes, exceeding the limit of %d bytes", len(rs.Markdown), l.MaxMarkdownBytes)
+	}
+	if l.MaxDescriptionBytes > 0 && len(rs.Description) > l.MaxDescriptionBytes {
+		return fmt.Errorf("description is %d bytes, exceeding the limit of %d bytes", len(rs.Description), l.MaxDescriptionBytes)
+	}
+	if l.MaxTags > 0 && len(rs.Tags) > l.MaxTags {
+		return fmt.Errorf("ruleset has %d tags, exceeding the limit of %d tags", len(rs.Tags), l.MaxTags)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package ruleset
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewService_DefaultOptions(t *testing.T) {
+	service := NewService(nil)
+
+	assert.Equal(t, DefaultKeyPrefix, service.opts.KeyPrefix)
+	assert.Equal(t, ParseModeStrict, service.opts.ParseMode)
+	assert.NotNil(t, service.opts.Clock)
+	assert.Nil(t, service.opts.Cache)
+	assert.Equal(t, "ruleset:example", service.key("example"))
+}
+
+func TestNewService_WithOptions(t *testing.T) {
+	fixed := time.Date(2025, 10, 29, 10, 30, 0, 0, time.UTC)
+	cache := NewLRUCache(8)
+	validator := func(_ *Ruleset) error { return nil }
+
+	service := NewService(nil,
+		WithKeyPrefix("team:"),
+		WithClock(func() time.Time { return fixed }),
+		WithValidators(validator, validator),
+		WithCache(cache),
+		WithLimits(Limits{MaxMarkdownBytes: 10}),
+		WithParseMode(ParseModeLenient),
+	)
+
+	assert.Equal(t, "team:example", service.key("example"))
+	assert.Equal(t, fixed, service.opts.Clock())
+	assert.Len(t, service.opts.Validators, 2)
+	assert.Same(t, cache, service.opts.Cache)
+	assert.Equal(t, 10, service.opts.Limits.MaxMarkdownBytes)
+	assert.Equal(t, ParseModeLenient, service.opts.ParseMode)
+}
+
+func TestLimits_Check(t *testing.T) {
+	limits := Limits{MaxMarkdownBytes: 8, MaxDescriptionBytes: 4, MaxTags: 1}
+
+	testCases := []struct {
+		name    string
+		ruleset *Ruleset
+		wantErr string
+	}{
+		{"within limits", &Ruleset{Markdown: "# ok", Description: "ok", Tags: []string{"a"}}, ""},
+		{"markdown too large", &Ruleset{Markdown: strings.Repeat("x", 9)}, "markdown content is 9 bytes"},
+		{"description too large", &Ruleset{Description: "too long"}, "description is 8 bytes"},
+		{"too many tags", &Ruleset{Tags: []string{"a", "b"}}, "ruleset has 2 tags"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := limits.check(tc.ruleset)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+
+	// Zero limits never reject
+	assert.NoError(t, Limits{}.check(&Ruleset{Markdown: strings.Repeat("x", 1<<16)}))
+}
+
+func TestCombineHooks(t *testing.T) {
+	var calls []string
+	hooks := CombineHooks(
+		Hooks{
+			AfterCreate: func(rs *Ruleset) { calls = append(calls, "first create "+rs.Name) },
+			AfterDelete: func(name string) { calls = append(calls, "first delete "+name) },
+		},
+		Hooks{},
+		Hooks{
+			AfterCreate: func(rs *Ruleset) { calls = append(calls, "second create "+rs.Name) },
+			AfterUpdate: func(_, updated *Ruleset) { calls = append(calls, "second update "+updated.Name) },
+		},
+	)
+
+	hooks.AfterCreate(&Ruleset{Name: "a"})
+	hooks.AfterUpdate(&Ruleset{Name: "b"}, &Ruleset{Name: "b"})
+	hooks.AfterDelete("c")
+	assert.Equal(t, []string{"first create a", "second create a", "second update b", "first delete c"}, calls)
+
+	// Callbacks no hook sets stay nil
+	assert.Nil(t, CombineHooks(Hooks{}).AfterCreate)
+}
@@ -0,0 +1,46 @@
+package ruleset
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// referencePattern matches links to the ruleset named name: markdown links
+// [text](ruleset://name?query), capturing the text, and bare ruleset://name?query
+// URIs, capturing the character ending them. That character must not continue
+// the name, so go_rules does not match ruleset://go_rules_v2.
+func referencePattern(name string) *regexp.Regexp {
+	uri := `ruleset://` + regexp.QuoteMeta(name)
+	return regexp.MustCompile(`\[([^\]]*)\]\(` + uri + `(?:\?[^\s)]*)?\)|` + uri + `(?:\?[^\s)\]]*)?([^a-z0-9_?]|$)`)
+}
+
+// LinksTo reports whether content links to the ruleset named name
+func LinksTo(content, name string) bool {
+	return strings.Contains(content, "ruleset://"+name) && referencePattern(name).MatchString(content)
+}
+
+// Unlink removes links to the ruleset named name from content. Markdown links
+// are replaced by their text and bare URIs by the name.
+func Unlink(content, name string) string {
+	pattern := referencePattern(name)
+	return pattern.ReplaceAllStringFunc(content, func(match string) string {
+		groups := pattern.FindStringSubmatch(match)
+		if strings.HasPrefix(match, "[") {
+			return groups[1]
+		}
+		return name + groups[2]
+	})
+}
+
+// References returns the sorted names of the rulesets among rulesets that link to the ruleset named name
+func References(rulesets []*Ruleset, name string) []string {
+	var names []string
+	for _, rs := range rulesets {
+		if rs.Name != name && LinksTo(rs.Markdown, name) {
+			names = append(names, rs.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
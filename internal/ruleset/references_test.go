@@ -0,0 +1,66 @@
+package ruleset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinksToAndUnlink(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		links    bool
+		unlinked string
+	}{
+		{
+			name:     "markdown link",
+			content:  "See [the Go rules](ruleset://go_rules) first.",
+			links:    true,
+			unlinked: "See the Go rules first.",
+		},
+		{
+			name:     "markdown link with query",
+			content:  "See [testing](ruleset://go_rules?section=testing).",
+			links:    true,
+			unlinked: "See testing.",
+		},
+		{
+			name:     "bare URI",
+			content:  "Also read ruleset://go_rules, then ruleset://go_rules?raw=true\nand ruleset://go_rules",
+			links:    true,
+			unlinked: "Also read go_rules, then go_rules\nand go_rules",
+		},
+		{
+			name:     "longer name",
+			content:  "See [v2](ruleset://go_rules_v2) and ruleset://go_rules_v2.",
+			links:    false,
+			unlinked: "See [v2](ruleset://go_rules_v2) and ruleset://go_rules_v2.",
+		},
+		{
+			name:     "name without link",
+			content:  "Follow go_rules.",
+			links:    false,
+			unlinked: "Follow go_rules.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.links, LinksTo(tt.content, "go_rules"))
+			assert.Equal(t, tt.unlinked, Unlink(tt.content, "go_rules"))
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	rulesets := []*Ruleset{
+		{Name: "python_rules", Markdown: "Like [Go](ruleset://go_rules)."},
+		{Name: "go_rules", Markdown: "Self link ruleset://go_rules"},
+		{Name: "api_rules", Markdown: "ruleset://go_rules"},
+		{Name: "rust_rules", Markdown: "# Rust"},
+	}
+
+	assert.Equal(t, []string{"api_rules", "python_rules"}, References(rulesets, "go_rules"))
+	assert.Empty(t, References(rulesets, "rust_rules"))
+}
@@ -0,0 +1,173 @@
+package ruleset
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// IndexStatus reports the progress of background index rebuilds
+type IndexStatus struct {
+	// Running is set while a rebuild is in progress; Done and Total count its
+	// rulesets processed so far out of those stored when it started
+	Running bool `json:"running"`
+	Done    int  `json:"done"`
+	Total   int  `json:"total"`
+	// Pending is set when another rebuild has been requested to follow the running one
+	Pending bool `json:"pending"`
+	// DeferredImports is the number of bulk imports in progress whose writes are not indexed yet
+	DeferredImports int       `json:"deferred_imports"`
+	StartedAt       time.Time `json:"started_at,omitzero"`
+	FinishedAt      time.Time `json:"finished_at,omitzero"`
+	// Indexed and Error describe the last finished rebuild
+	Indexed int    `json:"indexed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// IndexStatusReporter reports the progress of background index rebuilds
+type IndexStatusReporter interface {
+	IndexStatus() IndexStatus
+}
+
+// BulkImporter defers index maintenance of the writes made by a bulk import
+// to a background rebuild, so large imports are not slowed by it
+type BulkImporter interface {
+	// BeginBulkImport starts deferring; calling end schedules the rebuild
+	BeginBulkImport() (end func())
+}
+
+// reindexer runs throttled index rebuilds in the background, one at a time.
+// A rebuild requested while one runs is coalesced into a single follow-up.
+type reindexer struct {
+	service *Service
+	delay   time.Duration
+	sleep   func(time.Duration)
+
+	mu       sync.Mutex
+	status   IndexStatus
+	finished chan struct{}
+}
+
+// newReindexer creates the background reindexer of s
+func newReindexer(s *Service, delay time.Duration) *reindexer {
+	return &reindexer{service: s, delay: delay, sleep: time.Sleep}
+}
+
+// deferring reports whether writes should leave indexing to a later rebuild
+func (r *reindexer) deferring() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status.DeferredImports > 0
+}
+
+// schedule starts a rebuild, or queues one to follow the running rebuild
+func (r *reindexer) schedule() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status.Running {
+		r.status.Pending = true
+		return
+	}
+	r.start()
+}
+
+// start begins a rebuild in the background; r.mu must be held
+func (r *reindexer) start() {
+	r.status.Running = true
+	r.status.Pending = false
+	r.status.Done, r.status.Total = 0, 0
+	r.status.StartedAt = r.service.opts.Clock()
+	r.finished = make(chan struct{})
+	go r.run(r.finished)
+}
+
+// run rebuilds the indexes, then starts the next rebuild if one was requested meanwhile
+func (r *reindexer) run(finished chan struct{}) {
+	defer close(finished)
+
+	count, err := r.service.RebuildIndexesWithProgress(func(done, total int) {
+		r.mu.Lock()
+		r.status.Done, r.status.Total = done, total
+		r.mu.Unlock()
+		if r.delay > 0 {
+			r.sleep(r.delay)
+		}
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Background index rebuild failed")
+	} else {
+		log.Info().Int("rulesets", count).Msg("Background index rebuild finished")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Running = false
+	r.status.FinishedAt = r.service.opts.Clock()
+	r.status.Indexed = count
+	r.status.Error = ""
+	if err != nil {
+		r.status.Error = err.Error()
+	}
+	if r.status.Pending {
+		r.start()
+	}
+}
+
+// wait blocks until no rebuild is running or pending
+func (r *reindexer) wait() {
+	for {
+		r.mu.Lock()
+		finished := r.finished
+		running := r.status.Running
+		r.mu.Unlock()
+		if !running {
+			return
+		}
+		<-finished
+	}
+}
+
+// BeginBulkImport defers indexing of the writes made until end is called,
+// which schedules a background rebuild of the indexes. Writes by other
+// clients meanwhile are deferred too, and are indexed by the same rebuild.
+// Without WithBackgroundReindex writes are indexed as usual and end does nothing.
+func (s *Service) BeginBulkImport() (end func()) {
+	r := s.reindexer
+	if r == nil {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	r.status.DeferredImports++
+	r.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			r.status.DeferredImports--
+			r.mu.Unlock()
+			r.schedule()
+		})
+	}
+}
+
+// IndexStatus reports the progress of background index rebuilds
+func (s *Service) IndexStatus() IndexStatus {
+	if s.reindexer == nil {
+		return IndexStatus{}
+	}
+	s.reindexer.mu.Lock()
+	defer s.reindexer.mu.Unlock()
+	return s.reindexer.status
+}
+
+// indexWrite indexes a written ruleset, unless a bulk import defers indexing
+// to the background rebuild that follows it
+func (s *Service) indexWrite(rs *Ruleset) {
+	if s.reindexer != nil && s.reindexer.deferring() {
+		return
+	}
+	s.index(rs)
+}
@@ -0,0 +1,125 @@
+package ruleset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginBulkImport_DefersIndexing(t *testing.T) {
+	service, _ := newMemoryService(WithBackgroundReindex(0))
+	since := Filter{CreatedAfter: time.Now().Add(-time.Hour)}
+
+	end := service.BeginBulkImport()
+	for _, name := range []string{"go_rules", "py_rules"} {
+		require.NoError(t, service.Create(&Ruleset{Name: name, Markdown: "# Rules"}))
+	}
+	assert.Equal(t, 1, service.IndexStatus().DeferredImports)
+
+	// The imported rulesets are not indexed until the background rebuild
+	found, err := service.Find(since)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	end()
+	end() // ending twice has no further effect
+	service.reindexer.wait()
+
+	found, err = service.Find(since)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	status := service.IndexStatus()
+	assert.False(t, status.Running)
+	assert.Equal(t, 0, status.DeferredImports)
+	assert.Equal(t, 2, status.Indexed)
+	assert.Equal(t, 2, status.Done)
+	assert.Equal(t, 2, status.Total)
+	assert.False(t, status.FinishedAt.IsZero())
+	assert.Empty(t, status.Error)
+}
+
+func TestBeginBulkImport_ThrottlesAndCoalesces(t *testing.T) {
+	service, _ := newMemoryService(WithBackgroundReindex(time.Millisecond))
+	require.NoError(t, service.Create(&Ruleset{Name: "go_rules", Markdown: "# Go"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "py_rules", Markdown: "# Python"}))
+
+	// Hold the rebuild after its first ruleset to observe its progress
+	paused := make(chan time.Duration)
+	resume := make(chan struct{})
+	service.reindexer.sleep = func(d time.Duration) {
+		paused <- d
+		<-resume
+	}
+
+	service.BeginBulkImport()()
+	assert.Equal(t, time.Millisecond, <-paused)
+
+	status := service.IndexStatus()
+	assert.True(t, status.Running)
+	assert.Equal(t, 1, status.Done)
+	assert.Equal(t, 2, status.Total)
+
+	// Imports ending while a rebuild runs queue a single follow-up rebuild
+	service.BeginBulkImport()()
+	service.BeginBulkImport()()
+	assert.True(t, service.IndexStatus().Pending)
+
+	go func() {
+		for range paused {
+			resume <- struct{}{}
+		}
+	}()
+	resume <- struct{}{}
+	service.reindexer.wait()
+	close(paused)
+
+	status = service.IndexStatus()
+	assert.False(t, status.Running)
+	assert.False(t, status.Pending)
+	assert.Equal(t, 2, status.Indexed)
+}
+
+func TestBeginBulkImport_WithoutBackgroundReindex(t *testing.T) {
+	service, _ := newMemoryService()
+
+	end := service.BeginBulkImport()
+	require.NoError(t, service.Create(&Ruleset{Name: "go_rules", Markdown: "# Go"}))
+	end()
+
+	// Writes are indexed immediately
+	found, err := service.Find(Filter{CreatedAfter: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, IndexStatus{}, service.IndexStatus())
+}
+
+func TestBackgroundReindex_KeepsRulesetsCreatedMeanwhile(t *testing.T) {
+	service, _ := newMemoryService(WithBackgroundReindex(time.Millisecond))
+	require.NoError(t, service.Create(&Ruleset{Name: "go_rules", Markdown: "# Go"}))
+
+	// Hold the rebuild after its first ruleset
+	paused := make(chan time.Duration)
+	resume := make(chan struct{})
+	service.reindexer.sleep = func(d time.Duration) {
+		paused <- d
+		<-resume
+	}
+	service.BeginBulkImport()()
+	<-paused
+
+	// A ruleset created after the rebuild listed the rulesets is indexed as usual
+	require.NoError(t, service.Create(&Ruleset{Name: "py_rules", Markdown: "# Python"}))
+	resume <- struct{}{}
+	service.reindexer.wait()
+
+	found, err := service.Find(Filter{CreatedAfter: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	names := make([]string, 0, len(found))
+	for _, rs := range found {
+		names = append(names, rs.Name)
+	}
+	assert.ElementsMatch(t, []string{"go_rules", "py_rules"}, names)
+}
@@ -0,0 +1,52 @@
+package ruleset
+
+import (
+	"fmt"
+
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// Restore writes a complete ruleset as given, keeping its timestamps and
+// version, and replaces any stored ruleset of the same name. It is meant for
+// loading snapshots and dumps rather than editing: no hooks run. It reports
+// whether the ruleset was created. Missing timestamps and versions are filled
+// in as Create would.
+func (s *Service) Restore(rs *Ruleset) (bool, error) {
+	if err := validation.ValidateRulesetName(rs.Name); err != nil {
+		return false, err
+	}
+
+	if rs.ContentType == "" {
+		rs.ContentType = ContentTypeMarkdown
+	}
+	if err := s.validate(rs); err != nil {
+		return false, fmt.Errorf("ruleset '%s': %w", rs.Name, err)
+	}
+	if err := validateSourceURL(rs.SourceURL); err != nil {
+		return false, fmt.Errorf("ruleset '%s': %w", rs.Name, err)
+	}
+
+	if rs.CreatedAt.IsZero() {
+		rs.CreatedAt = s.opts.Clock()
+	}
+	if rs.LastModified.IsZero() {
+		rs.LastModified = rs.CreatedAt
+	}
+	if rs.Version < 1 {
+		rs.Version = 1
+	}
+
+	fields, err := s.encode(rs, true)
+	if err != nil {
+		return false, err
+	}
+
+	existed, err := s.storage.UpsertHash(s.ctx, s.key(rs.Name), fields, fields)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore ruleset: %w", err)
+	}
+
+	s.invalidate(rs.Name)
+	s.indexWrite(rs)
+	return !existed, nil
+}
@@ -0,0 +1,31 @@
+package ruleset
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+)
+
+// SchemaVersion is the version of the published Ruleset and Update JSON Schemas.
+// It is bumped, along with the $id of each schema, on incompatible changes.
+const SchemaVersion = 1
+
+// Names of the published JSON Schemas
+const (
+	SchemaRuleset = "ruleset"
+	SchemaUpdate  = "update"
+)
+
+// Schemas lists the names of the published JSON Schemas
+var Schemas = []string{SchemaRuleset, SchemaUpdate}
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// Schema returns the JSON Schema document describing the Ruleset or Update structure
+func Schema(name string) ([]byte, error) {
+	if !slices.Contains(Schemas, name) {
+		return nil, fmt.Errorf("unknown schema '%s' (available: %v)", name, Schemas)
+	}
+	return schemaFiles.ReadFile("schemas/" + name + ".json")
+}
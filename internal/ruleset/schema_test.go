@@ -0,0 +1,71 @@
+package ruleset
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonFieldNames returns the JSON names of the exported fields of v's type
+func jsonFieldNames(v any) []string {
+	t := reflect.TypeOf(v)
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestSchema_MatchesStructs(t *testing.T) {
+	testCases := []struct {
+		schema string
+		value  any
+	}{
+		{SchemaRuleset, Ruleset{}},
+		{SchemaUpdate, Update{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schema, func(t *testing.T) {
+			data, err := Schema(tc.schema)
+			require.NoError(t, err)
+
+			var doc struct {
+				ID         string `json:"$id"`
+				Properties map[string]struct {
+					Enum    []string `json:"enum"`
+					Pattern string   `json:"pattern"`
+				} `json:"properties"`
+			}
+			require.NoError(t, json.Unmarshal(data, &doc))
+
+			assert.Equal(t, fmt.Sprintf("urn:archivyr:schema:%s:v%d", tc.schema, SchemaVersion), doc.ID)
+
+			properties := make([]string, 0, len(doc.Properties))
+			for name := range doc.Properties {
+				properties = append(properties, name)
+			}
+			assert.ElementsMatch(t, jsonFieldNames(tc.value), properties)
+			assert.Equal(t, SupportedContentTypes, doc.Properties["content_type"].Enum)
+
+			if pattern := doc.Properties["name"].Pattern; pattern != "" {
+				re := regexp.MustCompile(pattern)
+				assert.True(t, re.MatchString("python_style_guide"))
+				assert.False(t, re.MatchString("Python-Style"))
+			}
+		})
+	}
+}
+
+func TestSchema_Unknown(t *testing.T) {
+	_, err := Schema("pack")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown schema 'pack'")
+}
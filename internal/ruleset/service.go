@@ -1,25 +1,51 @@
 package ruleset
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jbrinkman/archivyr/internal/validation"
-	"github.com/jbrinkman/archivyr/internal/valkey"
-	"github.com/valkey-io/valkey-glide/go/v2/models"
+	"github.com/rs/zerolog/log"
 )
 
 // Service provides business logic for ruleset management
 type Service struct {
-	valkeyClient *valkey.Client
+	storage Storage
+	ctx     context.Context
+	opts    Options
+	// reindexer runs background index rebuilds when WithBackgroundReindex is set
+	reindexer *reindexer
+	// generations keep reads from caching rulesets mutated while they were read
+	generations cacheGenerations
 }
 
-// NewService creates a new ruleset service instance
-func NewService(client *valkey.Client) *Service {
-	return &Service{
-		valkeyClient: client,
+// NewService creates a new ruleset service backed by storage and configured by opts
+func NewService(storage Storage, opts ...Option) *Service {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
 	}
+
+	s := &Service{
+		storage: storage,
+		ctx:     context.Background(),
+		opts:    options,
+	}
+	if options.BackgroundReindex {
+		s.reindexer = newReindexer(s, options.ReindexDelay)
+	}
+	return s
+}
+
+// key returns the Valkey key holding the named ruleset
+func (s *Service) key(name string) string {
+	return s.opts.KeyPrefix + name
 }
 
 // Exists checks if a ruleset with the given name exists
@@ -28,50 +54,37 @@ func (s *Service) Exists(name string) (bool, error) {
 		return false, err
 	}
 
-	key := fmt.Sprintf("ruleset:%s", name)
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
-
-	count, err := client.Exists(ctx, []string{key})
+	exists, err := s.storage.Exists(s.ctx, s.key(name))
 	if err != nil {
 		return false, fmt.Errorf("failed to check if ruleset exists: %w", err)
 	}
 
-	return count > 0, nil
+	return exists, nil
 }
 
 // ListNames retrieves all ruleset names from Valkey using SCAN
 func (s *Service) ListNames() ([]string, error) {
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
-
-	names := make([]string, 0)
-	cursor := models.NewCursor()
-
-	// Use SCAN to iterate through all keys matching the pattern
-	for {
-		result, err := client.Scan(ctx, cursor)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan ruleset keys: %w", err)
-		}
-
-		// Extract names from keys that match the pattern (remove "ruleset:" prefix)
-		for _, key := range result.Data {
-			if len(key) > 8 && key[:8] == "ruleset:" { // len("ruleset:") = 8
-				name := key[8:]
-				names = append(names, name)
-			}
-		}
+	keys, err := s.scanKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ruleset keys: %w", err)
+	}
 
-		cursor = result.Cursor
-		if cursor.IsFinished() {
-			break
+	// Extract names from keys that carry the ruleset prefix
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if name, ok := strings.CutPrefix(key, s.opts.KeyPrefix); ok && name != "" {
+			names = append(names, name)
 		}
 	}
 
 	return names, nil
 }
 
+// scanKeys returns every key under the ruleset prefix
+func (s *Service) scanKeys() ([]string, error) {
+	return s.storage.ScanKeys(s.ctx, globEscaper.Replace(s.opts.KeyPrefix)+"*")
+}
+
 // Create creates a new ruleset in Valkey
 func (s *Service) Create(ruleset *Ruleset) error {
 	// Validate ruleset name
@@ -79,13 +92,18 @@ func (s *Service) Create(ruleset *Ruleset) error {
 		return err
 	}
 
-	// Check if ruleset already exists
-	exists, err := s.Exists(ruleset.Name)
+	fields, err := s.prepareCreate(ruleset)
 	if err != nil {
 		return err
 	}
 
-	if exists {
+	// Store the hash only if the key is still missing
+	existed, err := s.storage.UpsertHash(s.ctx, s.key(ruleset.Name), fields, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create ruleset: %w", err)
+	}
+
+	if existed {
 		// Get list of existing names for error message
 		existingNames, listErr := s.ListNames()
 		if listErr != nil {
@@ -94,37 +112,65 @@ func (s *Service) Create(ruleset *Ruleset) error {
 		return fmt.Errorf("ruleset '%s' already exists. Please choose a different name. Existing rulesets: %v", ruleset.Name, existingNames)
 	}
 
+	s.afterCreate(ruleset)
+	return nil
+}
+
+// prepareCreate validates a new ruleset, sets its timestamps and returns its hash fields
+func (s *Service) prepareCreate(ruleset *Ruleset) (map[string]string, error) {
+	// Default to markdown when no content type is given
+	if ruleset.ContentType == "" {
+		ruleset.ContentType = ContentTypeMarkdown
+	}
+
+	// Enforce content type, size limits and custom validators
+	if err := s.validate(ruleset); err != nil {
+		return nil, err
+	}
+
+	if err := validateSourceURL(ruleset.SourceURL); err != nil {
+		return nil, err
+	}
+
 	// Set timestamps
-	now := time.Now()
+	now := s.opts.Clock()
 	ruleset.CreatedAt = now
 	ruleset.LastModified = now
+	ruleset.Version = 1
+	ruleset.ImportedAt = time.Time{}
+	if ruleset.SourceURL != "" {
+		ruleset.ImportedAt = now
+	}
 
-	// Prepare hash fields
-	key := fmt.Sprintf("ruleset:%s", ruleset.Name)
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
+	return s.encode(ruleset, false)
+}
 
+// encode returns every hash field of a complete ruleset. When replacing an
+// existing hash, replace also clears fields the ruleset leaves unset.
+func (s *Service) encode(ruleset *Ruleset, replace bool) (map[string]string, error) {
 	// Encode tags as JSON
 	tagsJSON, err := json.Marshal(ruleset.Tags)
 	if err != nil {
-		return fmt.Errorf("failed to encode tags: %w", err)
+		return nil, fmt.Errorf("failed to encode tags: %w", err)
 	}
 
-	// Store ruleset in Valkey hash
 	fields := map[string]string{
 		"description":   ruleset.Description,
 		"tags":          string(tagsJSON),
-		"markdown":      ruleset.Markdown,
+		"content_type":  ruleset.ContentType,
+		"license":       ruleset.License,
 		"created_at":    validation.FormatTimestamp(ruleset.CreatedAt),
 		"last_modified": validation.FormatTimestamp(ruleset.LastModified),
+		"version":       strconv.FormatInt(ruleset.Version, 10),
+		"source_url":    ruleset.SourceURL,
+		"imported_at":   formatOptionalTimestamp(ruleset.ImportedAt),
+		"owner":         ruleset.Owner,
+		"team":          ruleset.Team,
 	}
-
-	_, err = client.HSet(ctx, key, fields)
-	if err != nil {
-		return fmt.Errorf("failed to create ruleset: %w", err)
+	if err := s.setBody(fields, ruleset.Markdown, replace); err != nil {
+		return nil, err
 	}
-
-	return nil
+	return fields, nil
 }
 
 // Get retrieves a ruleset by exact name from Valkey
@@ -134,12 +180,17 @@ func (s *Service) Get(name string) (*Ruleset, error) {
 		return nil, err
 	}
 
-	key := fmt.Sprintf("ruleset:%s", name)
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
+	// Serve from cache when available
+	var generation cacheGeneration
+	if s.opts.Cache != nil {
+		if cached, ok := s.opts.Cache.Get(name); ok {
+			return cached, nil
+		}
+		generation = s.generations.current(name)
+	}
 
 	// Retrieve all hash fields
-	result, err := client.HGetAll(ctx, key)
+	result, err := s.storage.HGetAll(s.ctx, s.key(name))
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve ruleset: %w", err)
 	}
@@ -149,7 +200,35 @@ func (s *Service) Get(name string) (*Ruleset, error) {
 		return nil, fmt.Errorf("ruleset '%s' not found", name)
 	}
 
-	// Parse hash fields into Ruleset struct
+	ruleset, err := s.decodeRuleset(name, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.opts.Cache != nil {
+		s.generations.setIfCurrent(s.opts.Cache, ruleset, generation)
+	}
+
+	return ruleset, nil
+}
+
+// lookup reads a ruleset directly from storage, bypassing the cache.
+// It returns nil without an error when the ruleset does not exist.
+func (s *Service) lookup(name string) (*Ruleset, error) {
+	result, err := s.storage.HGetAll(s.ctx, s.key(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ruleset: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return s.decodeRuleset(name, result)
+}
+
+// decodeRuleset parses Valkey hash fields into a Ruleset struct.
+// In strict mode the first malformed field aborts with a *ParseError; in
+// lenient mode malformed fields are logged and left at their zero value.
+func (s *Service) decodeRuleset(name string, result map[string]string) (*Ruleset, error) {
 	ruleset := &Ruleset{
 		Name: name,
 	}
@@ -162,19 +241,46 @@ func (s *Service) Get(name string) (*Ruleset, error) {
 	if tagsJSON, ok := result["tags"]; ok {
 		var tags []string
 		if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
-			return nil, fmt.Errorf("failed to parse tags: %w", err)
+			if failure := s.parseFailure(name, "tags", err); failure != nil {
+				return nil, failure
+			}
+		} else {
+			ruleset.Tags = tags
 		}
-		ruleset.Tags = tags
+	}
+
+	// Rulesets stored before content types existed are markdown
+	ruleset.ContentType = ContentTypeMarkdown
+	if contentType, ok := result["content_type"]; ok && contentType != "" {
+		ruleset.ContentType = contentType
+	}
+
+	if license, ok := result["license"]; ok {
+		ruleset.License = license
 	}
 
 	if markdown, ok := result["markdown"]; ok {
 		ruleset.Markdown = markdown
 	}
 
+	// Bodies in blob storage are referenced by digest instead of stored inline
+	if digest, ok := result["blob"]; ok && digest != "" {
+		markdown, err := s.loadBlob(digest)
+		if err != nil {
+			if failure := s.parseFailure(name, "blob", err); failure != nil {
+				return nil, failure
+			}
+		} else {
+			ruleset.Markdown = markdown
+		}
+	}
+
 	if createdAtStr, ok := result["created_at"]; ok {
 		createdAt, err := validation.ParseTimestamp(createdAtStr)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+			if failure := s.parseFailure(name, "created_at", err); failure != nil {
+				return nil, failure
+			}
 		}
 		ruleset.CreatedAt = createdAt
 	}
@@ -182,28 +288,71 @@ func (s *Service) Get(name string) (*Ruleset, error) {
 	if lastModifiedStr, ok := result["last_modified"]; ok {
 		lastModified, err := validation.ParseTimestamp(lastModifiedStr)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse last_modified: %w", err)
+			if failure := s.parseFailure(name, "last_modified", err); failure != nil {
+				return nil, failure
+			}
 		}
 		ruleset.LastModified = lastModified
 	}
 
+	// Rulesets stored before versioning existed start at version 1
+	ruleset.Version = 1
+	if versionStr, ok := result["version"]; ok && versionStr != "" {
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			if failure := s.parseFailure(name, "version", err); failure != nil {
+				return nil, failure
+			}
+		} else {
+			ruleset.Version = version
+		}
+	}
+
+	if sourceURL, ok := result["source_url"]; ok {
+		ruleset.SourceURL = sourceURL
+	}
+
+	if importedAtStr, ok := result["imported_at"]; ok && importedAtStr != "" {
+		importedAt, err := validation.ParseTimestamp(importedAtStr)
+		if err != nil {
+			if failure := s.parseFailure(name, "imported_at", err); failure != nil {
+				return nil, failure
+			}
+		}
+		ruleset.ImportedAt = importedAt
+	}
+
+	ruleset.Owner = result["owner"]
+	ruleset.Team = result["team"]
+
 	return ruleset, nil
 }
 
-// List retrieves all rulesets with metadata from Valkey
-func (s *Service) List() ([]*Ruleset, error) {
-	// Get all ruleset names
-	names, err := s.ListNames()
-	if err != nil {
-		return nil, err
+// parseFailure applies the configured parse mode to a malformed field.
+// It returns a *ParseError in strict mode and nil (after logging) in lenient mode.
+func (s *Service) parseFailure(name, field string, err error) error {
+	if s.opts.ParseMode == ParseModeLenient {
+		log.Warn().Err(err).Str("ruleset", name).Str("field", field).Msg("Ignoring malformed ruleset field")
+		return nil
 	}
+	return &ParseError{Name: name, Field: field, Err: err}
+}
 
-	// Retrieve each ruleset
+// collect retrieves full rulesets for the given names, skipping entries that
+// vanished since they were listed. Malformed entries fail the call in strict
+// mode and are skipped with a warning in lenient mode.
+func (s *Service) collect(names []string) ([]*Ruleset, error) {
 	rulesets := make([]*Ruleset, 0, len(names))
 	for _, name := range names {
 		ruleset, err := s.Get(name)
 		if err != nil {
-			// Skip rulesets that can't be retrieved (shouldn't happen, but be defensive)
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				if s.opts.ParseMode != ParseModeLenient {
+					return nil, err
+				}
+				log.Warn().Err(err).Str("ruleset", name).Msg("Skipping malformed ruleset")
+			}
 			continue
 		}
 		rulesets = append(rulesets, ruleset)
@@ -212,57 +361,81 @@ func (s *Service) List() ([]*Ruleset, error) {
 	return rulesets, nil
 }
 
+// List retrieves all rulesets with metadata from Valkey
+func (s *Service) List() ([]*Ruleset, error) {
+	// Get all ruleset names
+	names, err := s.ListNames()
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve each ruleset
+	return s.collect(names)
+}
+
 // Search searches for rulesets matching a glob pattern
 func (s *Service) Search(pattern string) ([]*Ruleset, error) {
 	if pattern == "" {
 		return nil, fmt.Errorf("search pattern cannot be empty")
 	}
 
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
+	return s.Find(Filter{Pattern: pattern})
+}
 
-	// Build the full key pattern for KEYS command
-	keyPattern := fmt.Sprintf("ruleset:%s", pattern)
+// Find retrieves the rulesets selected by filter
+func (s *Service) Find(filter Filter) ([]*Ruleset, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
 
-	// Use SCAN with pattern matching
-	cursor := models.NewCursor()
-	matchingNames := make([]string, 0)
+	pattern := filter.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
 
-	for {
-		result, err := client.Scan(ctx, cursor)
-		if err != nil {
-			return nil, fmt.Errorf("failed to search rulesets: %w", err)
-		}
+	// Build the full key pattern to match against
+	keyPattern := s.opts.KeyPrefix + pattern
 
-		// Filter keys that match our pattern and extract names
-		for _, key := range result.Data {
-			if len(key) > 8 && key[:8] == "ruleset:" {
-				// Simple pattern matching - check if key matches the pattern
-				if matchesPattern(key, keyPattern) {
-					name := key[8:]
-					matchingNames = append(matchingNames, name)
-				}
-			}
-		}
+	keys, err := s.scanKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search rulesets: %w", err)
+	}
 
-		cursor = result.Cursor
-		if cursor.IsFinished() {
-			break
+	// Narrow date-range searches with the timestamp indexes
+	indexed, err := s.indexedNames(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter keys that match our pattern and extract names
+	matchingNames := make([]string, 0)
+	for _, key := range keys {
+		if name, ok := strings.CutPrefix(key, s.opts.KeyPrefix); ok && name != "" {
+			if indexed != nil && !indexed[name] {
+				continue
+			}
+			// Simple pattern matching - check if key matches the pattern
+			if matchesPattern(key, keyPattern) {
+				matchingNames = append(matchingNames, name)
+			}
 		}
 	}
 
 	// Retrieve full rulesets for matching names
-	rulesets := make([]*Ruleset, 0, len(matchingNames))
-	for _, name := range matchingNames {
-		ruleset, err := s.Get(name)
-		if err != nil {
-			// Skip rulesets that can't be retrieved
-			continue
+	rulesets, err := s.collect(matchingNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply metadata filters
+	matching := make([]*Ruleset, 0, len(rulesets))
+	for _, rs := range rulesets {
+		if filter.matches(rs) {
+			matching = append(matching, rs)
 		}
-		rulesets = append(rulesets, ruleset)
 	}
 
-	return rulesets, nil
+	return matching, nil
 }
 
 // Update updates an existing ruleset with the provided fields
@@ -272,21 +445,64 @@ func (s *Service) Update(name string, updates *Update) error {
 		return err
 	}
 
-	// Check if ruleset exists
-	exists, err := s.Exists(name)
+	current, err := s.lookup(name)
 	if err != nil {
 		return err
 	}
 
-	if !exists {
+	if current == nil {
 		return fmt.Errorf("ruleset '%s' not found", name)
 	}
 
-	// Prepare fields to update
-	key := fmt.Sprintf("ruleset:%s", name)
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
+	previous := *current
+	fields, err := s.prepareUpdate(current, updates)
+	if err != nil {
+		return err
+	}
+
+	// If no fields to update, return early
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// Write only if the key still exists so a concurrent delete is not undone
+	existed, err := s.storage.UpsertHash(s.ctx, s.key(name), nil, fields)
+	if err != nil {
+		return fmt.Errorf("failed to update ruleset: %w", err)
+	}
 
+	if !existed {
+		return fmt.Errorf("ruleset '%s' not found", name)
+	}
+
+	s.afterUpdate(&previous, current)
+	return nil
+}
+
+// prepareUpdate validates updates against the current ruleset and returns the hash fields to write.
+// An empty result means the updates change nothing; otherwise it bumps version and last_modified.
+func (s *Service) prepareUpdate(current *Ruleset, updates *Update) (map[string]string, error) {
+	// Imported content and its source may only be changed by forcing
+	changesSource := updates.SourceURL != nil && *updates.SourceURL != current.SourceURL
+	if current.SourceURL != "" && !updates.Force && (changesSource || changesContent(updates)) {
+		return nil, importedError(current)
+	}
+	if updates.SourceURL != nil {
+		if err := validateSourceURL(*updates.SourceURL); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkStewardship(current, updates); err != nil {
+		return nil, err
+	}
+
+	// Enforce content type, size limits and custom validators on the updated ruleset
+	applyUpdate(current, updates)
+	if err := s.validate(current); err != nil {
+		return nil, err
+	}
+
+	// Prepare fields to update
 	fields := make(map[string]string)
 
 	// Update only provided fields
@@ -297,64 +513,124 @@ func (s *Service) Update(name string, updates *Update) error {
 	if updates.Tags != nil {
 		tagsJSON, err := json.Marshal(*updates.Tags)
 		if err != nil {
-			return fmt.Errorf("failed to encode tags: %w", err)
+			return nil, fmt.Errorf("failed to encode tags: %w", err)
 		}
 		fields["tags"] = string(tagsJSON)
 	}
 
+	if updates.ContentType != nil {
+		fields["content_type"] = *updates.ContentType
+	}
+
+	if updates.License != nil {
+		fields["license"] = *updates.License
+	}
+
 	if updates.Markdown != nil {
-		fields["markdown"] = *updates.Markdown
+		if err := s.setBody(fields, *updates.Markdown, true); err != nil {
+			return nil, err
+		}
 	}
 
-	// Always update last_modified timestamp
-	fields["last_modified"] = validation.FormatTimestamp(time.Now())
+	if updates.Owner != nil {
+		fields["owner"] = *updates.Owner
+	}
 
-	// If no fields to update, return early
-	if len(fields) == 1 { // Only last_modified
-		return nil
+	if updates.Team != nil {
+		fields["team"] = *updates.Team
 	}
 
-	// Update the hash in Valkey
-	_, err = client.HSet(ctx, key, fields)
-	if err != nil {
-		return fmt.Errorf("failed to update ruleset: %w", err)
+	now := s.opts.Clock()
+	if updates.SourceURL != nil {
+		fields["source_url"] = *updates.SourceURL
+		fields["imported_at"] = ""
+		if *updates.SourceURL != "" {
+			fields["imported_at"] = validation.FormatTimestamp(now)
+		}
 	}
 
-	return nil
+	if len(fields) == 0 {
+		return fields, nil
+	}
+
+	current.Version++
+	current.LastModified = now
+	fields["version"] = strconv.FormatInt(current.Version, 10)
+	fields["last_modified"] = validation.FormatTimestamp(now)
+
+	return fields, nil
 }
 
-// Upsert creates a new ruleset or updates an existing one
+// maxUpsertAttempts bounds retries when a ruleset is created or deleted concurrently with an Upsert
+const maxUpsertAttempts = 3
+
+// Upsert atomically creates a new ruleset or updates an existing one and reports whether it was created.
 // For new rulesets, all fields in rs must be provided (name, description, markdown)
 // For existing rulesets, only fields in updates that are non-nil will be updated
-func (s *Service) Upsert(rs *Ruleset, updates *Update) error {
+func (s *Service) Upsert(rs *Ruleset, updates *Update) (bool, error) {
 	// Validate ruleset name
 	if err := validation.ValidateRulesetName(rs.Name); err != nil {
-		return err
+		return false, err
 	}
 
-	// Check if ruleset exists
-	exists, err := s.Exists(rs.Name)
-	if err != nil {
-		return err
-	}
+	for attempt := 0; attempt < maxUpsertAttempts; attempt++ {
+		current, err := s.lookup(rs.Name)
+		if err != nil {
+			return false, err
+		}
 
-	if !exists {
-		// Create new ruleset - all fields must be provided
-		if rs.Description == "" {
-			return fmt.Errorf("description is required for new rulesets")
+		// Prepare only the branch matching the observed state. The storage
+		// decides atomically which branch applies, so if the ruleset was
+		// created or deleted in the meantime nothing is written and we retry.
+		var create, update map[string]string
+		var previous Ruleset
+		if current == nil {
+			// Create new ruleset - all fields must be provided
+			if rs.Description == "" {
+				return false, fmt.Errorf("description is required for new rulesets")
+			}
+			if rs.Markdown == "" {
+				return false, fmt.Errorf("markdown content is required for new rulesets")
+			}
+			if create, err = s.prepareCreate(rs); err != nil {
+				return false, err
+			}
+		} else {
+			previous = *current
+			if update, err = s.prepareUpdate(current, updates); err != nil {
+				return false, err
+			}
+			if len(update) == 0 {
+				return false, nil
+			}
 		}
-		if rs.Markdown == "" {
-			return fmt.Errorf("markdown content is required for new rulesets")
+
+		existed, err := s.storage.UpsertHash(s.ctx, s.key(rs.Name), create, update)
+		if err != nil {
+			return false, fmt.Errorf("failed to upsert ruleset: %w", err)
+		}
+
+		switch {
+		case !existed && create != nil:
+			s.afterCreate(rs)
+			return true, nil
+		case existed && update != nil:
+			s.afterUpdate(&previous, current)
+			return false, nil
 		}
-		return s.Create(rs)
 	}
 
-	// Update existing ruleset
-	return s.Update(rs.Name, updates)
+	return false, fmt.Errorf("ruleset '%s' was modified concurrently, please retry", rs.Name)
 }
 
-// Delete removes a ruleset from Valkey by name
+// Delete removes a ruleset from Valkey by name.
+// Rulesets imported from an external source are only deleted by DeleteWithOptions with Force.
 func (s *Service) Delete(name string) error {
+	return s.DeleteWithOptions(name, DeleteOptions{})
+}
+
+// DeleteWithOptions removes a ruleset from Valkey by name
+func (s *Service) DeleteWithOptions(name string, opts DeleteOptions) error {
 	// Validate ruleset name
 	if err := validation.ValidateRulesetName(name); err != nil {
 		return err
@@ -375,19 +651,176 @@ func (s *Service) Delete(name string) error {
 		return fmt.Errorf("ruleset '%s' not found. Existing rulesets: %v", name, existingNames)
 	}
 
-	// Delete the ruleset from Valkey
-	key := fmt.Sprintf("ruleset:%s", name)
-	ctx := s.valkeyClient.GetContext()
-	client := s.valkeyClient.GetClient()
+	if !opts.Force {
+		current, err := s.Get(name)
+		if err != nil {
+			return err
+		}
+		if current.SourceURL != "" {
+			return importedError(current)
+		}
+	}
 
-	_, err = client.Del(ctx, []string{key})
-	if err != nil {
+	// Delete the ruleset from Valkey
+	if err := s.storage.Del(s.ctx, s.key(name)); err != nil {
 		return fmt.Errorf("failed to delete ruleset: %w", err)
 	}
 
+	s.invalidate(name)
+	s.unindex(name)
+	if s.opts.Hooks.AfterDelete != nil {
+		s.opts.Hooks.AfterDelete(name)
+	}
+
 	return nil
 }
 
+// validate enforces content type, the configured limits and validators on a complete ruleset
+func (s *Service) validate(rs *Ruleset) error {
+	if err := validateContent(rs.ContentType, rs.Markdown); err != nil {
+		return err
+	}
+	if rs.License != "" {
+		if err := validation.ValidateLicense(rs.License); err != nil {
+			return err
+		}
+	}
+	if rs.Owner != "" {
+		if err := validation.ValidatePrincipal("owner", rs.Owner); err != nil {
+			return err
+		}
+	}
+	if rs.Team != "" {
+		if err := validation.ValidatePrincipal("team", rs.Team); err != nil {
+			return err
+		}
+	}
+	if err := s.opts.Limits.check(rs); err != nil {
+		return err
+	}
+	if err := validateText(rs.Markdown); err != nil {
+		return err
+	}
+	for _, validator := range s.opts.Validators {
+		if err := validator(rs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changesContent reports whether updates modify any field other than provenance
+func changesContent(updates *Update) bool {
+	return updates.Description != nil || updates.Tags != nil || updates.ContentType != nil ||
+		updates.License != nil || updates.Markdown != nil
+}
+
+// importedError reports an attempt to modify an imported ruleset without force
+func importedError(rs *Ruleset) error {
+	return fmt.Errorf("ruleset '%s' is imported from %s; use force to modify it", rs.Name, rs.SourceURL)
+}
+
+// checkStewardship guards owner and team changes: reassigning an owned ruleset
+// requires naming its current owner, ignoring case, or forcing
+func checkStewardship(current *Ruleset, updates *Update) error {
+	if updates.Owner == nil && updates.Team == nil {
+		return nil
+	}
+	if current.Owner != "" && !updates.Force && !strings.EqualFold(updates.CurrentOwner, current.Owner) {
+		return fmt.Errorf("ruleset '%s' is owned by '%s'; name them as current owner to reassign it", current.Name, current.Owner)
+	}
+	return nil
+}
+
+// validateSourceURL checks that a non-empty source URL is absolute
+func validateSourceURL(sourceURL string) error {
+	if sourceURL == "" {
+		return nil
+	}
+	u, err := url.Parse(sourceURL)
+	if err != nil || u.Scheme == "" {
+		return fmt.Errorf("source URL must be an absolute URL: %s", sourceURL)
+	}
+	return nil
+}
+
+// formatOptionalTimestamp formats t, or returns an empty string for the zero time
+func formatOptionalTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return validation.FormatTimestamp(t)
+}
+
+// afterCreate invalidates the cache, indexes the ruleset and runs the create hook
+func (s *Service) afterCreate(rs *Ruleset) {
+	s.invalidate(rs.Name)
+	s.indexWrite(rs)
+	if s.opts.Hooks.AfterCreate != nil {
+		s.opts.Hooks.AfterCreate(rs)
+	}
+}
+
+// afterUpdate invalidates the cache, reindexes the updated ruleset and runs the update hook
+func (s *Service) afterUpdate(previous, updated *Ruleset) {
+	s.invalidate(updated.Name)
+	s.indexWrite(updated)
+	if s.opts.Hooks.AfterUpdate != nil {
+		s.opts.Hooks.AfterUpdate(previous, updated)
+	}
+}
+
+// FlushCache drops every cached ruleset so the next reads go to storage.
+// It reports whether a cache is configured.
+func (s *Service) FlushCache() bool {
+	if s.opts.Cache == nil {
+		return false
+	}
+	s.generations.clear(s.opts.Cache)
+	return true
+}
+
+// InvalidateCache drops a ruleset from the cache, e.g. after another server
+// instance modified it
+func (s *Service) InvalidateCache(name string) {
+	s.invalidate(name)
+}
+
+// invalidate drops a ruleset from the cache after a mutation
+func (s *Service) invalidate(name string) {
+	if s.opts.Cache != nil {
+		s.generations.delete(s.opts.Cache, name)
+	}
+}
+
+// applyUpdate copies the non-nil fields of updates onto rs
+func applyUpdate(rs *Ruleset, updates *Update) {
+	if updates.Description != nil {
+		rs.Description = *updates.Description
+	}
+	if updates.Tags != nil {
+		rs.Tags = *updates.Tags
+	}
+	if updates.ContentType != nil {
+		rs.ContentType = *updates.ContentType
+	}
+	if updates.License != nil {
+		rs.License = *updates.License
+	}
+	if updates.Markdown != nil {
+		rs.Markdown = *updates.Markdown
+	}
+	if updates.SourceURL != nil {
+		rs.SourceURL = *updates.SourceURL
+	}
+	if updates.Owner != nil {
+		rs.Owner = *updates.Owner
+	}
+	if updates.Team != nil {
+		rs.Team = *updates.Team
+	}
+}
+
 // matchesPattern performs simple glob pattern matching
 // Supports * (any characters) and ? (single character)
 func matchesPattern(text, pattern string) bool {
@@ -3,9 +3,13 @@ package ruleset
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jbrinkman/archivyr/internal/memstore"
 	"github.com/jbrinkman/archivyr/internal/valkey"
 
 	"github.com/stretchr/testify/assert"
@@ -49,6 +53,12 @@ func setupTestValkey(t *testing.T) (*valkey.Client, func()) {
 	return client, cleanup
 }
 
+// newMemoryService creates a service backed by an in-memory store for unit tests
+func newMemoryService(opts ...Option) (*Service, *memstore.Store) {
+	store := memstore.New()
+	return NewService(store, opts...), store
+}
+
 func TestCreate_Success(t *testing.T) {
 	client, cleanup := setupTestValkey(t)
 	defer cleanup()
@@ -281,6 +291,53 @@ func TestGet_InvalidName(t *testing.T) {
 	assert.Nil(t, retrieved)
 }
 
+func TestGet_MalformedData(t *testing.T) {
+	client, cleanup := setupTestValkey(t)
+	defer cleanup()
+
+	// Store a ruleset with corrupted tags and timestamp fields
+	_, err := client.GetClient().HSet(client.GetContext(), "ruleset:corrupt_test", map[string]string{
+		"description":   "Corrupted ruleset",
+		"tags":          "not-json",
+		"markdown":      "# Corrupt",
+		"created_at":    "yesterday",
+		"last_modified": "2025-10-29T10:30:00Z",
+	})
+	require.NoError(t, err)
+
+	t.Run("strict mode fails", func(t *testing.T) {
+		service := NewService(client)
+
+		retrieved, err := service.Get("corrupt_test")
+		require.Error(t, err)
+		assert.Nil(t, retrieved)
+		assert.Contains(t, err.Error(), "failed to parse tags")
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "corrupt_test", parseErr.Name)
+
+		rulesets, err := service.List()
+		require.Error(t, err)
+		assert.Nil(t, rulesets)
+	})
+
+	t.Run("lenient mode returns best effort", func(t *testing.T) {
+		service := NewService(client, WithParseMode(ParseModeLenient))
+
+		retrieved, err := service.Get("corrupt_test")
+		require.NoError(t, err)
+		assert.Equal(t, "Corrupted ruleset", retrieved.Description)
+		assert.Empty(t, retrieved.Tags)
+		assert.True(t, retrieved.CreatedAt.IsZero())
+		assert.False(t, retrieved.LastModified.IsZero())
+
+		rulesets, err := service.List()
+		require.NoError(t, err)
+		assert.Len(t, rulesets, 1)
+	})
+}
+
 func TestList_Empty(t *testing.T) {
 	client, cleanup := setupTestValkey(t)
 	defer cleanup()
@@ -969,8 +1026,9 @@ func TestUpsert_CreateNewRuleset(t *testing.T) {
 		Markdown:    &ruleset.Markdown,
 	}
 
-	err := service.Upsert(ruleset, updates)
+	created, err := service.Upsert(ruleset, updates)
 	require.NoError(t, err)
+	assert.True(t, created)
 
 	// Verify the ruleset was created
 	exists, err := service.Exists("upsert_new")
@@ -1018,8 +1076,9 @@ func TestUpsert_UpdateExistingRuleset(t *testing.T) {
 		Markdown:    &newMarkdown,
 	}
 
-	err = service.Upsert(ruleset, updates)
+	created, err := service.Upsert(ruleset, updates)
 	require.NoError(t, err)
+	assert.False(t, created)
 
 	// Verify the ruleset was updated
 	retrieved, err := service.Get("upsert_existing")
@@ -1049,7 +1108,7 @@ func TestUpsert_CreateWithMissingDescription(t *testing.T) {
 
 	updates := &Update{}
 
-	err := service.Upsert(ruleset, updates)
+	_, err := service.Upsert(ruleset, updates)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "description is required")
 }
@@ -1068,7 +1127,7 @@ func TestUpsert_CreateWithMissingMarkdown(t *testing.T) {
 
 	updates := &Update{}
 
-	err := service.Upsert(ruleset, updates)
+	_, err := service.Upsert(ruleset, updates)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "markdown content is required")
 }
@@ -1100,7 +1159,7 @@ func TestUpsert_UpdatePartialFields(t *testing.T) {
 		Description: &newDescription,
 	}
 
-	err = service.Upsert(ruleset, updates)
+	_, err = service.Upsert(ruleset, updates)
 	require.NoError(t, err)
 
 	// Verify only description was updated
@@ -1126,7 +1185,810 @@ func TestUpsert_InvalidName(t *testing.T) {
 
 	updates := &Update{}
 
-	err := service.Upsert(ruleset, updates)
+	_, err := service.Upsert(ruleset, updates)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "snake_case")
 }
+
+func TestService_WithOptions(t *testing.T) {
+	fixed := time.Date(2025, 10, 29, 10, 30, 0, 0, time.UTC)
+	var created, deleted []string
+	var updated []string
+
+	service, store := newMemoryService(
+		WithKeyPrefix("team:"),
+		WithClock(func() time.Time { return fixed }),
+		WithCache(NewLRUCache(4)),
+		WithLimits(Limits{MaxMarkdownBytes: 64}),
+		WithValidators(func(rs *Ruleset) error {
+			if len(rs.Tags) == 0 {
+				return fmt.Errorf("at least one tag is required")
+			}
+			return nil
+		}),
+		WithHooks(Hooks{
+			AfterCreate: func(rs *Ruleset) { created = append(created, rs.Name) },
+			AfterUpdate: func(_, rs *Ruleset) { updated = append(updated, rs.Name) },
+			AfterDelete: func(name string) { deleted = append(deleted, name) },
+		}),
+	)
+
+	rs := &Ruleset{
+		Name:        "options_test",
+		Description: "Options test",
+		Tags:        []string{"test"},
+		Markdown:    "# Options",
+	}
+	require.NoError(t, service.Create(rs))
+	assert.Equal(t, fixed, rs.CreatedAt)
+
+	// Stored under the custom prefix
+	exists, err := store.Exists(context.Background(), "team:options_test")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	names, err := service.ListNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"options_test"}, names)
+
+	// Validators and limits reject bad updates
+	noTags := []string{}
+	err = service.Update("options_test", &Update{Tags: &noTags})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least one tag is required")
+
+	tooLarge := string(make([]byte, 65))
+	err = service.Update("options_test", &Update{Markdown: &tooLarge})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeding the limit")
+
+	// Cached reads reflect updates
+	_, err = service.Get("options_test")
+	require.NoError(t, err)
+	newDesc := testUpdatedDescription
+	require.NoError(t, service.Update("options_test", &Update{Description: &newDesc}))
+	retrieved, err := service.Get("options_test")
+	require.NoError(t, err)
+	assert.Equal(t, testUpdatedDescription, retrieved.Description)
+
+	require.NoError(t, service.Delete("options_test"))
+
+	assert.Equal(t, []string{"options_test"}, created)
+	assert.Equal(t, []string{"options_test"}, updated)
+	assert.Equal(t, []string{"options_test"}, deleted)
+}
+
+func TestService_MemoryStorage(t *testing.T) {
+	service, store := newMemoryService(WithKeyPrefix("team*:"))
+
+	rs := &Ruleset{
+		Name:        "memory_test",
+		Description: "Memory test",
+		Tags:        []string{"test"},
+		Markdown:    "# Memory",
+	}
+	require.NoError(t, service.Create(rs))
+
+	// Keys from other prefixes are ignored even when the prefix contains glob characters
+	require.NoError(t, store.HSet(context.Background(), "teamx:other", map[string]string{"description": "x"}))
+
+	names, err := service.ListNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memory_test"}, names)
+
+	matches, err := service.Search("memory_*")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "# Memory", matches[0].Markdown)
+
+	newDesc := testUpdatedDescription
+	require.NoError(t, service.Update("memory_test", &Update{Description: &newDesc}))
+
+	retrieved, err := service.Get("memory_test")
+	require.NoError(t, err)
+	assert.Equal(t, testUpdatedDescription, retrieved.Description)
+
+	require.NoError(t, service.Delete("memory_test"))
+	exists, err := service.Exists("memory_test")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestService_ContentTypes(t *testing.T) {
+	service, store := newMemoryService()
+
+	// Content type defaults to markdown
+	md := &Ruleset{Name: "markdown_doc", Description: "Markdown", Markdown: "# Doc"}
+	require.NoError(t, service.Create(md))
+	assert.Equal(t, ContentTypeMarkdown, md.ContentType)
+
+	// JSON schemas must be valid JSON
+	invalid := &Ruleset{Name: "bad_schema", Description: "Bad", ContentType: ContentTypeJSONSchema, Markdown: "{not json"}
+	err := service.Create(invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not valid JSON")
+
+	schema := &Ruleset{Name: "good_schema", Description: "Good", ContentType: ContentTypeJSONSchema, Markdown: `{"type":"object"}`}
+	require.NoError(t, service.Create(schema))
+
+	retrieved, err := service.Get("good_schema")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSONSchema, retrieved.ContentType)
+
+	// Unsupported content types are rejected
+	unknown := &Ruleset{Name: "binary_blob", Description: "Blob", ContentType: "image/png", Markdown: "x"}
+	err = service.Create(unknown)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported content type")
+
+	// Updating the body of a JSON artifact is validated against its stored type
+	broken := "{"
+	err = service.Update("good_schema", &Update{Markdown: &broken})
+	require.Error(t, err)
+
+	// Switching content type and body together succeeds
+	plain := ContentTypePlainText
+	require.NoError(t, service.Update("good_schema", &Update{ContentType: &plain, Markdown: &broken}))
+
+	// Rulesets stored without a content type read back as markdown
+	require.NoError(t, store.HSet(context.Background(), "ruleset:legacy_doc", map[string]string{"description": "Legacy", "markdown": "# Legacy"}))
+	legacy, err := service.Get("legacy_doc")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeMarkdown, legacy.ContentType)
+}
+
+func TestService_BinaryContent(t *testing.T) {
+	service, _ := newMemoryService()
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{name: "png", body: "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", reason: "PNG image"},
+		{name: "pdf", body: "%PDF-1.7\n", reason: "PDF document"},
+		{name: "invalid utf8", body: "# Doc\n\xc3\x28", reason: "invalid UTF-8"},
+		{name: "nul bytes", body: "# Doc\x00", reason: "NUL bytes"},
+		{name: "control characters", body: "\x01\x02\x03abc", reason: "control characters"},
+		{name: "text", body: "# Doc\n\n\tIndented, ünïcode and a \x1b[1mbold\x1b[0m escape in a long enough line of text.\r\n"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.Create(&Ruleset{Name: fmt.Sprintf("doc_%d", i), Description: "Doc", Markdown: tt.body})
+			if tt.reason == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "content looks like binary data ("+tt.reason+")")
+		})
+	}
+
+	// Updates are guarded too
+	require.NoError(t, service.Create(&Ruleset{Name: "guarded", Description: "Doc", Markdown: "# Doc"}))
+	png := "\x89PNG\r\n\x1a\n"
+	err := service.Update("guarded", &Update{Markdown: &png})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PNG image")
+}
+
+func TestService_Licenses(t *testing.T) {
+	service, _ := newMemoryService()
+
+	require.NoError(t, service.Create(&Ruleset{Name: "mit_rules", Description: "MIT", License: "MIT", Markdown: "# MIT"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "dual_rules", Description: "Dual", License: "Apache-2.0 OR MIT", Markdown: "# Dual"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "apache_rules", Description: "Apache", License: "Apache-2.0", Markdown: "# Apache"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "unlicensed_rules", Description: "None", Markdown: "# None"}))
+
+	// Invalid SPDX expressions are rejected on create and update
+	err := service.Create(&Ruleset{Name: "bad_license", Description: "Bad", License: "Proprietary", Markdown: "# Bad"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown license identifier")
+
+	invalid := "MIT OR"
+	require.Error(t, service.Update("mit_rules", &Update{License: &invalid}))
+
+	retrieved, err := service.Get("dual_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "Apache-2.0 OR MIT", retrieved.License)
+
+	// Filtering matches any identifier in the expression, ignoring case
+	mit, err := service.Find(Filter{License: "mit"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"mit_rules", "dual_rules"}, rulesetNames(mit))
+
+	apache, err := service.Find(Filter{Pattern: "apache_*", License: "Apache-2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apache_rules"}, rulesetNames(apache))
+
+	_, err = service.Find(Filter{License: "Proprietary"})
+	require.Error(t, err)
+
+	// Clearing the license removes it from filter results
+	cleared := ""
+	require.NoError(t, service.Update("mit_rules", &Update{License: &cleared}))
+	mit, err = service.Find(Filter{License: "MIT"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dual_rules"}, rulesetNames(mit))
+}
+
+// rulesetNames returns the names of the given rulesets
+func rulesetNames(rulesets []*Ruleset) []string {
+	names := make([]string, len(rulesets))
+	for i, rs := range rulesets {
+		names[i] = rs.Name
+	}
+	return names
+}
+
+func TestService_Provenance(t *testing.T) {
+	now := time.Date(2025, 10, 29, 10, 0, 0, 0, time.UTC)
+	service, _ := newMemoryService(WithClock(func() time.Time { return now }))
+
+	require.NoError(t, service.Create(&Ruleset{
+		Name:        "community_rules",
+		Description: "Community",
+		Markdown:    "# Upstream",
+		SourceURL:   "https://github.com/example/rules/blob/main/go.md",
+	}))
+	require.NoError(t, service.Create(&Ruleset{Name: "local_rules", Description: "Local", Markdown: "# Local"}))
+
+	imported, err := service.Get("community_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/example/rules/blob/main/go.md", imported.SourceURL)
+	assert.Equal(t, now, imported.ImportedAt)
+
+	local, err := service.Get("local_rules")
+	require.NoError(t, err)
+	assert.Empty(t, local.SourceURL)
+	assert.True(t, local.ImportedAt.IsZero())
+
+	// Relative source URLs are rejected
+	err = service.Create(&Ruleset{Name: "relative_rules", Description: "Relative", Markdown: "# R", SourceURL: "rules/go.md"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "absolute URL")
+
+	// Imported rulesets cannot be edited or deleted without force
+	edited := "# Local edit"
+	err = service.Update("community_rules", &Update{Markdown: &edited})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use force")
+
+	err = service.Delete("community_rules")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use force")
+
+	require.NoError(t, service.Update("community_rules", &Update{Markdown: &edited, Force: true}))
+
+	// Re-importing from a new source requires force and refreshes imported_at
+	now = now.Add(time.Hour)
+	newSource := "https://github.com/example/rules/blob/v2/go.md"
+	err = service.Update("community_rules", &Update{Markdown: &edited, SourceURL: &newSource})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use force")
+	require.NoError(t, service.Update("community_rules", &Update{Markdown: &edited, SourceURL: &newSource, Force: true}))
+	imported, err = service.Get("community_rules")
+	require.NoError(t, err)
+	assert.Equal(t, newSource, imported.SourceURL)
+	assert.Equal(t, now, imported.ImportedAt)
+
+	// Detaching requires force and makes the ruleset locally editable again
+	detached := ""
+	err = service.Update("community_rules", &Update{SourceURL: &detached})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use force")
+	require.NoError(t, service.Update("community_rules", &Update{SourceURL: &detached, Force: true}))
+	require.NoError(t, service.Update("community_rules", &Update{Markdown: &edited}))
+	imported, err = service.Get("community_rules")
+	require.NoError(t, err)
+	assert.True(t, imported.ImportedAt.IsZero())
+
+	newSourceAgain := newSource
+	require.NoError(t, service.Update("community_rules", &Update{SourceURL: &newSourceAgain}))
+	require.NoError(t, service.DeleteWithOptions("community_rules", DeleteOptions{Force: true}))
+	require.NoError(t, service.Delete("local_rules"))
+}
+
+func TestService_ConcurrentUpsert(t *testing.T) {
+	service, _ := newMemoryService()
+
+	const workers = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+		errs    []error
+	)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			description := fmt.Sprintf("Writer %d", i)
+			markdown := fmt.Sprintf("# Writer %d", i)
+			ok, err := service.Upsert(&Ruleset{Name: "shared_rules", Description: description, Markdown: markdown},
+				&Update{Description: &description, Markdown: &markdown})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if ok {
+				created++
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, created)
+
+	got, err := service.Get("shared_rules")
+	require.NoError(t, err)
+	// Fields come from a single writer, never a mix of two
+	assert.Equal(t, "# "+got.Description, got.Markdown)
+}
+
+func TestService_CreateDoesNotOverwrite(t *testing.T) {
+	service, _ := newMemoryService()
+
+	require.NoError(t, service.Create(&Ruleset{Name: "first_rules", Description: "Original", Markdown: "# One"}))
+	err := service.Create(&Ruleset{Name: "first_rules", Description: "Replacement", Markdown: "# Two"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	got, err := service.Get("first_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "Original", got.Description)
+}
+
+func TestService_Versioning(t *testing.T) {
+	service, store := newMemoryService()
+
+	require.NoError(t, service.Create(&Ruleset{Name: "versioned_rules", Description: "V", Markdown: "# One"}))
+	got, err := service.Get("versioned_rules")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.Version)
+	checksum := got.Checksum()
+
+	// Metadata changes bump the version but keep the content checksum
+	description := "Updated"
+	require.NoError(t, service.Update("versioned_rules", &Update{Description: &description}))
+	got, err = service.Get("versioned_rules")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.Version)
+	assert.Equal(t, checksum, got.Checksum())
+
+	markdown := "# Two"
+	_, err = service.Upsert(&Ruleset{Name: "versioned_rules"}, &Update{Markdown: &markdown})
+	require.NoError(t, err)
+	got, err = service.Get("versioned_rules")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version)
+	assert.NotEqual(t, checksum, got.Checksum())
+
+	// No-op updates leave the version alone
+	require.NoError(t, service.Update("versioned_rules", &Update{}))
+	got, err = service.Get("versioned_rules")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version)
+
+	// Rulesets stored before versioning read as version 1
+	require.NoError(t, store.HSet(context.Background(), "ruleset:legacy_rules", map[string]string{
+		"description": "Legacy",
+		"markdown":    "# Legacy",
+	}))
+	got, err = service.Get("legacy_rules")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.Version)
+}
+
+func TestService_FindByDate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service, store := newMemoryService(WithClock(func() time.Time { return now }))
+
+	require.NoError(t, service.Create(&Ruleset{Name: "old_rules", Description: "Old", Markdown: "# Old"}))
+	now = now.Add(48 * time.Hour)
+	require.NoError(t, service.Create(&Ruleset{Name: "new_rules", Description: "New", Markdown: "# New"}))
+	now = now.Add(48 * time.Hour)
+	description := "Touched"
+	require.NoError(t, service.Update("old_rules", &Update{Description: &description}))
+
+	names := func(filter Filter) []string {
+		rulesets, err := service.Find(filter)
+		require.NoError(t, err)
+		result := make([]string, 0, len(rulesets))
+		for _, rs := range rulesets {
+			result = append(result, rs.Name)
+		}
+		return result
+	}
+
+	march2 := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	march4 := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"new_rules"}, names(Filter{CreatedAfter: march2}))
+	assert.ElementsMatch(t, []string{"new_rules", "old_rules"}, names(Filter{ModifiedAfter: march2}))
+	assert.Equal(t, []string{"old_rules"}, names(Filter{ModifiedAfter: march4}))
+	assert.Equal(t, []string{"new_rules"}, names(Filter{ModifiedAfter: march2, ModifiedBefore: march4}))
+	assert.Equal(t, []string{"new_rules"}, names(Filter{Pattern: "new_*", ModifiedAfter: march2}))
+
+	// Bounds are exclusive
+	assert.Empty(t, names(Filter{ModifiedAfter: now}))
+
+	_, err := service.Find(Filter{ModifiedAfter: march4, ModifiedBefore: march2})
+	require.Error(t, err)
+
+	// Deleted rulesets leave the indexes
+	require.NoError(t, service.Delete("new_rules"))
+	members, err := store.ZRangeByScore(context.Background(), "index:ruleset:created", math.Inf(-1), math.Inf(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old_rules"}, members)
+}
+
+func TestService_RebuildIndexes(t *testing.T) {
+	service, store := newMemoryService()
+	ctx := context.Background()
+
+	// A ruleset stored before indexing existed and a stale index entry
+	require.NoError(t, store.HSet(ctx, "ruleset:legacy_rules", map[string]string{
+		"description":   "Legacy",
+		"markdown":      "# Legacy",
+		"created_at":    "2024-01-01T00:00:00Z",
+		"last_modified": "2024-01-02T00:00:00Z",
+	}))
+	require.NoError(t, store.ZAdd(ctx, "index:ruleset:modified", "gone_rules", 1))
+
+	found, err := service.Find(Filter{ModifiedAfter: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	count, err := service.RebuildIndexes()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	found, err = service.Find(Filter{ModifiedAfter: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "legacy_rules", found[0].Name)
+
+	members, err := store.ZRangeByScore(ctx, "index:ruleset:modified", math.Inf(-1), math.Inf(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy_rules"}, members)
+}
+
+func TestService_FindCombinedFilters(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	service, _ := newMemoryService(WithClock(func() time.Time { return now }))
+
+	for _, rs := range []*Ruleset{
+		{Name: "go_style", Description: "Go", Tags: []string{"go", "style"}, License: "MIT", Markdown: "# Go"},
+		{Name: "go_testing", Description: "Go tests", Tags: []string{"Go", "testing"}, License: "Apache-2.0", Markdown: "# Tests"},
+		{Name: "python_style", Description: "Python", Tags: []string{"python", "style"}, License: "MIT", Markdown: "# Py"},
+	} {
+		require.NoError(t, service.Create(rs))
+		now = now.Add(24 * time.Hour)
+	}
+
+	names := func(filter Filter) []string {
+		rulesets, err := service.Find(filter)
+		require.NoError(t, err)
+		result := make([]string, 0, len(rulesets))
+		for _, rs := range rulesets {
+			result = append(result, rs.Name)
+		}
+		return result
+	}
+
+	assert.ElementsMatch(t, []string{"go_style", "go_testing"}, names(Filter{Tags: []string{"go"}}))
+	assert.Equal(t, []string{"go_style"}, names(Filter{Tags: []string{"go", "style"}}))
+	assert.Equal(t, []string{"python_style"}, names(Filter{Pattern: "*_style", Tags: []string{"style"}, License: "MIT",
+		CreatedAfter: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)}))
+	assert.Empty(t, names(Filter{Pattern: "go_*", Tags: []string{"python"}}))
+	assert.ElementsMatch(t, []string{"go_style", "python_style"}, names(Filter{Text: []string{"STYLE"}}))
+	assert.Equal(t, []string{"go_testing"}, names(Filter{Text: []string{"go", "tests"}}))
+
+	assert.False(t, Filter{Pattern: "go_*"}.HasCriteria())
+	assert.True(t, Filter{Tags: []string{"go"}}.HasCriteria())
+}
+
+func TestService_BlobStorage(t *testing.T) {
+	ctx := context.Background()
+	service, store := newMemoryService(WithBlobStorage())
+
+	body := "# Shared\n\nSame rules in two places."
+	require.NoError(t, service.Create(&Ruleset{Name: "team_a", Description: "A", Markdown: body}))
+	require.NoError(t, service.Create(&Ruleset{Name: "team_b", Description: "B", Markdown: body}))
+
+	// Both hashes reference one blob instead of holding the body
+	blobs, err := store.ScanKeys(ctx, "blob:*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blob:ruleset:" + blobDigest(body)}, blobs)
+
+	fields, err := store.HGetAll(ctx, "ruleset:team_a")
+	require.NoError(t, err)
+	assert.Empty(t, fields["markdown"])
+	assert.Equal(t, blobDigest(body), fields["blob"])
+
+	rs, err := service.Get("team_b")
+	require.NoError(t, err)
+	assert.Equal(t, body, rs.Markdown)
+
+	// Updating the body points the ruleset at a new blob and leaves the shared one intact
+	changed := "# Changed"
+	require.NoError(t, service.Update("team_b", &Update{Markdown: &changed}))
+	rs, err = service.Get("team_b")
+	require.NoError(t, err)
+	assert.Equal(t, changed, rs.Markdown)
+	rs, err = service.Get("team_a")
+	require.NoError(t, err)
+	assert.Equal(t, body, rs.Markdown)
+
+	// Inline bodies written without blob storage stay readable, and move to a blob on update
+	require.NoError(t, store.HSet(ctx, "ruleset:legacy", map[string]string{"description": "Legacy", "markdown": "# Inline"}))
+	rs, err = service.Get("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "# Inline", rs.Markdown)
+
+	inline := NewService(store)
+	require.NoError(t, inline.Update("team_a", &Update{Markdown: &changed}))
+	fields, err = store.HGetAll(ctx, "ruleset:team_a")
+	require.NoError(t, err)
+	assert.Equal(t, changed, fields["markdown"])
+	assert.Empty(t, fields["blob"])
+
+	// A missing blob is a parse failure
+	require.NoError(t, store.HSet(ctx, "ruleset:dangling", map[string]string{"description": "Dangling", "blob": "deadbeef"}))
+	_, err = service.Get("dangling")
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "blob", parseErr.Field)
+}
+
+func TestService_CollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	service, store := newMemoryService(WithBlobStorage(), WithClock(func() time.Time { return now }))
+
+	shared := "# Shared"
+	require.NoError(t, service.Create(&Ruleset{Name: "team_a", Description: "A", Markdown: shared}))
+	require.NoError(t, service.Create(&Ruleset{Name: "team_b", Description: "B", Markdown: shared}))
+	require.NoError(t, service.Create(&Ruleset{Name: "solo", Description: "Solo", Markdown: "# Old body"}))
+
+	// Orphan the solo blob; the shared blob stays referenced by team_a
+	changed := "# New body"
+	require.NoError(t, service.Update("solo", &Update{Markdown: &changed}))
+	require.NoError(t, service.Update("team_b", &Update{Markdown: &changed}))
+
+	// Recently stored blobs are kept
+	report, err := service.CollectGarbage(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, GCReport{BlobsScanned: 3}, report)
+
+	now = now.Add(2 * time.Hour)
+	report, err = service.CollectGarbage(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, GCReport{BlobsScanned: 3, BlobsRemoved: 1, BytesReclaimed: int64(len("# Old body"))}, report)
+
+	blobs, err := store.ScanKeys(ctx, "blob:*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"blob:ruleset:" + blobDigest(shared), "blob:ruleset:" + blobDigest(changed)}, blobs)
+
+	for _, name := range []string{"team_a", "team_b", "solo"} {
+		_, err := service.Get(name)
+		require.NoError(t, err)
+	}
+
+	// Blobs of another key prefix are left alone
+	other := NewService(store, WithKeyPrefix("other:"), WithBlobStorage(), WithClock(func() time.Time { return now }))
+	report, err = other.CollectGarbage(0)
+	require.NoError(t, err)
+	assert.Equal(t, GCReport{}, report)
+}
+
+// blobRacingStorage runs interleave once, right after the first content blob is read
+type blobRacingStorage struct {
+	*memstore.Store
+	interleave func()
+}
+
+func (b *blobRacingStorage) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	result, err := b.Store.HGetAll(ctx, key)
+	if interleave := b.interleave; interleave != nil && strings.HasPrefix(key, "blob:") {
+		b.interleave = nil
+		interleave()
+	}
+	return result, err
+}
+
+func TestService_CollectGarbageKeepsRewrittenBlobs(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	storage := &blobRacingStorage{Store: memstore.New()}
+	service := NewService(storage, WithBlobStorage(), WithClock(func() time.Time { return now }))
+
+	old := "# Old body"
+	changed := "# New body"
+	require.NoError(t, service.Create(&Ruleset{Name: "solo", Description: "Solo", Markdown: old}))
+	require.NoError(t, service.Update("solo", &Update{Markdown: &changed}))
+
+	// The orphaned body is written again between GC reading and deleting its blob
+	now = now.Add(2 * time.Hour)
+	storage.interleave = func() {
+		require.NoError(t, service.Create(&Ruleset{Name: "revived", Description: "Revived", Markdown: old}))
+	}
+
+	report, err := service.CollectGarbage(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, GCReport{BlobsScanned: 2}, report)
+
+	revived, err := service.Get("revived")
+	require.NoError(t, err)
+	assert.Equal(t, old, revived.Markdown)
+}
+
+func TestService_RebuildIndexesWithProgress(t *testing.T) {
+	service, _ := newMemoryService(WithCache(NewLRUCache(10)))
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		require.NoError(t, service.Create(&Ruleset{Name: name, Description: "Doc", Markdown: "# Doc"}))
+	}
+
+	var reports [][2]int
+	count, err := service.RebuildIndexesWithProgress(func(done, total int) {
+		reports = append(reports, [2]int{done, total})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, [][2]int{{1, 3}, {2, 3}, {3, 3}}, reports)
+
+	assert.True(t, service.FlushCache())
+	assert.False(t, NewService(memstore.New()).FlushCache())
+}
+
+func TestService_Restore(t *testing.T) {
+	ctx := context.Background()
+	service, store := newMemoryService()
+
+	createdAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	modifiedAt := createdAt.Add(48 * time.Hour)
+	created, err := service.Restore(&Ruleset{
+		Name:         "restored",
+		Description:  "From a snapshot",
+		Tags:         []string{"backup"},
+		Markdown:     "# Restored",
+		CreatedAt:    createdAt,
+		LastModified: modifiedAt,
+		Version:      7,
+	})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// Timestamps and version are kept as given
+	rs, err := service.Get("restored")
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(rs.CreatedAt))
+	assert.True(t, modifiedAt.Equal(rs.LastModified))
+	assert.Equal(t, int64(7), rs.Version)
+
+	found, err := service.Find(Filter{Pattern: "*", Tags: []string{"backup"}})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// Restoring again replaces the ruleset and clears a stale blob reference
+	require.NoError(t, store.HSet(ctx, "ruleset:restored", map[string]string{"blob": "deadbeef"}))
+	created, err = service.Restore(&Ruleset{Name: "restored", Description: "Replaced", Markdown: "# Replaced"})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	rs, err = service.Get("restored")
+	require.NoError(t, err)
+	assert.Equal(t, "# Replaced", rs.Markdown)
+	assert.Equal(t, int64(1), rs.Version)
+	assert.Equal(t, rs.CreatedAt, rs.LastModified)
+
+	_, err = service.Restore(&Ruleset{Name: "Bad Name", Markdown: "# Bad"})
+	require.Error(t, err)
+}
+
+func TestService_Stewardship(t *testing.T) {
+	service, _ := newMemoryService()
+	require.NoError(t, service.Create(&Ruleset{Name: "go_rules", Description: "Go", Markdown: "# Go", Owner: "alice", Team: "platform"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "py_rules", Description: "Python", Markdown: "# Python"}))
+
+	bob := "bob"
+	err := service.Update("go_rules", &Update{Owner: &bob})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is owned by 'alice'")
+
+	require.NoError(t, service.Update("go_rules", &Update{Owner: &bob, CurrentOwner: "ALICE"}))
+	team := "data"
+	require.NoError(t, service.Update("go_rules", &Update{Team: &team, Force: true}))
+
+	// Content edits are not gated by ownership
+	description := "Go style"
+	require.NoError(t, service.Update("go_rules", &Update{Description: &description}))
+
+	rs, err := service.Get("go_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", rs.Owner)
+	assert.Equal(t, "data", rs.Team)
+	assert.Equal(t, int64(4), rs.Version)
+
+	found, err := service.Find(Filter{Pattern: "*", Owner: "Bob"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "go_rules", found[0].Name)
+
+	found, err = service.Find(Filter{Pattern: "*", Team: "platform"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	err = service.Create(&Ruleset{Name: "bad_owner", Description: "Bad", Markdown: "# Bad", Owner: "not valid"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "owner must be")
+}
+
+func TestService_ApplyChangeset(t *testing.T) {
+	service, _ := newMemoryService()
+	require.NoError(t, service.Create(&Ruleset{Name: "go_rules", Description: "Go", Markdown: "# Go"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "api_rules", Description: "API", Markdown: "See [Go](ruleset://go_rules)"}))
+
+	// Rename go_rules to golang_rules and update the reference
+	golang := &Ruleset{Name: "golang_rules", Description: "Go", Tags: []string{}, Markdown: "# Go"}
+	reference := "See [Go](ruleset://golang_rules)"
+	err := service.ApplyChangeset([]Change{
+		{Kind: ChangeUpsert, Name: "golang_rules", Ruleset: golang, Updates: &Update{}},
+		{Kind: ChangeUpsert, Name: "api_rules", Ruleset: &Ruleset{Name: "api_rules"}, Updates: &Update{Markdown: &reference}},
+		{Kind: ChangeDelete, Name: "go_rules"},
+	})
+	require.NoError(t, err)
+
+	names, err := service.ListNames()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"api_rules", "golang_rules"}, names)
+	api, err := service.Get("api_rules")
+	require.NoError(t, err)
+	assert.Equal(t, reference, api.Markdown)
+	assert.Equal(t, int64(2), api.Version)
+}
+
+func TestService_ApplyChangesetRollsBack(t *testing.T) {
+	var events []string
+	service, _ := newMemoryService(WithHooks(Hooks{
+		AfterCreate: func(rs *Ruleset) { events = append(events, "created "+rs.Name) },
+		AfterUpdate: func(_, rs *Ruleset) { events = append(events, "updated "+rs.Name) },
+		AfterDelete: func(name string) { events = append(events, "deleted "+name) },
+	}))
+	require.NoError(t, service.Create(&Ruleset{Name: "go_rules", Description: "Go", Markdown: "# Go"}))
+	require.NoError(t, service.Create(&Ruleset{Name: "api_rules", Description: "API", Markdown: "# API"}))
+	events = nil
+
+	edited := "# API v2"
+	err := service.ApplyChangeset([]Change{
+		{Kind: ChangeUpsert, Name: "golang_rules", Ruleset: &Ruleset{Name: "golang_rules", Description: "Go", Tags: []string{}, Markdown: "# Go"}, Updates: &Update{}},
+		{Kind: ChangeUpsert, Name: "api_rules", Ruleset: &Ruleset{Name: "api_rules"}, Updates: &Update{Markdown: &edited}},
+		{Kind: ChangeDelete, Name: "go_rules"},
+		{Kind: ChangeDelete, Name: "missing_rules"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "change 4 (delete missing_rules) failed: ruleset 'missing_rules' not found")
+	assert.Contains(t, err.Error(), "earlier changes were rolled back")
+
+	// Every ruleset is back to its state before the changeset
+	names, err := service.ListNames()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"api_rules", "go_rules"}, names)
+	api, err := service.Get("api_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "# API", api.Markdown)
+	assert.Equal(t, int64(1), api.Version)
+	goRules, err := service.Get("go_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "# Go", goRules.Markdown)
+
+	// Subscribers see the changes and their rollback
+	assert.Equal(t, []string{
+		"created golang_rules", "updated api_rules", "deleted go_rules",
+		"deleted golang_rules", "updated api_rules", "created go_rules",
+	}, events)
+}
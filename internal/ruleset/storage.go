@@ -0,0 +1,33 @@
+package ruleset
+
+import (
+	"context"
+	"strings"
+)
+
+// Storage is the narrow set of key-value commands the Service depends on.
+// It is implemented by *valkey.Client and can be satisfied by fakes or
+// alternate clients.
+type Storage interface {
+	Exists(ctx context.Context, key string) (bool, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HSet(ctx context.Context, key string, fields map[string]string) error
+	Del(ctx context.Context, keys ...string) error
+	ScanKeys(ctx context.Context, match string) ([]string, error)
+	// UpsertHash atomically sets create on a missing key or update on an existing one
+	// and reports whether the key existed. A nil map leaves the key untouched in that case.
+	UpsertHash(ctx context.Context, key string, create, update map[string]string) (existed bool, err error)
+	// DelIfField atomically deletes key only while its hash field holds value
+	// and reports whether it was deleted
+	DelIfField(ctx context.Context, key, field, value string) (deleted bool, err error)
+	// ZAdd adds member to the sorted set at key, or updates its score
+	ZAdd(ctx context.Context, key, member string, score float64) error
+	// ZRem removes members from the sorted set at key
+	ZRem(ctx context.Context, key string, members ...string) error
+	// ZRangeByScore returns the members of the sorted set at key with min <= score <= max
+	// in ascending score order. Infinite bounds are unbounded.
+	ZRangeByScore(ctx context.Context, key string, minScore, maxScore float64) ([]string, error)
+}
+
+// globEscaper escapes glob metacharacters so a literal prefix can be used in a SCAN pattern
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
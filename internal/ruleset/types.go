@@ -1,20 +1,140 @@
 package ruleset
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
 
-// Ruleset represents a complete ruleset with all metadata and content
+// Ruleset represents a complete ruleset with all metadata and content.
+// Markdown holds the body regardless of ContentType.
 type Ruleset struct {
 	Name         string    `json:"name"`
 	Description  string    `json:"description"`
 	Tags         []string  `json:"tags"`
+	ContentType  string    `json:"content_type"`
+	License      string    `json:"license,omitempty"`
 	Markdown     string    `json:"markdown"`
 	CreatedAt    time.Time `json:"created_at"`
 	LastModified time.Time `json:"last_modified"`
+	// Version starts at 1 and increases with every modification
+	Version int64 `json:"version"`
+	// SourceURL records where imported content came from; empty for locally authored rulesets
+	SourceURL  string    `json:"source_url,omitempty"`
+	ImportedAt time.Time `json:"imported_at,omitzero"`
+	// Owner and Team record who is responsible for the ruleset; empty when unassigned
+	Owner string `json:"owner,omitempty"`
+	Team  string `json:"team,omitempty"`
+}
+
+// Checksum returns the hex SHA-256 of the ruleset's content type and body,
+// which changes whenever the content served to clients changes
+func (r *Ruleset) Checksum() string {
+	h := sha256.New()
+	h.Write([]byte(r.ContentType))
+	h.Write([]byte{0})
+	h.Write([]byte(r.Markdown))
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // Update represents partial updates to an existing ruleset
 type Update struct {
 	Description *string   `json:"description,omitempty"`
 	Tags        *[]string `json:"tags,omitempty"`
+	ContentType *string   `json:"content_type,omitempty"`
+	License     *string   `json:"license,omitempty"`
 	Markdown    *string   `json:"markdown,omitempty"`
+	// SourceURL re-imports the ruleset from a new source, or detaches it when empty;
+	// changing the source of an imported ruleset requires Force
+	SourceURL *string `json:"source_url,omitempty"`
+	// Owner and Team reassign stewardship; an empty value clears the assignment
+	Owner *string `json:"owner,omitempty"`
+	Team  *string `json:"team,omitempty"`
+	// CurrentOwner must name the current owner to change the owner or team of an owned ruleset
+	CurrentOwner string `json:"current_owner,omitempty"`
+	// Force allows modifying a ruleset imported from an external source, including
+	// its source, or reassigning an owned ruleset without CurrentOwner
+	Force bool `json:"force,omitempty"`
+}
+
+// DeleteOptions controls ruleset deletion
+type DeleteOptions struct {
+	// Force allows deleting a ruleset imported from an external source
+	Force bool
+}
+
+// Supported content types for stored artifacts
+const (
+	ContentTypeMarkdown       = "text/markdown"
+	ContentTypePlainText      = "text/plain"
+	ContentTypeJSON           = "application/json"
+	ContentTypeJSONSchema     = "application/schema+json"
+	ContentTypePromptTemplate = "text/x-prompt-template"
+)
+
+// SupportedContentTypes lists the content types accepted by the service
+var SupportedContentTypes = []string{
+	ContentTypeMarkdown,
+	ContentTypePlainText,
+	ContentTypeJSON,
+	ContentTypeJSONSchema,
+	ContentTypePromptTemplate,
+}
+
+// IsJSONContentType reports whether the content type requires a valid JSON body
+func IsJSONContentType(contentType string) bool {
+	return contentType == ContentTypeJSON || contentType == ContentTypeJSONSchema
+}
+
+// ParseMode controls how malformed stored ruleset data is handled on read
+type ParseMode string
+
+const (
+	// ParseModeStrict fails reads that encounter malformed stored data
+	ParseModeStrict ParseMode = "strict"
+	// ParseModeLenient logs malformed fields and returns a best-effort ruleset
+	ParseModeLenient ParseMode = "lenient"
+)
+
+// ReferencePolicy controls what deleting a ruleset that others link to does
+type ReferencePolicy string
+
+const (
+	// ReferencePolicyWarn deletes the ruleset and reports the references left dangling
+	ReferencePolicyWarn ReferencePolicy = "warn"
+	// ReferencePolicyBlock refuses to delete a referenced ruleset
+	ReferencePolicyBlock ReferencePolicy = "block"
+	// ReferencePolicyCascade deletes the ruleset and unlinks it from referencing rulesets
+	ReferencePolicyCascade ReferencePolicy = "cascade"
+)
+
+// ParseError reports a stored ruleset field that could not be decoded
+type ParseError struct {
+	Name  string
+	Field string
+	Err   error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying decoding error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// validateContent checks that the content type is supported and the body matches it
+func validateContent(contentType, body string) error {
+	if !slices.Contains(SupportedContentTypes, contentType) {
+		return fmt.Errorf("unsupported content type '%s' (supported: %v)", contentType, SupportedContentTypes)
+	}
+	if IsJSONContentType(contentType) && !json.Valid([]byte(body)) {
+		return fmt.Errorf("content is not valid JSON for content type '%s'", contentType)
+	}
+	return nil
 }
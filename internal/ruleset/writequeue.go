@@ -0,0 +1,207 @@
+package ruleset
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// QueuedError reports a mutation that could not reach storage and was
+// accepted into the write queue instead. It is applied on a later replay.
+type QueuedError struct {
+	// Pending is the number of queued mutations, including this one
+	Pending int
+	// Err is the storage error that caused the mutation to be queued
+	Err error
+}
+
+func (e *QueuedError) Error() string {
+	return fmt.Sprintf("storage is unavailable; change queued and will be applied when it returns (%d pending): %v", e.Pending, e.Err)
+}
+
+func (e *QueuedError) Unwrap() error {
+	return e.Err
+}
+
+// queuedMutation is a journaled write, replayed against the wrapped service
+type queuedMutation struct {
+	desc  string
+	apply func(ServiceInterface) error
+}
+
+// QueuedService wraps a ServiceInterface with a bounded write-behind journal.
+// Mutations failing because storage is unreachable are queued and reported
+// with a *QueuedError; Replay applies them in order once storage is back.
+// While changes are queued, later mutations queue behind them so order is kept.
+// Reads always go to the wrapped service. The journal is held in memory, so
+// changes still queued when the process exits are lost.
+type QueuedService struct {
+	ServiceInterface
+
+	mu          sync.Mutex
+	journal     []queuedMutation
+	capacity    int
+	unavailable func(error) bool
+}
+
+// NewQueuedService wraps service with a write queue of at most capacity
+// mutations. unavailable classifies storage errors that are worth queueing.
+func NewQueuedService(service ServiceInterface, unavailable func(error) bool, capacity int) *QueuedService {
+	return &QueuedService{
+		ServiceInterface: service,
+		capacity:         capacity,
+		unavailable:      unavailable,
+	}
+}
+
+// Pending returns the number of queued mutations
+func (q *QueuedService) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.journal)
+}
+
+// Replay applies queued mutations in order and returns how many were applied.
+// It stops at the first mutation storage is still unavailable for. Mutations
+// rejected for other reasons, e.g. a name taken in the meantime, are dropped
+// with a warning since retrying them cannot succeed.
+func (q *QueuedService) Replay() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.replay()
+}
+
+// replay implements Replay with q.mu held
+func (q *QueuedService) replay() (int, error) {
+	applied := 0
+	for len(q.journal) > 0 {
+		m := q.journal[0]
+		err := m.apply(q.ServiceInterface)
+		if err != nil && q.unavailable(err) {
+			return applied, err
+		}
+		q.journal = q.journal[1:]
+		if err != nil {
+			log.Warn().Err(err).Str("mutation", m.desc).Msg("Dropping queued change rejected on replay")
+			continue
+		}
+		applied++
+	}
+	q.journal = nil
+	return applied, nil
+}
+
+// mutate applies a mutation, or queues it when storage is unavailable or earlier
+// changes are still queued. It returns the result of apply when it was applied.
+func (q *QueuedService) mutate(desc string, apply func(ServiceInterface) (bool, error)) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var cause error
+	if len(q.journal) > 0 {
+		_, cause = q.replay()
+	}
+	if len(q.journal) == 0 {
+		result, err := apply(q.ServiceInterface)
+		if err == nil || !q.unavailable(err) {
+			return result, err
+		}
+		cause = err
+	}
+
+	if len(q.journal) >= q.capacity {
+		return false, fmt.Errorf("storage is unavailable and the write queue is full (%d changes pending): %w", len(q.journal), cause)
+	}
+	q.journal = append(q.journal, queuedMutation{desc: desc, apply: func(s ServiceInterface) error {
+		_, err := apply(s)
+		return err
+	}})
+	log.Warn().Err(cause).Str("mutation", desc).Int("pending", len(q.journal)).Msg("Queued change while storage is unavailable")
+	return false, &QueuedError{Pending: len(q.journal), Err: cause}
+}
+
+// Create creates a ruleset, queueing it while storage is unavailable
+func (q *QueuedService) Create(rs *Ruleset) error {
+	_, err := q.mutate("create "+rs.Name, func(s ServiceInterface) (bool, error) {
+		return false, s.Create(rs)
+	})
+	return err
+}
+
+// Update updates a ruleset, queueing the update while storage is unavailable
+func (q *QueuedService) Update(name string, updates *Update) error {
+	_, err := q.mutate("update "+name, func(s ServiceInterface) (bool, error) {
+		return false, s.Update(name, updates)
+	})
+	return err
+}
+
+// Upsert creates or updates a ruleset, queueing it while storage is unavailable.
+// For a queued upsert created is false, since the outcome is not known yet.
+func (q *QueuedService) Upsert(rs *Ruleset, updates *Update) (bool, error) {
+	return q.mutate("upsert "+rs.Name, func(s ServiceInterface) (bool, error) {
+		return s.Upsert(rs, updates)
+	})
+}
+
+// Delete deletes a ruleset, queueing the deletion while storage is unavailable
+func (q *QueuedService) Delete(name string) error {
+	return q.DeleteWithOptions(name, DeleteOptions{})
+}
+
+// DeleteWithOptions deletes a ruleset, queueing the deletion while storage is unavailable
+func (q *QueuedService) DeleteWithOptions(name string, opts DeleteOptions) error {
+	_, err := q.mutate("delete "+name, func(s ServiceInterface) (bool, error) {
+		return false, s.DeleteWithOptions(name, opts)
+	})
+	return err
+}
+
+// ApplyChangeset applies a changeset atomically through the wrapped service,
+// queueing it behind earlier changes and while storage is unavailable
+func (q *QueuedService) ApplyChangeset(changes []Change) error {
+	applier, ok := q.ServiceInterface.(ChangesetApplier)
+	if !ok {
+		return fmt.Errorf("the ruleset service does not support changesets")
+	}
+	_, err := q.mutate(fmt.Sprintf("changeset of %d change(s)", len(changes)), func(ServiceInterface) (bool, error) {
+		return false, applier.ApplyChangeset(changes)
+	})
+	return err
+}
+
+// RebuildIndexesWithProgress rebuilds the indexes of the wrapped service once
+// queued changes are applied, so the rebuild reflects them
+func (q *QueuedService) RebuildIndexesWithProgress(progress func(done, total int)) (int, error) {
+	maintainer, ok := q.ServiceInterface.(Maintainer)
+	if !ok {
+		return 0, fmt.Errorf("the ruleset service does not support index rebuilds")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.journal) > 0 {
+		if _, err := q.replay(); err != nil {
+			return 0, fmt.Errorf("%d queued change(s) must be applied first: %w", len(q.journal), err)
+		}
+	}
+	return maintainer.RebuildIndexesWithProgress(progress)
+}
+
+// FlushCache drops the read cache of the wrapped service, reporting whether it has one
+func (q *QueuedService) FlushCache() bool {
+	if maintainer, ok := q.ServiceInterface.(Maintainer); ok {
+		return maintainer.FlushCache()
+	}
+	return false
+}
+
+// BeginBulkImport defers index maintenance of a bulk import when the wrapped
+// service supports it
+func (q *QueuedService) BeginBulkImport() (end func()) {
+	if bulk, ok := q.ServiceInterface.(BulkImporter); ok {
+		return bulk.BeginBulkImport()
+	}
+	return func() {}
+}
@@ -0,0 +1,172 @@
+package ruleset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errStorageDown = errors.New("connection refused")
+
+// flakyStorage fails every command while down
+type flakyStorage struct {
+	*memstore.Store
+	down bool
+}
+
+func (f *flakyStorage) Exists(ctx context.Context, key string) (bool, error) {
+	if f.down {
+		return false, errStorageDown
+	}
+	return f.Store.Exists(ctx, key)
+}
+
+func (f *flakyStorage) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if f.down {
+		return nil, errStorageDown
+	}
+	return f.Store.HGetAll(ctx, key)
+}
+
+func (f *flakyStorage) UpsertHash(ctx context.Context, key string, create, update map[string]string) (bool, error) {
+	if f.down {
+		return false, errStorageDown
+	}
+	return f.Store.UpsertHash(ctx, key, create, update)
+}
+
+func newQueuedService(capacity int) (*QueuedService, *flakyStorage) {
+	storage := &flakyStorage{Store: memstore.New()}
+	unavailable := func(err error) bool { return errors.Is(err, errStorageDown) }
+	return NewQueuedService(NewService(storage), unavailable, capacity), storage
+}
+
+func TestQueuedService_QueuesWhileUnavailable(t *testing.T) {
+	queue, storage := newQueuedService(10)
+
+	created, err := queue.Upsert(&Ruleset{Name: "offline_rules", Description: "Offline", Markdown: "# v1"}, &Update{})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	storage.down = true
+	markdown := "# v2"
+	err = queue.Update("offline_rules", &Update{Markdown: &markdown})
+	var queued *QueuedError
+	require.ErrorAs(t, err, &queued)
+	assert.Equal(t, 1, queued.Pending)
+	assert.ErrorIs(t, err, errStorageDown)
+
+	require.ErrorAs(t, queue.Create(&Ruleset{Name: "new_rules", Description: "New", Markdown: "# New"}), &queued)
+	assert.Equal(t, 2, queue.Pending())
+
+	// Replay keeps changes while storage is still down
+	applied, err := queue.Replay()
+	assert.Equal(t, 0, applied)
+	assert.ErrorIs(t, err, errStorageDown)
+	assert.Equal(t, 2, queue.Pending())
+
+	storage.down = false
+	applied, err = queue.Replay()
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	assert.Equal(t, 0, queue.Pending())
+
+	rs, err := queue.Get("offline_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "# v2", rs.Markdown)
+	exists, err := queue.Exists("new_rules")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestQueuedService_KeepsOrder(t *testing.T) {
+	queue, storage := newQueuedService(10)
+	require.NoError(t, queue.Create(&Ruleset{Name: "ordered", Description: "Ordered", Markdown: "# v1"}))
+
+	storage.down = true
+	first := "# v2"
+	require.ErrorAs(t, queue.Update("ordered", &Update{Markdown: &first}), new(*QueuedError))
+
+	// Once storage is back the next change replays the queue before applying itself
+	storage.down = false
+	second := "# v3"
+	require.NoError(t, queue.Update("ordered", &Update{Markdown: &second}))
+	assert.Equal(t, 0, queue.Pending())
+
+	rs, err := queue.Get("ordered")
+	require.NoError(t, err)
+	assert.Equal(t, "# v3", rs.Markdown)
+	assert.Equal(t, int64(3), rs.Version)
+}
+
+func TestQueuedService_ChangesetsAndRebuildsKeepOrder(t *testing.T) {
+	queue, storage := newQueuedService(10)
+	require.NoError(t, queue.Create(&Ruleset{Name: "ordered", Description: "Ordered", Markdown: "# v1"}))
+
+	storage.down = true
+	first := "# v2"
+	require.ErrorAs(t, queue.Update("ordered", &Update{Markdown: &first}), new(*QueuedError))
+
+	// Rebuilds wait for queued changes
+	_, err := queue.RebuildIndexesWithProgress(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 queued change(s) must be applied first")
+
+	// A changeset committed while storage is down is queued behind the update
+	second := "# v3"
+	changes := []Change{{Kind: ChangeUpsert, Name: "ordered", Ruleset: &Ruleset{Name: "ordered"}, Updates: &Update{Markdown: &second}}}
+	require.ErrorAs(t, queue.ApplyChangeset(changes), new(*QueuedError))
+	assert.Equal(t, 2, queue.Pending())
+
+	storage.down = false
+	count, err := queue.RebuildIndexesWithProgress(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 0, queue.Pending())
+
+	rs, err := queue.Get("ordered")
+	require.NoError(t, err)
+	assert.Equal(t, "# v3", rs.Markdown)
+	assert.Equal(t, int64(3), rs.Version)
+
+	// Once the queue is empty changesets apply directly, after earlier changes
+	third := "# v4"
+	storage.down = true
+	require.ErrorAs(t, queue.Update("ordered", &Update{Markdown: &third}), new(*QueuedError))
+	storage.down = false
+	fourth := "# v5"
+	changes[0].Updates = &Update{Markdown: &fourth}
+	require.NoError(t, queue.ApplyChangeset(changes))
+	rs, err = queue.Get("ordered")
+	require.NoError(t, err)
+	assert.Equal(t, "# v5", rs.Markdown)
+	assert.Equal(t, int64(5), rs.Version)
+}
+
+func TestQueuedService_Limits(t *testing.T) {
+	queue, storage := newQueuedService(1)
+
+	// Errors other than unavailability are returned as is
+	err := queue.Create(&Ruleset{Name: "Bad Name", Description: "Bad", Markdown: "# Bad"})
+	require.Error(t, err)
+	assert.NotErrorAs(t, err, new(*QueuedError))
+
+	storage.down = true
+	require.ErrorAs(t, queue.Delete("first"), new(*QueuedError))
+
+	err = queue.Delete("second")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "write queue is full (1 changes pending)")
+	assert.Equal(t, 1, queue.Pending())
+
+	// Changes rejected on replay are dropped
+	storage.down = false
+	applied, err := queue.Replay()
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied)
+	assert.Equal(t, 0, queue.Pending())
+}
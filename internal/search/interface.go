@@ -0,0 +1,9 @@
+package search
+
+// ServiceInterface defines the interface for saved search operations
+type ServiceInterface interface {
+	Save(saved *Saved) (created bool, err error)
+	Get(name string) (*Saved, error)
+	Delete(name string) error
+	List() ([]*Saved, error)
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QueryFields lists the field names understood by Parse
+var QueryFields = []string{"name", "tag", "license", "owner", "team", "created", "modified"}
+
+// Parse turns a query string into search criteria. A query is a whitespace
+// separated list of terms, all of which must match:
+//
+//	name:go_*               glob pattern matched against the ruleset name
+//	tag:python              ruleset carries the tag (repeatable)
+//	license:MIT             license expression references the SPDX identifier
+//	owner:alice             ruleset is owned by alice
+//	team:platform           ruleset is assigned to the platform team
+//	created:>2024-01-01     created after the date
+//	modified:>-7d           modified after the date
+//	modified:<2024-06-01    modified before the date
+//	style                   name, description or a tag contains the word
+//
+// Dates accept the same forms as the search tool parameters. Values and words
+// containing spaces or colons can be double-quoted, e.g. tag:"code review".
+func Parse(query string) (Criteria, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return Criteria{}, err
+	}
+
+	var c Criteria
+	for _, tok := range tokens {
+		if tok.field == "" {
+			c.Text = append(c.Text, tok.value)
+			continue
+		}
+		if tok.value == "" {
+			return Criteria{}, fmt.Errorf("missing value for '%s:'", tok.field)
+		}
+
+		switch tok.field {
+		case "name":
+			err = setOnce(&c.Pattern, tok)
+		case "tag":
+			c.Tags = append(c.Tags, tok.value)
+		case "license":
+			err = setOnce(&c.License, tok)
+		case "owner":
+			err = setOnce(&c.Owner, tok)
+		case "team":
+			err = setOnce(&c.Team, tok)
+		case "created", "modified":
+			err = c.setDate(tok)
+		default:
+			err = fmt.Errorf("unknown field '%s' (supported: %s); quote the term to search for it literally",
+				tok.field, strings.Join(QueryFields, ", "))
+		}
+		if err != nil {
+			return Criteria{}, err
+		}
+	}
+
+	return c, nil
+}
+
+// setDate applies a created:/modified: comparison to the criteria
+func (c *Criteria) setDate(tok token) error {
+	op, value := tok.value[:1], tok.value[1:]
+	if (op != ">" && op != "<") || value == "" {
+		return fmt.Errorf("'%s:' needs a comparison such as %s:>2024-01-01", tok.field, tok.field)
+	}
+
+	var target *string
+	switch {
+	case tok.field == "created" && op == ">":
+		target = &c.CreatedAfter
+	case tok.field == "modified" && op == ">":
+		target = &c.ModifiedAfter
+	case tok.field == "modified" && op == "<":
+		target = &c.ModifiedBefore
+	default:
+		return fmt.Errorf("'created:' only supports '>'")
+	}
+
+	if *target != "" {
+		return fmt.Errorf("duplicate '%s:%s' term", tok.field, op)
+	}
+	*target = value
+	return nil
+}
+
+// setOnce assigns a single-valued field, rejecting repeats
+func setOnce(target *string, tok token) error {
+	if *target != "" {
+		return fmt.Errorf("duplicate '%s:' term", tok.field)
+	}
+	*target = tok.value
+	return nil
+}
+
+// token is a single query term; field is empty for free-text words
+type token struct {
+	field string
+	value string
+}
+
+// tokenize splits a query into terms, honouring double quotes
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	rest := strings.TrimSpace(query)
+	for rest != "" {
+		var tok token
+
+		// A field prefix is an unquoted word followed by a colon
+		if end := strings.IndexAny(rest, ": \t\""); end > 0 && rest[end] == ':' {
+			tok.field = strings.ToLower(rest[:end])
+			rest = rest[end+1:]
+		}
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			tok.value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			tok.value = rest[:end]
+			rest = rest[end:]
+		}
+
+		if tok.field != "" || tok.value != "" {
+			tokens = append(tokens, tok)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+	}
+	return tokens, nil
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		want  Criteria
+	}{
+		{"empty", "   ", Criteria{}},
+		{"free text", "style guide", Criteria{Text: []string{"style", "guide"}}},
+		{
+			"fields and text",
+			"tag:python modified:>2024-01-01 style",
+			Criteria{Tags: []string{"python"}, ModifiedAfter: "2024-01-01", Text: []string{"style"}},
+		},
+		{
+			"all fields",
+			"name:go_* tag:go tag:style license:MIT created:>-30d modified:>-7d modified:<2024-06-01",
+			Criteria{
+				Pattern:        "go_*",
+				Tags:           []string{"go", "style"},
+				License:        "MIT",
+				CreatedAfter:   "-30d",
+				ModifiedAfter:  "-7d",
+				ModifiedBefore: "2024-06-01",
+			},
+		},
+		{"quoted values", `tag:"code review" "error handling"`, Criteria{Tags: []string{"code review"}, Text: []string{"error handling"}}},
+		{"quoted colon", `"http://example.com"`, Criteria{Text: []string{"http://example.com"}}},
+		{"field names ignore case", "TAG:go", Criteria{Tags: []string{"go"}}},
+		{"stewardship", "owner:alice@example.com team:platform", Criteria{Owner: "alice@example.com", Team: "platform"}},
+		{"timestamp with colons", "modified:>2024-01-01T10:00:00Z", Criteria{ModifiedAfter: "2024-01-01T10:00:00Z"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := []struct {
+		query   string
+		wantErr string
+	}{
+		{"status:published", "unknown field 'status'"},
+		{"tag:", "missing value for 'tag:'"},
+		{"name:a name:b", "duplicate 'name:' term"},
+		{"license:MIT license:BSD-3-Clause", "duplicate 'license:' term"},
+		{"owner:alice owner:bob", "duplicate 'owner:' term"},
+		{"modified:2024-01-01", "needs a comparison"},
+		{"modified:>", "needs a comparison"},
+		{"modified:>-7d modified:>-1d", "duplicate 'modified:>' term"},
+		{"created:<2024-01-01", "only supports '>'"},
+		{`tag:"open`, "unterminated quote"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := Parse(tc.query)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
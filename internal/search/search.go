@@ -0,0 +1,70 @@
+// Package search provides reusable ruleset search criteria and saved searches.
+package search
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// Criteria are search filters as entered by a user. Dates stay unparsed so
+// relative values such as -7d are resolved each time the search runs.
+type Criteria struct {
+	Pattern        string   `json:"pattern,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Text           []string `json:"text,omitempty"`
+	License        string   `json:"license,omitempty"`
+	Owner          string   `json:"owner,omitempty"`
+	Team           string   `json:"team,omitempty"`
+	CreatedAfter   string   `json:"created_after,omitempty"`
+	ModifiedAfter  string   `json:"modified_after,omitempty"`
+	ModifiedBefore string   `json:"modified_before,omitempty"`
+}
+
+// Filter resolves the criteria into a ruleset filter, evaluating relative dates against now
+func (c Criteria) Filter(now time.Time) (ruleset.Filter, error) {
+	filter := ruleset.Filter{
+		Pattern: c.Pattern,
+		Tags:    c.Tags,
+		Text:    c.Text,
+		License: c.License,
+		Owner:   c.Owner,
+		Team:    c.Team,
+	}
+	if filter.Pattern == "" {
+		filter.Pattern = "*"
+	}
+
+	dates := []struct {
+		param  string
+		value  string
+		target *time.Time
+	}{
+		{"created_after", c.CreatedAfter, &filter.CreatedAfter},
+		{"modified_after", c.ModifiedAfter, &filter.ModifiedAfter},
+		{"modified_before", c.ModifiedBefore, &filter.ModifiedBefore},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		t, err := validation.ParseDate(d.value, now)
+		if err != nil {
+			return ruleset.Filter{}, fmt.Errorf("invalid %s: %w", d.param, err)
+		}
+		*d.target = t
+	}
+
+	return filter, nil
+}
+
+// Saved is a named, persisted search
+type Saved struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Criteria     Criteria  `json:"criteria"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastModified time.Time `json:"last_modified"`
+}
@@ -0,0 +1,41 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCriteria_Filter(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	filter, err := Criteria{}.Filter(now)
+	require.NoError(t, err)
+	assert.Equal(t, "*", filter.Pattern)
+	assert.False(t, filter.HasCriteria())
+
+	filter, err = Criteria{
+		Pattern:        "go_*",
+		Tags:           []string{"go"},
+		License:        "MIT",
+		CreatedAfter:   "2024-01-01",
+		ModifiedAfter:  "-7d",
+		ModifiedBefore: "2024-06-15T00:00:00Z",
+	}.Filter(now)
+	require.NoError(t, err)
+	assert.Equal(t, ruleset.Filter{
+		Pattern:        "go_*",
+		Tags:           []string{"go"},
+		License:        "MIT",
+		CreatedAfter:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ModifiedAfter:  now.AddDate(0, 0, -7),
+		ModifiedBefore: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+	}, filter)
+
+	_, err = Criteria{ModifiedBefore: "yesterday"}.Filter(now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid modified_before")
+}
@@ -0,0 +1,156 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// KeyPrefix is the Valkey key prefix used for saved search hashes
+const KeyPrefix = "saved_search:"
+
+// Service provides business logic for saved search management
+type Service struct {
+	storage ruleset.Storage
+	ctx     context.Context
+	clock   func() time.Time
+}
+
+// NewService creates a new saved search service backed by storage
+func NewService(storage ruleset.Storage) *Service {
+	return &Service{
+		storage: storage,
+		ctx:     context.Background(),
+		clock:   time.Now,
+	}
+}
+
+// Save creates or replaces a saved search, preserving its creation time.
+// It reports whether the search was newly created.
+func (s *Service) Save(saved *Saved) (bool, error) {
+	if err := validation.ValidateName("saved search", saved.Name); err != nil {
+		return false, err
+	}
+
+	// Reject criteria that could never run
+	now := s.clock()
+	if _, err := saved.Criteria.Filter(now); err != nil {
+		return false, err
+	}
+	if saved.Criteria.License != "" {
+		if err := validation.ValidateLicense(saved.Criteria.License); err != nil {
+			return false, err
+		}
+	}
+
+	key := KeyPrefix + saved.Name
+	existing, err := s.storage.HGetAll(s.ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve saved search: %w", err)
+	}
+
+	created := len(existing) == 0
+	saved.CreatedAt = now
+	if !created {
+		if createdAt, err := validation.ParseTimestamp(existing["created_at"]); err == nil {
+			saved.CreatedAt = createdAt
+		}
+	}
+	saved.LastModified = now
+
+	criteriaJSON, err := json.Marshal(saved.Criteria)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode criteria: %w", err)
+	}
+
+	fields := map[string]string{
+		"description":   saved.Description,
+		"criteria":      string(criteriaJSON),
+		"created_at":    validation.FormatTimestamp(saved.CreatedAt),
+		"last_modified": validation.FormatTimestamp(saved.LastModified),
+	}
+	if err := s.storage.HSet(s.ctx, key, fields); err != nil {
+		return false, fmt.Errorf("failed to save search: %w", err)
+	}
+
+	return created, nil
+}
+
+// Get retrieves a saved search by exact name
+func (s *Service) Get(name string) (*Saved, error) {
+	if err := validation.ValidateName("saved search", name); err != nil {
+		return nil, err
+	}
+
+	result, err := s.storage.HGetAll(s.ctx, KeyPrefix+name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve saved search: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("saved search '%s' not found", name)
+	}
+
+	saved := &Saved{
+		Name:        name,
+		Description: result["description"],
+	}
+	if err := json.Unmarshal([]byte(result["criteria"]), &saved.Criteria); err != nil {
+		return nil, fmt.Errorf("failed to parse criteria: %w", err)
+	}
+	if saved.CreatedAt, err = validation.ParseTimestamp(result["created_at"]); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if saved.LastModified, err = validation.ParseTimestamp(result["last_modified"]); err != nil {
+		return nil, fmt.Errorf("failed to parse last_modified: %w", err)
+	}
+
+	return saved, nil
+}
+
+// Delete removes a saved search by name
+func (s *Service) Delete(name string) error {
+	if err := validation.ValidateName("saved search", name); err != nil {
+		return err
+	}
+
+	key := KeyPrefix + name
+	exists, err := s.storage.Exists(s.ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check if saved search exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("saved search '%s' not found", name)
+	}
+
+	if err := s.storage.Del(s.ctx, key); err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all saved searches sorted by name
+func (s *Service) List() ([]*Saved, error) {
+	keys, err := s.storage.ScanKeys(s.ctx, KeyPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan saved search keys: %w", err)
+	}
+
+	searches := make([]*Saved, 0, len(keys))
+	for _, key := range keys {
+		saved, err := s.Get(strings.TrimPrefix(key, KeyPrefix))
+		if err != nil {
+			// Skip searches that vanished or cannot be decoded
+			continue
+		}
+		searches = append(searches, saved)
+	}
+
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	return searches, nil
+}
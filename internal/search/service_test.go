@@ -0,0 +1,76 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CRUD(t *testing.T) {
+	service := NewService(memstore.New())
+	first := time.Date(2025, 10, 29, 10, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return first }
+
+	criteria := Criteria{Pattern: "go_*", Tags: []string{"go"}, ModifiedAfter: "-30d"}
+	created, err := service.Save(&Saved{Name: "recent_go", Description: "Go rules touched this month", Criteria: criteria})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	saved, err := service.Get("recent_go")
+	require.NoError(t, err)
+	assert.Equal(t, "Go rules touched this month", saved.Description)
+	assert.Equal(t, criteria, saved.Criteria)
+	assert.Equal(t, first, saved.CreatedAt)
+
+	// Saving again replaces the criteria but keeps the creation time
+	later := first.Add(time.Hour)
+	service.clock = func() time.Time { return later }
+	created, err = service.Save(&Saved{Name: "recent_go", Criteria: Criteria{Pattern: "go_*"}})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	saved, err = service.Get("recent_go")
+	require.NoError(t, err)
+	assert.Equal(t, Criteria{Pattern: "go_*"}, saved.Criteria)
+	assert.Equal(t, first, saved.CreatedAt)
+	assert.Equal(t, later, saved.LastModified)
+
+	_, err = service.Save(&Saved{Name: "all_rules"})
+	require.NoError(t, err)
+
+	searches, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, "all_rules", searches[0].Name)
+	assert.Equal(t, "recent_go", searches[1].Name)
+
+	require.NoError(t, service.Delete("recent_go"))
+	_, err = service.Get("recent_go")
+	assert.Error(t, err)
+	assert.Error(t, service.Delete("recent_go"))
+}
+
+func TestService_SaveValidation(t *testing.T) {
+	service := NewService(memstore.New())
+
+	testCases := []struct {
+		name    string
+		saved   *Saved
+		wantErr string
+	}{
+		{"invalid name", &Saved{Name: "Bad-Name"}, "snake_case"},
+		{"invalid date", &Saved{Name: "bad_date", Criteria: Criteria{CreatedAfter: "someday"}}, "invalid created_after"},
+		{"invalid license", &Saved{Name: "bad_license", Criteria: Criteria{License: "NotALicense"}}, "NotALicense"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.Save(tc.saved)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Package selftest runs a scripted create, get, update, search and delete
+// cycle against a ruleset service, so operators can check a live deployment,
+// e.g. after an upgrade, without touching its rulesets.
+package selftest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+)
+
+// rulesetName is the name of the ruleset written by the self-test
+const rulesetName = "selftest_probe"
+
+// Step outcomes
+const (
+	StatusPassed  = "PASS"
+	StatusFailed  = "FAIL"
+	StatusSkipped = "SKIP"
+)
+
+// Step is the outcome of one step of the self-test
+type Step struct {
+	Name     string
+	Status   string
+	Duration time.Duration
+	Err      error
+}
+
+// Report is the outcome of a self-test
+type Report struct {
+	Steps []Step
+	// CleanupErr is set when the rulesets or blobs written could not be removed afterwards
+	CleanupErr error
+}
+
+// Passed reports whether every step passed
+func (r *Report) Passed() bool {
+	for _, step := range r.Steps {
+		if step.Status != StatusPassed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the number of failed steps
+func (r *Report) Failed() int {
+	failed := 0
+	for _, step := range r.Steps {
+		if step.Status == StatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Run runs the cycle against service, which must use a key prefix of its own
+// so the deployment's rulesets are never read or written. Each step depends
+// on the previous one, so the steps after a failure are skipped. Whatever the
+// outcome, Run then removes the rulesets and content blobs it left behind.
+func Run(service *ruleset.Service) *Report {
+	start := time.Now()
+	probe := &ruleset.Ruleset{
+		Name:        rulesetName,
+		Description: "Self-test probe",
+		Tags:        []string{"selftest"},
+		Markdown:    "# Self-test\n\nWritten by the self-test; safe to delete.\n",
+	}
+	updated := "# Self-test\n\nUpdated by the self-test.\n"
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"create", func() error {
+			return service.Create(probe)
+		}},
+		{"get", func() error {
+			return expect(service, probe.Markdown, 1)
+		}},
+		{"update", func() error {
+			if err := service.Update(rulesetName, &ruleset.Update{Markdown: &updated}); err != nil {
+				return err
+			}
+			return expect(service, updated, 2)
+		}},
+		{"search", func() error {
+			found, err := service.Find(ruleset.Filter{Tags: []string{"selftest"}, ModifiedAfter: start.Add(-time.Second)})
+			if err != nil {
+				return err
+			}
+			if len(found) != 1 || found[0].Name != rulesetName {
+				return fmt.Errorf("expected to find '%s', found %d ruleset(s)", rulesetName, len(found))
+			}
+			return nil
+		}},
+		{"delete", func() error {
+			if err := service.Delete(rulesetName); err != nil {
+				return err
+			}
+			exists, err := service.Exists(rulesetName)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("ruleset '%s' still exists after deletion", rulesetName)
+			}
+			return nil
+		}},
+	}
+
+	report := &Report{}
+	failed := false
+	for _, step := range steps {
+		if failed {
+			report.Steps = append(report.Steps, Step{Name: step.name, Status: StatusSkipped})
+			continue
+		}
+		began := time.Now()
+		err := step.run()
+		result := Step{Name: step.name, Status: StatusPassed, Duration: time.Since(began), Err: err}
+		if err != nil {
+			result.Status = StatusFailed
+			failed = true
+		}
+		report.Steps = append(report.Steps, result)
+	}
+
+	report.CleanupErr = cleanup(service)
+	return report
+}
+
+// expect reads the probe and checks its content and version
+func expect(service *ruleset.Service, markdown string, version int64) error {
+	rs, err := service.Get(rulesetName)
+	if err != nil {
+		return err
+	}
+	if rs.Markdown != markdown {
+		return fmt.Errorf("read content differs from the content written")
+	}
+	if rs.Version != version {
+		return fmt.Errorf("version is %d, expected %d", rs.Version, version)
+	}
+	return nil
+}
+
+// cleanup deletes every ruleset under the service's prefix and the content blobs they used
+func cleanup(service *ruleset.Service) error {
+	names, err := service.ListNames()
+	if err != nil {
+		return fmt.Errorf("failed to list leftover rulesets: %w", err)
+	}
+	for _, name := range names {
+		if err := service.DeleteWithOptions(name, ruleset.DeleteOptions{Force: true}); err != nil {
+			return fmt.Errorf("failed to delete leftover ruleset '%s': %w", name, err)
+		}
+	}
+	if _, err := service.CollectGarbage(0); err != nil {
+		return fmt.Errorf("failed to remove leftover content blobs: %w", err)
+	}
+	return nil
+}
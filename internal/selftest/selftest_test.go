@@ -0,0 +1,75 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []ruleset.Option
+	}{
+		{name: "inline bodies"},
+		{name: "blob storage", opts: []ruleset.Option{ruleset.WithBlobStorage()}},
+		{name: "cache", opts: []ruleset.Option{ruleset.WithCache(ruleset.NewLRUCache(8))}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memstore.New()
+			production := ruleset.NewService(store)
+			require.NoError(t, production.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go", Markdown: "# Go"}))
+
+			report := Run(ruleset.NewService(store, append(tt.opts, ruleset.WithKeyPrefix("selftest:abc:"))...))
+
+			require.Len(t, report.Steps, 5)
+			for _, step := range report.Steps {
+				assert.Equal(t, StatusPassed, step.Status, step.Name)
+				assert.NoError(t, step.Err, step.Name)
+			}
+			assert.True(t, report.Passed())
+			assert.NoError(t, report.CleanupErr)
+
+			// Nothing is left behind and the deployment's rulesets are untouched
+			leftover, err := store.ScanKeys(context.Background(), "*selftest:abc:*")
+			require.NoError(t, err)
+			assert.Empty(t, leftover)
+			names, err := production.ListNames()
+			require.NoError(t, err)
+			assert.Equal(t, []string{"go_rules"}, names)
+		})
+	}
+}
+
+func TestRun_SkipsAfterFailure(t *testing.T) {
+	store := memstore.New()
+	rejectUpdates := func(rs *ruleset.Ruleset) error {
+		if strings.Contains(rs.Markdown, "Updated") {
+			return errors.New("read-only")
+		}
+		return nil
+	}
+
+	report := Run(ruleset.NewService(store, ruleset.WithKeyPrefix("selftest:abc:"), ruleset.WithValidators(rejectUpdates)))
+
+	statuses := make([]string, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		statuses = append(statuses, step.Status)
+	}
+	assert.Equal(t, []string{StatusPassed, StatusPassed, StatusFailed, StatusSkipped, StatusSkipped}, statuses)
+	assert.ErrorContains(t, report.Steps[2].Err, "read-only")
+	assert.False(t, report.Passed())
+	assert.Equal(t, 1, report.Failed())
+
+	// The probe is removed even though the delete step was skipped
+	assert.NoError(t, report.CleanupErr)
+	leftover, err := store.ScanKeys(context.Background(), "selftest:abc:*")
+	require.NoError(t, err)
+	assert.Empty(t, leftover)
+}
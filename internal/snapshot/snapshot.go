@@ -0,0 +1,117 @@
+// Package snapshot persists the ruleset corpus to a local file so that reads
+// keep working when Valkey is unreachable.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+)
+
+// FormatVersion is the version of the snapshot file format written by Write
+const FormatVersion = 1
+
+// ErrReadOnly is returned by mutations on a service serving a snapshot
+var ErrReadOnly = errors.New("server is offline and serving a read-only snapshot; changes are not possible")
+
+// Snapshot is a point-in-time copy of every ruleset
+type Snapshot struct {
+	Version  int                `json:"version"`
+	TakenAt  time.Time          `json:"taken_at"`
+	Rulesets []*ruleset.Ruleset `json:"rulesets"`
+}
+
+// Take reads every ruleset from service into a snapshot
+func Take(service ruleset.ServiceInterface, now time.Time) (*Snapshot, error) {
+	rulesets, err := service.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rulesets: %w", err)
+	}
+	return &Snapshot{Version: FormatVersion, TakenAt: now.UTC(), Rulesets: rulesets}, nil
+}
+
+// Write stores snap at path. The file is replaced atomically, so a reader or
+// a crash mid-write never leaves a truncated snapshot behind.
+func Write(path string, snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads the snapshot stored at path
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if snap.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d in %s (expected %d)", snap.Version, path, FormatVersion)
+	}
+	return &snap, nil
+}
+
+// Service returns a read-only ruleset service answering reads from the snapshot
+func (s *Snapshot) Service() (ruleset.ServiceInterface, error) {
+	service := ruleset.NewService(memstore.New())
+	for _, rs := range s.Rulesets {
+		if _, err := service.Restore(rs); err != nil {
+			return nil, fmt.Errorf("failed to load snapshot: %w", err)
+		}
+	}
+	return readOnlyService{service}, nil
+}
+
+// readOnlyService rejects every mutation with ErrReadOnly
+type readOnlyService struct {
+	ruleset.ServiceInterface
+}
+
+func (readOnlyService) Create(*ruleset.Ruleset) error {
+	return ErrReadOnly
+}
+
+func (readOnlyService) Update(string, *ruleset.Update) error {
+	return ErrReadOnly
+}
+
+func (readOnlyService) Upsert(*ruleset.Ruleset, *ruleset.Update) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyService) Delete(string) error {
+	return ErrReadOnly
+}
+
+func (readOnlyService) DeleteWithOptions(string, ruleset.DeleteOptions) error {
+	return ErrReadOnly
+}
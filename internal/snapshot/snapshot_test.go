@@ -0,0 +1,70 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	source := ruleset.NewService(memstore.New())
+	require.NoError(t, source.Create(&ruleset.Ruleset{Name: "go_rules", Description: "Go", Tags: []string{"go"}, Markdown: "# Go"}))
+	require.NoError(t, source.Create(&ruleset.Ruleset{Name: "py_rules", Description: "Python", Tags: []string{"python"}, Markdown: "# Python"}))
+
+	takenAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	snap, err := Take(source, takenAt)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, Write(path, snap))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, loaded.Version)
+	assert.True(t, takenAt.Equal(loaded.TakenAt))
+	assert.Len(t, loaded.Rulesets, 2)
+
+	service, err := loaded.Service()
+	require.NoError(t, err)
+
+	rs, err := service.Get("go_rules")
+	require.NoError(t, err)
+	assert.Equal(t, "# Go", rs.Markdown)
+
+	found, err := service.Find(ruleset.Filter{Pattern: "*", Tags: []string{"python"}})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "py_rules", found[0].Name)
+}
+
+func TestSnapshot_ServiceIsReadOnly(t *testing.T) {
+	service, err := (&Snapshot{Version: FormatVersion}).Service()
+	require.NoError(t, err)
+
+	rs := &ruleset.Ruleset{Name: "new_rules", Description: "New", Markdown: "# New"}
+	assert.ErrorIs(t, service.Create(rs), ErrReadOnly)
+	assert.ErrorIs(t, service.Update("new_rules", &ruleset.Update{}), ErrReadOnly)
+	_, err = service.Upsert(rs, &ruleset.Update{})
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, service.Delete("new_rules"), ErrReadOnly)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read snapshot")
+
+	future := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(future, []byte(`{"version": 99, "rulesets": []}`), 0o600))
+	_, err = Load(future)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported snapshot version 99")
+}
@@ -0,0 +1,9 @@
+package snippet
+
+// ServiceInterface defines the interface for snippet operations
+type ServiceInterface interface {
+	Save(sn *Snippet) (created bool, err error)
+	Get(name string) (*Snippet, error)
+	Delete(name string) error
+	List(tag string) ([]*Snippet, error)
+}
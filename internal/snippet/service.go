@@ -0,0 +1,162 @@
+package snippet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/ruleset"
+	"github.com/jbrinkman/archivyr/internal/validation"
+)
+
+// KeyPrefix is the Valkey key prefix used for snippet hashes
+const KeyPrefix = "snippet:"
+
+// Service provides business logic for snippet management
+type Service struct {
+	storage ruleset.Storage
+	ctx     context.Context
+	clock   func() time.Time
+}
+
+// NewService creates a new snippet service backed by storage
+func NewService(storage ruleset.Storage) *Service {
+	return &Service{
+		storage: storage,
+		ctx:     context.Background(),
+		clock:   time.Now,
+	}
+}
+
+// Save creates or replaces a snippet, preserving its creation time.
+// It reports whether the snippet was newly created.
+func (s *Service) Save(sn *Snippet) (bool, error) {
+	if err := validation.ValidateName("snippet", sn.Name); err != nil {
+		return false, err
+	}
+	if sn.Text == "" {
+		return false, fmt.Errorf("snippet text cannot be empty")
+	}
+	if len(sn.Text) > MaxTextBytes {
+		return false, fmt.Errorf("snippet text is %d bytes, exceeding the limit of %d bytes; use a ruleset for longer content", len(sn.Text), MaxTextBytes)
+	}
+	if len(Includes(sn.Text)) > 0 {
+		return false, fmt.Errorf("snippets cannot include other snippets")
+	}
+
+	key := KeyPrefix + sn.Name
+	existing, err := s.storage.HGetAll(s.ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve snippet: %w", err)
+	}
+
+	now := s.clock()
+	created := len(existing) == 0
+	sn.CreatedAt = now
+	if !created {
+		if createdAt, err := validation.ParseTimestamp(existing["created_at"]); err == nil {
+			sn.CreatedAt = createdAt
+		}
+	}
+	sn.LastModified = now
+
+	if sn.Tags == nil {
+		sn.Tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(sn.Tags)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode tags: %w", err)
+	}
+
+	fields := map[string]string{
+		"text":          sn.Text,
+		"tags":          string(tagsJSON),
+		"created_at":    validation.FormatTimestamp(sn.CreatedAt),
+		"last_modified": validation.FormatTimestamp(sn.LastModified),
+	}
+	if err := s.storage.HSet(s.ctx, key, fields); err != nil {
+		return false, fmt.Errorf("failed to save snippet: %w", err)
+	}
+
+	return created, nil
+}
+
+// Get retrieves a snippet by exact name
+func (s *Service) Get(name string) (*Snippet, error) {
+	if err := validation.ValidateName("snippet", name); err != nil {
+		return nil, err
+	}
+
+	result, err := s.storage.HGetAll(s.ctx, KeyPrefix+name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve snippet: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("snippet '%s' not found", name)
+	}
+
+	sn := &Snippet{
+		Name: name,
+		Text: result["text"],
+	}
+	if err := json.Unmarshal([]byte(result["tags"]), &sn.Tags); err != nil {
+		return nil, fmt.Errorf("failed to parse tags: %w", err)
+	}
+	if sn.CreatedAt, err = validation.ParseTimestamp(result["created_at"]); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if sn.LastModified, err = validation.ParseTimestamp(result["last_modified"]); err != nil {
+		return nil, fmt.Errorf("failed to parse last_modified: %w", err)
+	}
+
+	return sn, nil
+}
+
+// Delete removes a snippet by name
+func (s *Service) Delete(name string) error {
+	if err := validation.ValidateName("snippet", name); err != nil {
+		return err
+	}
+
+	key := KeyPrefix + name
+	exists, err := s.storage.Exists(s.ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check if snippet exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("snippet '%s' not found", name)
+	}
+
+	if err := s.storage.Del(s.ctx, key); err != nil {
+		return fmt.Errorf("failed to delete snippet: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all snippets sorted by name, optionally restricted to those carrying tag
+func (s *Service) List(tag string) ([]*Snippet, error) {
+	keys, err := s.storage.ScanKeys(s.ctx, KeyPrefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan snippet keys: %w", err)
+	}
+
+	snippets := make([]*Snippet, 0, len(keys))
+	for _, key := range keys {
+		sn, err := s.Get(strings.TrimPrefix(key, KeyPrefix))
+		if err != nil {
+			// Skip snippets that vanished or cannot be decoded
+			continue
+		}
+		if tag != "" && !slices.Contains(sn.Tags, tag) {
+			continue
+		}
+		snippets = append(snippets, sn)
+	}
+
+	sort.Slice(snippets, func(i, j int) bool { return snippets[i].Name < snippets[j].Name })
+	return snippets, nil
+}
@@ -0,0 +1,90 @@
+package snippet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrinkman/archivyr/internal/memstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CRUD(t *testing.T) {
+	service := NewService(memstore.New())
+	first := time.Date(2025, 10, 29, 10, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return first }
+
+	created, err := service.Save(&Snippet{Name: "no_tabs", Text: "Use spaces, not tabs.", Tags: []string{"style"}})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	retrieved, err := service.Get("no_tabs")
+	require.NoError(t, err)
+	assert.Equal(t, "Use spaces, not tabs.", retrieved.Text)
+	assert.Equal(t, []string{"style"}, retrieved.Tags)
+	assert.Equal(t, first, retrieved.CreatedAt)
+
+	// Saving again replaces the text but keeps the creation time
+	later := first.Add(time.Hour)
+	service.clock = func() time.Time { return later }
+	created, err = service.Save(&Snippet{Name: "no_tabs", Text: "Indent with spaces."})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	retrieved, err = service.Get("no_tabs")
+	require.NoError(t, err)
+	assert.Equal(t, "Indent with spaces.", retrieved.Text)
+	assert.Empty(t, retrieved.Tags)
+	assert.Equal(t, first, retrieved.CreatedAt)
+	assert.Equal(t, later, retrieved.LastModified)
+
+	require.NoError(t, service.Delete("no_tabs"))
+	_, err = service.Get("no_tabs")
+	assert.Error(t, err)
+
+	err = service.Delete("no_tabs")
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestService_List(t *testing.T) {
+	service := NewService(memstore.New())
+	_, err := service.Save(&Snippet{Name: "line_length", Text: "Wrap at 100 columns.", Tags: []string{"style"}})
+	require.NoError(t, err)
+	_, err = service.Save(&Snippet{Name: "commit_format", Text: "Use imperative subjects.", Tags: []string{"git"}})
+	require.NoError(t, err)
+
+	all, err := service.List("")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "commit_format", all[0].Name)
+	assert.Equal(t, "line_length", all[1].Name)
+
+	style, err := service.List("style")
+	require.NoError(t, err)
+	require.Len(t, style, 1)
+	assert.Equal(t, "line_length", style[0].Name)
+}
+
+func TestService_SaveValidation(t *testing.T) {
+	service := NewService(memstore.New())
+
+	tests := []struct {
+		name    string
+		snippet *Snippet
+		errMsg  string
+	}{
+		{"invalid name", &Snippet{Name: "Bad-Name", Text: "x"}, "snake_case"},
+		{"empty text", &Snippet{Name: "empty"}, "cannot be empty"},
+		{"text too long", &Snippet{Name: "long", Text: strings.Repeat("a", MaxTextBytes+1)}, "exceeding the limit"},
+		{"nested include", &Snippet{Name: "nested", Text: "{{snippet:other}}"}, "cannot include other snippets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Save(tt.snippet)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
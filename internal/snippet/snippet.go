@@ -0,0 +1,57 @@
+// Package snippet provides storage for short reusable text fragments and their
+// inclusion into rulesets.
+package snippet
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// MaxTextBytes is the maximum size of a snippet's text
+const MaxTextBytes = 2048
+
+// Snippet is a short reusable fragment of rule text
+type Snippet struct {
+	Name         string    `json:"name"`
+	Text         string    `json:"text"`
+	Tags         []string  `json:"tags"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// includeRegex matches {{snippet:name}} include directives
+var includeRegex = regexp.MustCompile(`\{\{\s*snippet:([a-z][a-z0-9]*(?:_[a-z0-9]+)*)\s*\}\}`)
+
+// Includes returns the names of snippets referenced by text, in order of first appearance
+func Includes(text string) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, match := range includeRegex.FindAllStringSubmatch(text, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// Expand replaces {{snippet:name}} directives in text with the text returned by lookup.
+// Snippets that cannot be resolved are replaced with an HTML comment so the
+// surrounding document stays readable.
+func Expand(text string, lookup func(name string) (*Snippet, error)) string {
+	resolved := make(map[string]string)
+	return includeRegex.ReplaceAllStringFunc(text, func(directive string) string {
+		name := includeRegex.FindStringSubmatch(directive)[1]
+		if body, ok := resolved[name]; ok {
+			return body
+		}
+
+		body := fmt.Sprintf("<!-- snippet '%s' not found -->", name)
+		if sn, err := lookup(name); err == nil {
+			body = sn.Text
+		}
+		resolved[name] = body
+		return body
+	})
+}
@@ -0,0 +1,63 @@
+package snippet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludes(t *testing.T) {
+	names := Includes("{{snippet:no_tabs}} and {{ snippet:line_length }} then {{snippet:no_tabs}} {{language}}")
+	assert.Equal(t, []string{"no_tabs", "line_length"}, names)
+	assert.Empty(t, Includes("no includes here"))
+}
+
+func TestExpand(t *testing.T) {
+	lookups := 0
+	lookup := func(name string) (*Snippet, error) {
+		lookups++
+		if name == "no_tabs" {
+			return &Snippet{Name: name, Text: "Use spaces, not tabs."}, nil
+		}
+		return nil, fmt.Errorf("snippet '%s' not found", name)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "resolves include",
+			text:     "# Style\n{{snippet:no_tabs}}\n",
+			expected: "# Style\nUse spaces, not tabs.\n",
+		},
+		{
+			name:     "tolerates whitespace",
+			text:     "{{ snippet:no_tabs }}",
+			expected: "Use spaces, not tabs.",
+		},
+		{
+			name:     "missing snippet leaves comment",
+			text:     "{{snippet:missing}}",
+			expected: "<!-- snippet 'missing' not found -->",
+		},
+		{
+			name:     "ignores other placeholders",
+			text:     "{{language}}",
+			expected: "{{language}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Expand(tt.text, lookup))
+		})
+	}
+
+	// Repeated includes are resolved once
+	lookups = 0
+	assert.Equal(t, "Use spaces, not tabs. Use spaces, not tabs.", Expand("{{snippet:no_tabs}} {{snippet:no_tabs}}", lookup))
+	assert.Equal(t, 1, lookups)
+}
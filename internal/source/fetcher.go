@@ -0,0 +1,129 @@
+// Package source fetches the upstream content of imported rulesets.
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxContentBytes caps the size of fetched upstream content
+const maxContentBytes = 10 << 20
+
+// Fetcher retrieves the current content at a ruleset's source URL
+type Fetcher interface {
+	Fetch(sourceURL string) (string, error)
+}
+
+// HTTPFetcher fetches http and https sources. GitHub and GitLab file page URLs
+// are rewritten to their raw content URLs.
+type HTTPFetcher struct {
+	client *http.Client
+	ctx    context.Context
+}
+
+// NewHTTPFetcher creates a fetcher using client. When client is nil a client
+// with a default timeout is used that only connects to public addresses, so
+// a source URL cannot reach loopback, private or link-local hosts such as
+// cloud metadata endpoints, whether directly, through DNS or by redirect.
+func NewHTTPFetcher(client *http.Client) *HTTPFetcher {
+	if client == nil {
+		client = checkedClient(checkAddress)
+	}
+	return &HTTPFetcher{
+		client: client,
+		ctx:    context.Background(),
+	}
+}
+
+// Fetch downloads the content at sourceURL
+func (f *HTTPFetcher) Fetch(sourceURL string) (string, error) {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("refreshing from '%s' sources is not supported", u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, RawURL(u).String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch source: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch source: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxContentBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read source: %w", err)
+	}
+	if len(body) > maxContentBytes {
+		return "", fmt.Errorf("source content exceeds %d bytes", maxContentBytes)
+	}
+
+	return string(body), nil
+}
+
+// checkedClient creates an HTTP client whose connections are checked by control.
+// It uses no proxy, since the proxy's address rather than the source's would be checked.
+func checkedClient(control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: control}
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// checkAddress rejects connections to non-public addresses. It runs after DNS
+// resolution for every connection, including those made to follow redirects.
+func checkAddress(_ string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address '%s': %w", address, err)
+	}
+	addr := addrPort.Addr().Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsUnspecified() || addr.IsMulticast() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("fetching from non-public address %s is not allowed", addr)
+	}
+	return nil
+}
+
+// RawURL rewrites GitHub and GitLab file page URLs to the URL of the raw file
+func RawURL(u *url.URL) *url.URL {
+	raw := *u
+	switch u.Host {
+	case "github.com":
+		// /{owner}/{repo}/blob/{ref}/{path} -> raw.githubusercontent.com/{owner}/{repo}/{ref}/{path}
+		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+		if len(parts) == 4 && parts[2] == "blob" {
+			raw.Host = "raw.githubusercontent.com"
+			raw.Path = "/" + parts[0] + "/" + parts[1] + "/" + parts[3]
+		}
+	case "gitlab.com":
+		// /{project}/-/blob/{ref}/{path} -> /{project}/-/raw/{ref}/{path}
+		raw.Path = strings.Replace(u.Path, "/-/blob/", "/-/raw/", 1)
+	}
+	return &raw
+}
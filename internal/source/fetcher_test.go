@@ -0,0 +1,131 @@
+package source
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rules.md" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("# Upstream"))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher(server.Client())
+
+	content, err := fetcher.Fetch(server.URL + "/rules.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Upstream", content)
+
+	_, err = fetcher.Fetch(server.URL + "/missing.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+
+	_, err = fetcher.Fetch("pack:go_team@1.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'pack' sources is not supported")
+}
+
+func TestHTTPFetcher_RejectsNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher(nil)
+
+	_, err := fetcher.Fetch(server.URL + "/rules.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fetching from non-public address 127.0.0.1 is not allowed")
+
+	// Host names are checked once resolved
+	_, err = fetcher.Fetch(strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/rules.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not allowed")
+}
+
+func TestHTTPFetcher_ChecksRedirects(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("secret"))
+	}))
+	defer internal.Close()
+	public := httptest.NewServer(http.RedirectHandler(internal.URL+"/latest/meta-data", http.StatusFound))
+	defer public.Close()
+
+	// Treat the redirecting server as public; every other connection is checked
+	publicAddr := strings.TrimPrefix(public.URL, "http://")
+	fetcher := NewHTTPFetcher(checkedClient(func(network, address string, c syscall.RawConn) error {
+		if address == publicAddr {
+			return nil
+		}
+		return checkAddress(network, address, c)
+	}))
+
+	_, err := fetcher.Fetch(public.URL + "/rules.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fetching from non-public address 127.0.0.1 is not allowed")
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"100.64.0.1:80", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1]:80", false},
+		{"[fd00::1]:80", false},
+		{"0.0.0.0:80", false},
+		{"[::]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkAddress("tcp", tt.address, nil)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "is not allowed")
+			}
+		})
+	}
+}
+
+func TestRawURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://github.com/example/rules/blob/main/go/style.md", "https://raw.githubusercontent.com/example/rules/main/go/style.md"},
+		{"https://github.com/example/rules", "https://github.com/example/rules"},
+		{"https://gitlab.com/group/rules/-/blob/main/style.md", "https://gitlab.com/group/rules/-/raw/main/style.md"},
+		{"https://example.com/rules.md", "https://example.com/rules.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			u, err := url.Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, RawURL(u).String())
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package util provides helpers shared by features that do not belong to a
+// single domain package.
+package util
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ID generation strategies
+const (
+	StrategyUUID     = "uuid"
+	StrategyULID     = "ulid"
+	StrategyNanoID   = "nanoid"
+	StrategyDateSlug = "date_slug"
+)
+
+// Strategies lists the supported ID generation strategies
+var Strategies = []string{StrategyUUID, StrategyULID, StrategyNanoID, StrategyDateSlug}
+
+// IDGenerator produces identifiers for entities the server names itself
+type IDGenerator interface {
+	// NewID returns a new identifier. hint describes the entity, e.g. its
+	// title; strategies that do not derive IDs from content ignore it.
+	NewID(hint string) (string, error)
+}
+
+// NewIDGenerator returns the generator for a strategy
+func NewIDGenerator(strategy string) (IDGenerator, error) {
+	g := &generator{strategy: strategy, clock: time.Now, random: rand.Reader}
+	switch strategy {
+	case StrategyUUID, StrategyULID, StrategyNanoID, StrategyDateSlug:
+		return g, nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy '%s'; must be one of: %s", strategy, strings.Join(Strategies, ", "))
+	}
+}
+
+// MustIDGenerator is like NewIDGenerator but panics for an unknown strategy
+func MustIDGenerator(strategy string) IDGenerator {
+	g, err := NewIDGenerator(strategy)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// generator implements every strategy; clock and random are replaceable for tests
+type generator struct {
+	strategy string
+	clock    func() time.Time
+	random   io.Reader
+}
+
+// NewID returns a new identifier following the generator's strategy
+func (g *generator) NewID(hint string) (string, error) {
+	switch g.strategy {
+	case StrategyULID:
+		return g.ulid()
+	case StrategyNanoID:
+		return g.nanoID()
+	case StrategyDateSlug:
+		return g.dateSlug(hint)
+	default:
+		return g.uuid()
+	}
+}
+
+// uuid returns a random (version 4) UUID
+func (g *generator) uuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(g.random, b); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	s := hex.EncodeToString(b)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulid returns a ULID: a 48-bit millisecond timestamp followed by 80 random
+// bits, so IDs sort by creation time
+func (g *generator) ulid() (string, error) {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[:8], uint64(g.clock().UnixMilli())<<16)
+	if _, err := io.ReadFull(g.random, b[6:]); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	// 128 bits encode as 26 characters of 5 bits, the first holding only 3
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out), nil
+}
+
+// nanoIDAlphabet is the URL-safe alphabet of nanoid
+const nanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// nanoIDLength gives about as many random bits as a UUID
+const nanoIDLength = 21
+
+// nanoID returns a 21-character nanoid
+func (g *generator) nanoID() (string, error) {
+	return g.randomString(nanoIDAlphabet, nanoIDLength)
+}
+
+// dateSlugSuffixLength is the number of random characters keeping slugs of
+// the same hint on the same day apart
+const dateSlugSuffixLength = 6
+
+// dateSlug returns the UTC date, the slug of hint and a random suffix, e.g.
+// "2025-10-28-api-style-k3x9qa"
+func (g *generator) dateSlug(hint string) (string, error) {
+	suffix, err := g.randomString(strings.ToLower(crockford), dateSlugSuffixLength)
+	if err != nil {
+		return "", err
+	}
+	parts := []string{g.clock().UTC().Format("2006-01-02")}
+	if slug := Slugify(hint); slug != "" {
+		parts = append(parts, slug)
+	}
+	return strings.Join(append(parts, suffix), "-"), nil
+}
+
+// randomString returns n characters drawn uniformly from alphabet, which
+// must have a power-of-two length of at most 256
+func (g *generator) randomString(alphabet string, n int) (string, error) {
+	mask := byte(len(alphabet) - 1)
+	b := make([]byte, n)
+	if _, err := io.ReadFull(g.random, b); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	for i := range b {
+		b[i] = alphabet[b[i]&mask]
+	}
+	return string(b), nil
+}
+
+// maxSlugLength bounds the slug part of date-prefixed IDs
+const maxSlugLength = 48
+
+// Slugify converts text to lowercase ASCII words of letters and digits joined
+// by hyphens, e.g. "API Style (v2)" becomes "api-style-v2"
+func Slugify(text string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(text) {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			pending = b.Len() > 0
+			continue
+		}
+		if pending {
+			if b.Len() >= maxSlugLength-1 {
+				break
+			}
+			b.WriteByte('-')
+			pending = false
+		}
+		b.WriteRune(r)
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+	return b.String()
+}
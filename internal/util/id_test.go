@@ -0,0 +1,73 @@
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDGenerator(t *testing.T) {
+	tests := []struct {
+		strategy string
+		hint     string
+		pattern  string
+	}{
+		{StrategyUUID, "", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{StrategyULID, "", `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`},
+		{StrategyNanoID, "", `^[A-Za-z0-9_-]{21}$`},
+		{StrategyDateSlug, "API Style (v2)", `^2025-10-28-api-style-v2-[0-9a-hjkmnp-tv-z]{6}$`},
+		{StrategyDateSlug, "", `^2025-10-28-[0-9a-hjkmnp-tv-z]{6}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy+" "+tt.hint, func(t *testing.T) {
+			gen, err := NewIDGenerator(tt.strategy)
+			require.NoError(t, err)
+			gen.(*generator).clock = func() time.Time { return time.Date(2025, 10, 28, 15, 45, 0, 0, time.UTC) }
+
+			first, err := gen.NewID(tt.hint)
+			require.NoError(t, err)
+			second, err := gen.NewID(tt.hint)
+			require.NoError(t, err)
+
+			assert.Regexp(t, regexp.MustCompile(tt.pattern), first)
+			assert.NotEqual(t, first, second)
+		})
+	}
+}
+
+func TestIDGenerator_ULIDSortsByTime(t *testing.T) {
+	gen, err := NewIDGenerator(StrategyULID)
+	require.NoError(t, err)
+	g := gen.(*generator)
+
+	g.random = bytes.NewReader(bytes.Repeat([]byte{0xff}, 10))
+	g.clock = func() time.Time { return time.UnixMilli(1) }
+	earlier, err := gen.NewID("")
+	require.NoError(t, err)
+
+	g.random = bytes.NewReader(make([]byte, 10))
+	g.clock = func() time.Time { return time.UnixMilli(2) }
+	later, err := gen.NewID("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "0000000001ZZZZZZZZZZZZZZZZ", earlier)
+	assert.Equal(t, "00000000020000000000000000", later)
+}
+
+func TestNewIDGenerator_UnknownStrategy(t *testing.T) {
+	_, err := NewIDGenerator("serial")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be one of: uuid, ulid, nanoid, date_slug")
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "api-style-v2", Slugify("  API Style (v2)!"))
+	assert.Equal(t, "caf-menu", Slugify("Café menu"))
+	assert.Equal(t, "", Slugify("---"))
+	assert.LessOrEqual(t, len(Slugify(string(bytes.Repeat([]byte("ab "), 40)))), maxSlugLength)
+}
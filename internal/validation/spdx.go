@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// spdxLicenses holds the SPDX license identifiers accepted in license expressions, keyed by lowercase ID
+var spdxLicenses = indexIdentifiers(
+	"0BSD", "AFL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0",
+	"Artistic-2.0", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause", "BSD-2-Clause-Patent",
+	"BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "CC-BY-3.0", "CC-BY-4.0",
+	"CC-BY-NC-4.0", "CC-BY-NC-SA-4.0", "CC-BY-ND-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0", "CC0-1.0",
+	"CDDL-1.0", "CDDL-1.1", "CECILL-2.1", "ECL-2.0", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2",
+	"GFDL-1.3-only", "GFDL-1.3-or-later", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only",
+	"GPL-3.0-or-later", "ISC", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0-only",
+	"LGPL-3.0-or-later", "LPPL-1.3c", "MIT", "MIT-0", "MPL-1.1", "MPL-2.0",
+	"MPL-2.0-no-copyleft-exception", "MS-PL", "MS-RL", "MulanPSL-2.0", "NCSA", "ODbL-1.0",
+	"OFL-1.1", "OSL-3.0", "PostgreSQL", "Python-2.0", "Unicode-3.0", "Unicode-DFS-2016",
+	"Unlicense", "UPL-1.0", "Vim", "W3C", "WTFPL", "X11", "Zlib", "ZPL-2.1",
+)
+
+// spdxExceptions holds the SPDX license exception identifiers accepted after WITH
+var spdxExceptions = indexIdentifiers(
+	"Autoconf-exception-3.0", "Bison-exception-2.2", "Classpath-exception-2.0",
+	"GCC-exception-3.1", "LLVM-exception", "OpenJDK-assembly-exception-1.0", "Qt-GPL-exception-1.0",
+)
+
+// licenseRefRegex matches user-defined license references
+var licenseRefRegex = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.\-]+:)?LicenseRef-[A-Za-z0-9.\-]+$`)
+
+// indexIdentifiers maps lowercase identifiers to their canonical spelling
+func indexIdentifiers(ids ...string) map[string]string {
+	index := make(map[string]string, len(ids))
+	for _, id := range ids {
+		index[strings.ToLower(id)] = id
+	}
+	return index
+}
+
+// ValidateLicense validates that license is an SPDX license expression
+// such as "MIT", "Apache-2.0 OR MIT" or "GPL-2.0-only WITH Classpath-exception-2.0"
+func ValidateLicense(license string) error {
+	_, err := LicenseIdentifiers(license)
+	return err
+}
+
+// LicenseIdentifiers parses an SPDX license expression and returns the license identifiers it references
+func LicenseIdentifiers(expression string) ([]string, error) {
+	tokens := tokenizeLicense(expression)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("license expression cannot be empty")
+	}
+
+	p := &licenseParser{tokens: tokens}
+	if err := p.expression(); err != nil {
+		return nil, fmt.Errorf("invalid SPDX license expression '%s': %w", expression, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid SPDX license expression '%s': unexpected '%s'", expression, p.tokens[p.pos])
+	}
+	return p.ids, nil
+}
+
+// tokenizeLicense splits a license expression into identifiers, operators and parentheses
+func tokenizeLicense(expression string) []string {
+	expression = strings.ReplaceAll(expression, "(", " ( ")
+	expression = strings.ReplaceAll(expression, ")", " ) ")
+	return strings.Fields(expression)
+}
+
+// licenseParser is a recursive descent parser for SPDX license expressions
+type licenseParser struct {
+	tokens []string
+	pos    int
+	ids    []string
+}
+
+// expression parses: term (("AND" | "OR") term)*
+func (p *licenseParser) expression() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	for p.pos < len(p.tokens) && (p.tokens[p.pos] == "AND" || p.tokens[p.pos] == "OR") {
+		p.pos++
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// term parses: "(" expression ")" | license ["WITH" exception]
+func (p *licenseParser) term() error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("unexpected end of expression")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	if token == "(" {
+		if err := p.expression(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return nil
+	}
+
+	id := strings.TrimSuffix(token, "+")
+	if _, ok := spdxLicenses[strings.ToLower(id)]; !ok && !licenseRefRegex.MatchString(id) {
+		return fmt.Errorf("unknown license identifier '%s'", token)
+	}
+	p.ids = append(p.ids, id)
+
+	if p.pos < len(p.tokens) && p.tokens[p.pos] == "WITH" {
+		p.pos++
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("missing exception after WITH")
+		}
+		if _, ok := spdxExceptions[strings.ToLower(p.tokens[p.pos])]; !ok {
+			return fmt.Errorf("unknown license exception '%s'", p.tokens[p.pos])
+		}
+		p.pos++
+	}
+	return nil
+}
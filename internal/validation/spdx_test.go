@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLicense(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{name: "single identifier", input: "MIT"},
+		{name: "case insensitive identifier", input: "apache-2.0"},
+		{name: "or-later suffix", input: "MPL-1.1+"},
+		{name: "disjunction", input: "Apache-2.0 OR MIT"},
+		{name: "exception", input: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{name: "nested", input: "(MIT OR Apache-2.0) AND CC-BY-4.0"},
+		{name: "license ref", input: "LicenseRef-Acme-Internal"},
+		{name: "empty", input: "", wantError: true},
+		{name: "unknown identifier", input: "Proprietary", wantError: true},
+		{name: "dangling operator", input: "MIT OR", wantError: true},
+		{name: "lowercase operator", input: "MIT or Apache-2.0", wantError: true},
+		{name: "unknown exception", input: "MIT WITH Nothing", wantError: true},
+		{name: "unbalanced parenthesis", input: "(MIT OR Apache-2.0", wantError: true},
+		{name: "trailing parenthesis", input: "MIT)", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLicense(tt.input)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLicenseIdentifiers(t *testing.T) {
+	ids, err := LicenseIdentifiers("(MIT OR Apache-2.0+) AND LicenseRef-Acme WITH LLVM-exception")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MIT", "Apache-2.0", "LicenseRef-Acme"}, ids)
+}
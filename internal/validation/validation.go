@@ -4,25 +4,49 @@ package validation
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
 )
 
+// NamePattern is the snake_case pattern entity names must match
+const NamePattern = `^[a-z][a-z0-9]*(_[a-z0-9]+)*$`
+
 // snakeCaseRegex matches valid snake_case identifiers
-var snakeCaseRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+var snakeCaseRegex = regexp.MustCompile(NamePattern)
 
 // ValidateRulesetName validates that a ruleset name follows snake_case convention
 func ValidateRulesetName(name string) error {
+	return ValidateName("ruleset", name)
+}
+
+// ValidateName validates that the name of an entity of the given kind follows snake_case convention
+func ValidateName(kind, name string) error {
 	if name == "" {
-		return fmt.Errorf("ruleset name cannot be empty")
+		return fmt.Errorf("%s name cannot be empty", kind)
 	}
 
 	if !snakeCaseRegex.MatchString(name) {
-		return fmt.Errorf("ruleset name must be in snake_case format (lowercase letters, numbers, and underscores only, starting with a letter): %s", name)
+		return fmt.Errorf("%s name must be in snake_case format (lowercase letters, numbers, and underscores only, starting with a letter): %s", kind, name)
 	}
 
 	return nil
 }
 
+// PrincipalPattern is the pattern owner and team identifiers must match, e.g.
+// "alice", "alice@example.com" or "platform-team"
+const PrincipalPattern = `^[A-Za-z0-9][A-Za-z0-9._@+-]{0,127}$`
+
+// principalRegex matches valid owner and team identifiers
+var principalRegex = regexp.MustCompile(PrincipalPattern)
+
+// ValidatePrincipal validates an owner or team identifier; kind names it in errors
+func ValidatePrincipal(kind, value string) error {
+	if !principalRegex.MatchString(value) {
+		return fmt.Errorf("%s must be 1-128 characters of letters, digits and . _ @ + - starting with a letter or digit: %s", kind, value)
+	}
+	return nil
+}
+
 // FormatTimestamp converts a time.Time to RFC3339 format string
 func FormatTimestamp(t time.Time) string {
 	return t.Format(time.RFC3339)
@@ -36,3 +60,27 @@ func ParseTimestamp(s string) (time.Time, error) {
 	}
 	return t, nil
 }
+
+// relativeDateRegex matches offsets into the past such as -7d, -12h or -2w
+var relativeDateRegex = regexp.MustCompile(`^-(\d+)([hdw])$`)
+
+// ParseDate parses a YYYY-MM-DD date (midnight UTC), an RFC3339 timestamp, or
+// an offset into the past relative to now such as -7d, -12h or -2w
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	if m := relativeDateRegex.FindStringSubmatch(value); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative date '%s': %w", value, err)
+		}
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s' (expected YYYY-MM-DD, RFC3339 or a relative offset like -7d)", value)
+	}
+	return t, nil
+}
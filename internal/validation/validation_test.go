@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -232,3 +233,60 @@ func TestFormatAndParseTimestamp_RoundTrip(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, original.Equal(parsed), "round trip failed: expected %v, got %v", original, parsed)
 }
+
+func TestValidateName(t *testing.T) {
+	err := ValidateName("prompt template", "review_prompt")
+	assert.NoError(t, err)
+
+	err = ValidateName("prompt template", "")
+	require.Error(t, err)
+	assert.Equal(t, "prompt template name cannot be empty", err.Error())
+
+	err = ValidateName("snippet", "Bad-Name")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "snippet name must be in snake_case format")
+}
+
+func TestValidatePrincipal(t *testing.T) {
+	for _, valid := range []string{"alice", "alice@example.com", "platform-team", "j.doe+rules"} {
+		assert.NoError(t, ValidatePrincipal("owner", valid), valid)
+	}
+
+	for _, invalid := range []string{"", "-alice", "alice smith", strings.Repeat("a", 129)} {
+		err := ValidatePrincipal("team", invalid)
+		require.Error(t, err, invalid)
+		assert.Contains(t, err.Error(), "team must be 1-128 characters")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2024-01-15", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), false},
+		{"2024-01-15T10:30:00Z", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), false},
+		{"2024-01-15T10:30:00+02:00", time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC), false},
+		{"-12h", time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), false},
+		{"-7d", time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC), false},
+		{"-2w", time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), false},
+		{"-3m", time.Time{}, true},
+		{"7d", time.Time{}, true},
+		{"15/01/2024", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDate(tt.input, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %v", got)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	glide "github.com/valkey-io/valkey-glide/go/v2"
 	"github.com/valkey-io/valkey-glide/go/v2/config"
@@ -16,22 +17,56 @@ type Client struct {
 	ctx         context.Context
 }
 
-// NewClient creates a new Valkey client and establishes a connection
-func NewClient(host, port string) (*Client, error) {
+// Option configures the connection of a Client
+type Option func(*clientOptions)
+
+// clientOptions holds the connection settings passed to glide
+type clientOptions struct {
+	clientName     string
+	requestTimeout time.Duration
+	database       int
+}
+
+// WithClientName sets the name the connection reports in CLIENT LIST
+func WithClientName(name string) Option {
+	return func(o *clientOptions) {
+		o.clientName = name
+	}
+}
+
+// WithRequestTimeout bounds how long a command may take; zero keeps glide's default
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.requestTimeout = timeout
+	}
+}
+
+// WithDatabase selects the logical database used by the connection
+func WithDatabase(database int) Option {
+	return func(o *clientOptions) {
+		o.database = database
+	}
+}
+
+// clientConfiguration builds the glide configuration for a connection to host and port
+func clientConfiguration(host, port string, opts []Option) (*config.ClientConfiguration, *clientOptions, error) {
 	if host == "" {
-		return nil, fmt.Errorf("host cannot be empty")
+		return nil, nil, fmt.Errorf("host cannot be empty")
 	}
 	if port == "" {
-		return nil, fmt.Errorf("port cannot be empty")
+		return nil, nil, fmt.Errorf("port cannot be empty")
 	}
 
 	// Convert port string to int
 	portNum, err := strconv.Atoi(port)
 	if err != nil {
-		return nil, fmt.Errorf("invalid port number: %w", err)
+		return nil, nil, fmt.Errorf("invalid port number: %w", err)
 	}
 
-	ctx := context.Background()
+	o := &clientOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 
 	// Configure the Valkey client
 	clientConfig := config.NewClientConfiguration().
@@ -39,6 +74,26 @@ func NewClient(host, port string) (*Client, error) {
 			Host: host,
 			Port: portNum,
 		})
+	if o.clientName != "" {
+		clientConfig.WithClientName(o.clientName)
+	}
+	if o.requestTimeout > 0 {
+		clientConfig.WithRequestTimeout(o.requestTimeout)
+	}
+	if o.database > 0 {
+		clientConfig.WithDatabaseId(o.database)
+	}
+	return clientConfig, o, nil
+}
+
+// NewClient creates a new Valkey client and establishes a connection
+func NewClient(host, port string, opts ...Option) (*Client, error) {
+	clientConfig, _, err := clientConfiguration(host, port, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
 
 	// Create and connect the client
 	glideClient, err := glide.NewClient(clientConfig)
@@ -2,9 +2,13 @@ package valkey
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glide "github.com/valkey-io/valkey-glide/go/v2"
 )
 
 func TestNewClient_Validation(t *testing.T) {
@@ -145,6 +149,46 @@ func TestClient_MethodsWithNilClient(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "client is not initialized")
 	})
+
+	t.Run("Commands", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := client.Exists(ctx, "key")
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		_, err = client.HGetAll(ctx, "key")
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		err = client.HSet(ctx, "key", map[string]string{"field": "value"})
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		err = client.Del(ctx, "key")
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		_, err = client.ScanKeys(ctx, "*")
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		_, err = client.UpsertHash(ctx, "key", map[string]string{"field": "value"}, nil)
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		_, err = client.DelIfField(ctx, "key", "field", "value")
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		err = client.ZAdd(ctx, "key", "member", 1)
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		err = client.ZRem(ctx, "key", "member")
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		_, err = client.ZRangeByScore(ctx, "key", 0, 1)
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		_, err = client.AcquireLease(ctx, "key", "owner", time.Second)
+		assert.ErrorIs(t, err, errNotInitialized)
+
+		err = client.ReleaseLease(ctx, "key", "owner")
+		assert.ErrorIs(t, err, errNotInitialized)
+	})
 }
 
 // Test NewClient with various invalid inputs
@@ -190,3 +234,48 @@ func TestNewClient_InvalidInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"connection", glide.NewConnectionError("connection refused"), true},
+		{"timeout", glide.NewTimeoutError("timed out"), true},
+		{"disconnect", fmt.Errorf("failed to update ruleset: %w", glide.NewDisconnectError("broken pipe")), true},
+		{"closed client", glide.NewClosingError("client closed"), false},
+		{"command error", fmt.Errorf("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsUnavailable(tt.err))
+		})
+	}
+}
+
+func TestClientConfiguration_Options(t *testing.T) {
+	clientConfig, _, err := clientConfiguration("localhost", "6379", []Option{
+		WithClientName("archivyr"),
+		WithRequestTimeout(2 * time.Second),
+		WithDatabase(3),
+	})
+	require.NoError(t, err)
+
+	request, err := clientConfig.ToProtobuf()
+	require.NoError(t, err)
+	assert.Equal(t, "archivyr", request.ClientName)
+	assert.Equal(t, uint32(2000), request.RequestTimeout)
+	assert.Equal(t, uint32(3), request.DatabaseId)
+
+	// Without options glide's defaults apply
+	clientConfig, _, err = clientConfiguration("localhost", "6379", nil)
+	require.NoError(t, err)
+	request, err = clientConfig.ToProtobuf()
+	require.NoError(t, err)
+	assert.Empty(t, request.ClientName)
+	assert.Zero(t, request.RequestTimeout)
+	assert.Zero(t, request.DatabaseId)
+}
@@ -0,0 +1,235 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/valkey-io/valkey-glide/go/v2/constants"
+	"github.com/valkey-io/valkey-glide/go/v2/models"
+	"github.com/valkey-io/valkey-glide/go/v2/options"
+)
+
+// errNotInitialized is returned by commands invoked on a client without a connection
+var errNotInitialized = fmt.Errorf("client is not initialized")
+
+// Exists reports whether the key exists
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	if c.glideClient == nil {
+		return false, errNotInitialized
+	}
+
+	count, err := c.glideClient.Exists(ctx, []string{key})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// HGetAll returns all fields of the hash stored at key, or an empty map if it does not exist
+func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if c.glideClient == nil {
+		return nil, errNotInitialized
+	}
+
+	return c.glideClient.HGetAll(ctx, key)
+}
+
+// HSet sets the given fields on the hash stored at key
+func (c *Client) HSet(ctx context.Context, key string, fields map[string]string) error {
+	if c.glideClient == nil {
+		return errNotInitialized
+	}
+
+	_, err := c.glideClient.HSet(ctx, key, fields)
+	return err
+}
+
+// Del removes the given keys
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	if c.glideClient == nil {
+		return errNotInitialized
+	}
+
+	_, err := c.glideClient.Del(ctx, keys)
+	return err
+}
+
+// ScanKeys iterates the keyspace with SCAN and returns every key matching the glob pattern
+func (c *Client) ScanKeys(ctx context.Context, match string) ([]string, error) {
+	if c.glideClient == nil {
+		return nil, errNotInitialized
+	}
+
+	keys := make([]string, 0)
+	cursor := models.NewCursor()
+	scanOptions := options.NewScanOptions().SetMatch(match)
+
+	for {
+		result, err := c.glideClient.ScanWithOptions(ctx, cursor, *scanOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, result.Data...)
+
+		cursor = result.Cursor
+		if cursor.IsFinished() {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
+// upsertHashSource implements UpsertHash server-side so the existence check and
+// the write cannot interleave with other clients.
+// ARGV: create field count (-1 for none), update field count (-1 for none), then
+// the create pairs followed by the update pairs.
+const upsertHashSource = `
+local ncreate = tonumber(ARGV[1])
+local nupdate = tonumber(ARGV[2])
+local offset = 3
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  if ncreate > 0 then
+    redis.call('HSET', KEYS[1], unpack(ARGV, offset, offset + ncreate * 2 - 1))
+  end
+  return 0
+end
+if ncreate > 0 then
+  offset = offset + ncreate * 2
+end
+if nupdate > 0 then
+  redis.call('HSET', KEYS[1], unpack(ARGV, offset, offset + nupdate * 2 - 1))
+end
+return 1
+`
+
+var (
+	upsertHashOnce   sync.Once
+	upsertHashScript *options.Script
+)
+
+// UpsertHash atomically sets create on a missing key or update on an existing one
+// and reports whether the key existed. A nil map leaves the key untouched in that case.
+func (c *Client) UpsertHash(ctx context.Context, key string, create, update map[string]string) (bool, error) {
+	if c.glideClient == nil {
+		return false, errNotInitialized
+	}
+
+	upsertHashOnce.Do(func() {
+		upsertHashScript = options.NewScript(upsertHashSource)
+	})
+
+	args := []string{fieldCount(create), fieldCount(update)}
+	args = appendFields(args, create)
+	args = appendFields(args, update)
+
+	result, err := c.glideClient.InvokeScriptWithOptions(ctx, *upsertHashScript,
+		*options.NewScriptOptions().WithKeys([]string{key}).WithArgs(args))
+	if err != nil {
+		return false, err
+	}
+
+	existed, ok := result.(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected script result %v", result)
+	}
+	return existed == 1, nil
+}
+
+// delIfFieldSource deletes KEYS[1] only while its hash field ARGV[1] holds
+// ARGV[2], so the check cannot interleave with other clients' writes
+const delIfFieldSource = `
+if redis.call('HGET', KEYS[1], ARGV[1]) == ARGV[2] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+var (
+	delIfFieldOnce   sync.Once
+	delIfFieldScript *options.Script
+)
+
+// DelIfField atomically deletes key only while its hash field holds value
+// and reports whether it was deleted
+func (c *Client) DelIfField(ctx context.Context, key, field, value string) (bool, error) {
+	if c.glideClient == nil {
+		return false, errNotInitialized
+	}
+
+	delIfFieldOnce.Do(func() {
+		delIfFieldScript = options.NewScript(delIfFieldSource)
+	})
+
+	result, err := c.glideClient.InvokeScriptWithOptions(ctx, *delIfFieldScript,
+		*options.NewScriptOptions().WithKeys([]string{key}).WithArgs([]string{field, value}))
+	if err != nil {
+		return false, err
+	}
+
+	deleted, ok := result.(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected script result %v", result)
+	}
+	return deleted == 1, nil
+}
+
+// fieldCount encodes the number of fields for the upsert script, -1 for a nil map
+func fieldCount(fields map[string]string) string {
+	if fields == nil {
+		return "-1"
+	}
+	return strconv.Itoa(len(fields))
+}
+
+// appendFields appends field/value pairs to args
+func appendFields(args []string, fields map[string]string) []string {
+	for field, value := range fields {
+		args = append(args, field, value)
+	}
+	return args
+}
+
+// ZAdd adds member to the sorted set at key, or updates its score
+func (c *Client) ZAdd(ctx context.Context, key, member string, score float64) error {
+	if c.glideClient == nil {
+		return errNotInitialized
+	}
+
+	_, err := c.glideClient.ZAdd(ctx, key, map[string]float64{member: score})
+	return err
+}
+
+// ZRem removes members from the sorted set at key
+func (c *Client) ZRem(ctx context.Context, key string, members ...string) error {
+	if c.glideClient == nil {
+		return errNotInitialized
+	}
+
+	_, err := c.glideClient.ZRem(ctx, key, members)
+	return err
+}
+
+// ZRangeByScore returns the members of the sorted set at key with min <= score <= max
+// in ascending score order. Infinite bounds are unbounded.
+func (c *Client) ZRangeByScore(ctx context.Context, key string, minScore, maxScore float64) ([]string, error) {
+	if c.glideClient == nil {
+		return nil, errNotInitialized
+	}
+
+	// Infinite bounds map to open ends, everything else is inclusive
+	start := options.NewInclusiveScoreBoundary(minScore)
+	if math.IsInf(minScore, -1) {
+		start = options.NewInfiniteScoreBoundary(constants.NegativeInfinity)
+	}
+	end := options.NewInclusiveScoreBoundary(maxScore)
+	if math.IsInf(maxScore, 1) {
+		end = options.NewInfiniteScoreBoundary(constants.PositiveInfinity)
+	}
+
+	return c.glideClient.ZRange(ctx, key, options.NewRangeByScoreQuery(start, end))
+}
@@ -0,0 +1,17 @@
+package valkey
+
+import (
+	"errors"
+
+	glide "github.com/valkey-io/valkey-glide/go/v2"
+)
+
+// IsUnavailable reports whether err means Valkey could not be reached, as
+// opposed to a command that reached the server and failed. Such errors are
+// usually transient, so the operation can be retried later.
+func IsUnavailable(err error) bool {
+	var connErr *glide.ConnectionError
+	var timeoutErr *glide.TimeoutError
+	var disconnectErr *glide.DisconnectError
+	return errors.As(err, &connErr) || errors.As(err, &timeoutErr) || errors.As(err, &disconnectErr)
+}
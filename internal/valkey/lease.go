@@ -0,0 +1,83 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valkey-io/valkey-glide/go/v2/options"
+)
+
+// acquireLeaseSource takes the lease at KEYS[1] for owner ARGV[1] when it is
+// free, or extends it when owner already holds it, for ARGV[2] milliseconds.
+// It returns 1 when owner holds the lease afterwards.
+const acquireLeaseSource = `
+local holder = redis.call('GET', KEYS[1])
+if holder == ARGV[1] then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  return 1
+end
+if holder then
+  return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`
+
+// releaseLeaseSource deletes the lease at KEYS[1] only if owner ARGV[1] holds it
+const releaseLeaseSource = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+var (
+	leaseScriptsOnce   sync.Once
+	acquireLeaseScript *options.Script
+	releaseLeaseScript *options.Script
+)
+
+// leaseScripts loads the lease scripts on first use
+func leaseScripts() {
+	leaseScriptsOnce.Do(func() {
+		acquireLeaseScript = options.NewScript(acquireLeaseSource)
+		releaseLeaseScript = options.NewScript(releaseLeaseSource)
+	})
+}
+
+// AcquireLease takes the lease at key for owner, or extends it when owner
+// already holds it, so that it expires after ttl unless renewed. It reports
+// whether owner holds the lease.
+func (c *Client) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if c.glideClient == nil {
+		return false, errNotInitialized
+	}
+	leaseScripts()
+
+	result, err := c.glideClient.InvokeScriptWithOptions(ctx, *acquireLeaseScript,
+		*options.NewScriptOptions().WithKeys([]string{key}).WithArgs([]string{owner, strconv.FormatInt(ttl.Milliseconds(), 10)}))
+	if err != nil {
+		return false, err
+	}
+
+	held, ok := result.(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected script result %v", result)
+	}
+	return held == 1, nil
+}
+
+// ReleaseLease gives up the lease at key if owner holds it
+func (c *Client) ReleaseLease(ctx context.Context, key, owner string) error {
+	if c.glideClient == nil {
+		return errNotInitialized
+	}
+	leaseScripts()
+
+	_, err := c.glideClient.InvokeScriptWithOptions(ctx, *releaseLeaseScript,
+		*options.NewScriptOptions().WithKeys([]string{key}).WithArgs([]string{owner}))
+	return err
+}
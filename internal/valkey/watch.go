@@ -0,0 +1,101 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	glide "github.com/valkey-io/valkey-glide/go/v2"
+	"github.com/valkey-io/valkey-glide/go/v2/config"
+	"github.com/valkey-io/valkey-glide/go/v2/models"
+)
+
+// patternEscaper escapes glob metacharacters so a key prefix matches literally
+var patternEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// KeyWatcher reports keys modified by any client, so local caches can drop
+// entries that other server instances changed
+type KeyWatcher struct {
+	glideClient *glide.Client
+}
+
+// WatchKeys subscribes to keyspace notifications for keys starting with prefix
+// on a dedicated connection and calls onChange with every modified key,
+// including those modified through this process. The server must publish
+// notifications for hash and generic commands (notify-keyspace-events
+// containing K, h and g, or K and A); WatchKeys fails when it reports otherwise.
+func WatchKeys(host, port, prefix string, onChange func(key string), opts ...Option) (*KeyWatcher, error) {
+	clientConfig, o, err := clientConfiguration(host, port, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	channelPrefix := fmt.Sprintf("__keyspace@%d__:", o.database)
+	subscription := config.NewStandaloneSubscriptionConfig().
+		WithSubscription(config.PatternChannelMode, channelPrefix+patternEscaper.Replace(prefix)+"*").
+		WithCallback(func(msg *models.PubSubMessage, _ any) {
+			if key, ok := strings.CutPrefix(msg.Channel, channelPrefix); ok {
+				onChange(key)
+			}
+		}, nil)
+
+	glideClient, err := glide.NewClient(clientConfig.WithSubscriptionConfig(subscription))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Valkey client for keyspace notifications: %w", err)
+	}
+
+	w := &KeyWatcher{glideClient: glideClient}
+	if err := w.checkNotifications(); err != nil {
+		w.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// checkNotifications fails when the server does not publish the keyspace
+// notifications WatchKeys relies on. Servers that refuse CONFIG GET, as some
+// managed services do, are assumed to be configured.
+func (w *KeyWatcher) checkNotifications() error {
+	result, err := w.glideClient.CustomCommand(context.Background(), []string{"CONFIG", "GET", "notify-keyspace-events"})
+	if err != nil {
+		return nil
+	}
+	flags, ok := notifyFlags(result)
+	if !ok {
+		return nil
+	}
+	if !notificationsEnabled(flags) {
+		return fmt.Errorf("keyspace notifications are disabled (notify-keyspace-events is '%s'); set it to include K, h and g, e.g. CONFIG SET notify-keyspace-events Khg", flags)
+	}
+	return nil
+}
+
+// notifyFlags extracts the notify-keyspace-events value from a CONFIG GET
+// reply, which is a map under RESP3 and a flat array under RESP2
+func notifyFlags(result any) (string, bool) {
+	switch reply := result.(type) {
+	case map[string]any:
+		flags, ok := reply["notify-keyspace-events"].(string)
+		return flags, ok
+	case []any:
+		if len(reply) == 2 {
+			flags, ok := reply[1].(string)
+			return flags, ok
+		}
+	}
+	return "", false
+}
+
+// notificationsEnabled reports whether flags enable keyspace notifications
+// for hash and generic commands
+func notificationsEnabled(flags string) bool {
+	if !strings.Contains(flags, "K") {
+		return false
+	}
+	return strings.Contains(flags, "A") || (strings.Contains(flags, "h") && strings.Contains(flags, "g"))
+}
+
+// Close ends the subscription
+func (w *KeyWatcher) Close() {
+	w.glideClient.Close()
+}
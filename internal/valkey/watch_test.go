@@ -0,0 +1,38 @@
+package valkey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  any
+		flags   string
+		ok      bool
+		enabled bool
+	}{
+		{"RESP3 map", map[string]any{"notify-keyspace-events": "Khg"}, "Khg", true, true},
+		{"RESP2 array", []any{"notify-keyspace-events", "KA"}, "KA", true, true},
+		{"disabled", map[string]any{"notify-keyspace-events": ""}, "", true, false},
+		{"keyevent only", map[string]any{"notify-keyspace-events": "EA"}, "EA", true, false},
+		{"missing generic", map[string]any{"notify-keyspace-events": "Kh"}, "Kh", true, false},
+		{"unexpected reply", "OK", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, ok := notifyFlags(tt.result)
+			assert.Equal(t, tt.flags, flags)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.enabled, notificationsEnabled(flags))
+		})
+	}
+}
+
+func TestWatchKeys_Validation(t *testing.T) {
+	_, err := WatchKeys("", "6379", "ruleset:", func(string) {})
+	assert.ErrorContains(t, err, "host cannot be empty")
+}
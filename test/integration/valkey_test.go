@@ -524,3 +524,41 @@ func TestValkeyIntegration_DataPersistence(t *testing.T) {
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(rulesets), 2)
 }
+
+func TestValkeyIntegration_Lease(t *testing.T) {
+	container, host, port := setupValkeyContainer(t)
+	defer teardownValkeyContainer(t, container)
+
+	client, err := valkey.NewClient(host, port)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+	ctx := context.Background()
+
+	held, err := client.AcquireLease(ctx, "leader:test", "replica_a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	// Another owner cannot take a held lease, the holder can renew it
+	held, err = client.AcquireLease(ctx, "leader:test", "replica_b", time.Second)
+	require.NoError(t, err)
+	assert.False(t, held)
+	held, err = client.AcquireLease(ctx, "leader:test", "replica_a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	// Releasing by a non-holder does nothing; releasing by the holder frees it
+	require.NoError(t, client.ReleaseLease(ctx, "leader:test", "replica_b"))
+	held, err = client.AcquireLease(ctx, "leader:test", "replica_b", time.Second)
+	require.NoError(t, err)
+	assert.False(t, held)
+	require.NoError(t, client.ReleaseLease(ctx, "leader:test", "replica_a"))
+	held, err = client.AcquireLease(ctx, "leader:test", "replica_b", 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	// An expired lease can be taken over
+	time.Sleep(200 * time.Millisecond)
+	held, err = client.AcquireLease(ctx, "leader:test", "replica_a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, held)
+}